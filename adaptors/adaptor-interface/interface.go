@@ -21,18 +21,80 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"strings"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/notifications"
 	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
 )
 
 type HwMgrAdaptorIntf interface {
 	SetupAdaptor(mgr ctrl.Manager) error
 	HandleNodePool(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error)
-	HandleNodePoolDeletion(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) error
+	// HandleNodePoolDeletion releases the resources of a NodePool, returning true once the release is complete. It is
+	// called again on requeue until then, so must not block waiting for the hardware manager.
+	HandleNodePoolDeletion(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (bool, error)
+	GetCapabilities(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) (pluginv1alpha1.AdaptorCapabilities, error)
+	CheckReadiness(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) error
+}
+
+// NodePoolTimeoutHandler is optionally implemented by an adaptor to clean up the hardware manager job of a NodePool
+// whose provisioning or configuration has timed out
+type NodePoolTimeoutHandler interface {
+	HandleNodePoolTimeout(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) error
+}
+
+// NodePoolDrift describes the differences between a provisioned NodePool and its allocation in the hardware manager
+type NodePoolDrift struct {
+	// MissingNodes lists the nodes of the NodePool that are no longer allocated to it by the hardware manager
+	MissingNodes []string
+
+	// UnexpectedNodes lists the hardware manager nodes allocated to the NodePool that have no corresponding Node CR
+	UnexpectedNodes []string
+}
+
+// InSync checks whether the NodePool matches its allocation in the hardware manager
+func (d *NodePoolDrift) InSync() bool {
+	return len(d.MissingNodes) == 0 && len(d.UnexpectedNodes) == 0
+}
+
+func (d *NodePoolDrift) String() string {
+	var diffs []string
+	if len(d.MissingNodes) > 0 {
+		diffs = append(diffs, "nodes no longer allocated: "+strings.Join(d.MissingNodes, ", "))
+	}
+	if len(d.UnexpectedNodes) > 0 {
+		diffs = append(diffs, "allocated nodes without Node CR: "+strings.Join(d.UnexpectedNodes, ", "))
+	}
+	return strings.Join(diffs, "; ")
+}
+
+// NodePoolDriftHandler is optionally implemented by an adaptor to detect changes made to the allocation of a
+// provisioned NodePool outside of the plugin
+type NodePoolDriftHandler interface {
+	// CheckNodePoolDrift compares the NodePool and its Node CRs with the current allocation in the hardware manager
+	CheckNodePoolDrift(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (*NodePoolDrift, error)
+	// RemediateNodePoolDrift releases the missing nodes of the NodePool, along with any allocation left without a Node
+	// CR, so that replacements are allocated when its provisioning is restarted
+	RemediateNodePoolDrift(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool, drift *NodePoolDrift) error
+}
+
+// CapacityChecker is optionally implemented by an adaptor to report the free capacity of the hardware manager, so that
+// the feasibility of a NodePool request can be checked without allocating any nodes
+type CapacityChecker interface {
+	// GetAvailableCapacity returns the number of free nodes in each resource pool requested by the nodegroups of the
+	// NodePool, keyed by resource pool ID. The NodePool is not required to exist.
+	GetAvailableCapacity(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (map[string]int, error)
+}
+
+// NotificationHandler is optionally implemented by an adaptor that receives push notifications from its hardware
+// managers. It is called before SetupAdaptor when the notification receiver of the plugin is enabled.
+type NotificationHandler interface {
+	// SetupNotifications registers the handler for the notifications of the adaptor with the receiver
+	SetupNotifications(receiver *notifications.Receiver)
 }
 
 // Define the HwMgrAdaptor structures
@@ -20,29 +20,27 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/registry"
 	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/logging"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/notifications"
 	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
-
-	// Import the adaptors
-	dellhwmgr "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr"
-	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/loopback"
 )
 
-// Supported adaptor IDs
-const (
-	LoopbackAdaptorID  = "loopback"
-	DellHwMgrAdaptorID = "dell-hwmgr"
-)
+// hwMgrNotFoundMessage prefixes the Provisioned condition message of a NodePool whose HardwareManager is not found
+const hwMgrNotFoundMessage = "Unable to find HardwareManager instance: "
 
 // HwMgrAdaptorController
 type HwMgrAdaptorController struct {
@@ -50,16 +48,26 @@ type HwMgrAdaptorController struct {
 	Scheme    *runtime.Scheme
 	Logger    *slog.Logger
 	Namespace string
-	adaptors  map[string]adaptorinterface.HwMgrAdaptorIntf
+	Recorder  record.EventRecorder
+	// Notifications is the receiver of push notifications from the hardware managers, if enabled
+	Notifications *notifications.Receiver
+	adaptors      map[string]adaptorinterface.HwMgrAdaptorIntf
+	retries       retryTracker
+	drift         driftTracker
 }
 
 func (c *HwMgrAdaptorController) SetupWithManager(mgr ctrl.Manager) error {
-	// Setup the supported adaptors
+	// Setup the registered adaptors
 	c.adaptors = make(map[string]adaptorinterface.HwMgrAdaptorIntf)
-	c.adaptors[LoopbackAdaptorID] = loopback.NewAdaptor(c.Client, c.Scheme, c.Logger, c.Namespace)
-	c.adaptors[DellHwMgrAdaptorID] = dellhwmgr.NewAdaptor(c.Client, c.Scheme, c.Logger, c.Namespace)
+	for _, id := range registry.IDs() {
+		reg, _ := registry.Lookup(id)
+		c.adaptors[string(id)] = reg.NewAdaptor(c.Client, c.Scheme, c.Logger, c.Namespace)
+	}
 
 	for id, adaptor := range c.adaptors {
+		if handler, ok := adaptor.(adaptorinterface.NotificationHandler); ok && c.Notifications != nil {
+			handler.SetupNotifications(c.Notifications)
+		}
 		if err := adaptor.SetupAdaptor(mgr); err != nil {
 			c.Logger.Error("failed to setup adaptor", "id", id, "error", err)
 		}
@@ -80,25 +88,32 @@ func (c *HwMgrAdaptorController) getHwMgr(ctx context.Context, nodepool *hwmgmtv
 	}
 
 	// Validate that the required config data is present
-	switch hwmgr.Spec.AdaptorID {
-	case pluginv1alpha1.SupportedAdaptors.Loopback:
-		if hwmgr.Spec.LoopbackData == nil {
-			// Configuration data is not currently mandatory for the loopback adaptor, so just log it
-			c.Logger.DebugContext(ctx, "config data missing from HardwareManager", "name", hwmgr.Name)
-		}
-	case pluginv1alpha1.SupportedAdaptors.Dell:
-		if hwmgr.Spec.DellData == nil {
-			return nil, fmt.Errorf("required config data missing from HardwareManager: name=%s", hwmgr.Name)
-		}
-	default:
-		return nil, fmt.Errorf("unsupported adaptorId (%s) HardwareManager: name=%s", hwmgr.Spec.AdaptorID, hwmgr.Name)
+	if err := registry.ValidateConfig(hwmgr); err != nil {
+		return nil, fmt.Errorf("failed to validate HardwareManager CR (%s): %w", nodepool.Spec.HwMgrId, err)
 	}
 
 	return hwmgr, nil
 }
 
+// isHwMgrNotFound checks whether the NodePool failed because its HardwareManager was not found
+func isHwMgrNotFound(nodepool *hwmgmtv1alpha1.NodePool) bool {
+	condition := meta.FindStatusCondition(nodepool.Status.Conditions, string(hwmgmtv1alpha1.Provisioned))
+	return condition != nil &&
+		condition.Reason == string(hwmgmtv1alpha1.Failed) &&
+		strings.HasPrefix(condition.Message, hwMgrNotFoundMessage)
+}
+
 // HandleNodePool calls the applicable adaptor handler to process the NodePool CR
 func (c *HwMgrAdaptorController) HandleNodePool(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+	// Nodegroups assigned to different hardware managers are handed off via a child NodePool per hardware manager
+	fannedOut, children, err := c.isFannedOut(ctx, nodepool)
+	if err != nil {
+		return utils.RequeueWithShortInterval(), err
+	}
+	if fannedOut {
+		return c.handleFannedOutNodePool(ctx, nodepool, children)
+	}
+
 	ctx = logging.AppendCtx(ctx, slog.String("hwmgr", nodepool.Spec.HwMgrId))
 	hwmgr, err := c.getHwMgr(ctx, nodepool)
 	if err != nil {
@@ -106,7 +121,7 @@ func (c *HwMgrAdaptorController) HandleNodePool(ctx context.Context, nodepool *h
 
 		if err := utils.UpdateNodePoolStatusCondition(ctx, c.Client, nodepool,
 			hwmgmtv1alpha1.Provisioned, hwmgmtv1alpha1.Failed, metav1.ConditionFalse,
-			"Unable to find HardwareManager instance: "+nodepool.Spec.HwMgrId); err != nil {
+			hwMgrNotFoundMessage+nodepool.Spec.HwMgrId); err != nil {
 			return utils.RequeueWithMediumInterval(),
 				fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
 		}
@@ -131,10 +146,61 @@ func (c *HwMgrAdaptorController) HandleNodePool(ctx context.Context, nodepool *h
 		return utils.DoNotRequeue(), nil
 	}
 
+	// Resume a NodePool that failed only because its HardwareManager was not available
+	if isHwMgrNotFound(nodepool) {
+		c.Logger.InfoContext(ctx, "HardwareManager is now available")
+		if _, err := c.restartNodePool(ctx, nodepool, "HardwareManager found: "+nodepool.Spec.HwMgrId); err != nil {
+			return utils.RequeueWithShortInterval(), err
+		}
+	}
+
+	// Restart a failed operation on request
+	if err := c.checkNodePoolRetry(ctx, nodepool); err != nil {
+		return utils.RequeueWithShortInterval(), err
+	}
+
+	if isNodePoolTimedOut(nodepool) {
+		c.Logger.InfoContext(ctx, "NodePool provisioning has timed out")
+		return utils.DoNotRequeue(), nil
+	}
+
+	if err := c.restartNodePoolConfiguration(ctx, nodepool); err != nil {
+		return utils.RequeueWithShortInterval(), err
+	}
+
+	// Reject spec changes that the adaptor is not capable of handling
+	rejected, err := c.checkNodePoolSpecChange(ctx, adaptor, hwmgr, nodepool)
+	if err != nil {
+		return utils.RequeueWithMediumInterval(), err
+	}
+	if rejected {
+		return utils.DoNotRequeue(), nil
+	}
+
+	timedOut, remaining, err := c.checkNodePoolTimeout(ctx, adaptor, hwmgr, nodepool)
+	if err != nil {
+		return utils.RequeueWithShortInterval(), err
+	}
+	if timedOut {
+		return utils.DoNotRequeue(), nil
+	}
+
 	result, err := adaptor.HandleNodePool(ctx, hwmgr, nodepool)
+	if utils.IsTransientError(err) {
+		return c.handleTransientFailure(ctx, nodepool, err)
+	}
 	if err != nil {
 		return result, fmt.Errorf("failed HandleNodePool for adaptorID %s: %w", adaptorID, err)
 	}
+	c.retries.reset(nodepool.UID)
+	result = requeueBeforeTimeout(result, remaining)
+
+	// Compare a provisioned NodePool with its allocation in the hardware manager, requeueing for the next check
+	driftInterval, err := c.checkNodePoolDrift(ctx, adaptor, hwmgr, nodepool)
+	if err != nil {
+		c.Logger.InfoContext(ctx, "NodePool drift check failed", slog.String("error", err.Error()))
+	}
+	result = requeueBeforeTimeout(result, driftInterval)
 
 	if !controllerutil.ContainsFinalizer(nodepool, utils.NodepoolFinalizer) {
 		c.Logger.InfoContext(ctx, "Adding finalizer to NodePool")
@@ -146,11 +212,25 @@ func (c *HwMgrAdaptorController) HandleNodePool(ctx context.Context, nodepool *h
 	return result, nil
 }
 
-// HandleNodePool calls the applicable adaptor handler to process the NodePool CR deletion
-func (c *HwMgrAdaptorController) HandleNodePoolDeletion(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) error {
+// HandleNodePoolDeletion calls the applicable adaptor handler to process the NodePool CR deletion, returning true
+// once the resources of the NodePool have been released
+func (c *HwMgrAdaptorController) HandleNodePoolDeletion(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) (bool, error) {
+	// The nodegroups of a fanned out NodePool are released by deleting the child NodePools
+	children, err := c.getChildNodePools(ctx, nodepool)
+	if err != nil {
+		return false, err
+	}
+	if len(children) > 0 {
+		return false, c.handleFannedOutNodePoolDeletion(ctx, children)
+	}
+	if hasNodeGroupHwMgrOverride(nodepool) {
+		// The NodePool was not handed off to any adaptor, or its children are gone, so there is nothing to release
+		return true, nil
+	}
+
 	hwmgr, err := c.getHwMgr(ctx, nodepool)
 	if err != nil {
-		return fmt.Errorf("failed to get HardwareManager CR (%s): %w", nodepool.Spec.HwMgrId, err)
+		return false, fmt.Errorf("failed to get HardwareManager CR (%s): %w", nodepool.Spec.HwMgrId, err)
 	}
 
 	adaptorID := string(hwmgr.Spec.AdaptorID)
@@ -159,11 +239,38 @@ func (c *HwMgrAdaptorController) HandleNodePoolDeletion(ctx context.Context, nod
 	adaptor, exists := c.adaptors[adaptorID]
 	if !exists {
 		c.Logger.Error("unsupported adaptor ID", "adaptorID", adaptorID)
-		return nil
+		return true, nil
+	}
+
+	released, err := adaptor.HandleNodePoolDeletion(ctx, hwmgr, nodepool)
+	if err != nil {
+		return false, fmt.Errorf("failed HandleNodePoolDeletion for adaptorID %s: %w", adaptorID, err)
+	}
+
+	if released {
+		// Drop the in-memory tracking of the NodePool
+		c.retries.reset(nodepool.UID)
+		c.drift.reset(nodepool.UID)
+	}
+
+	return released, nil
+}
+
+// CheckReadiness calls the applicable adaptor to verify that the hardware manager is usable
+func (c *HwMgrAdaptorController) CheckReadiness(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) error {
+	if err := registry.ValidateConfig(hwmgr); err != nil {
+		return err // nolint: wrapcheck
+	}
+
+	adaptorID := string(hwmgr.Spec.AdaptorID)
+
+	adaptor, exists := c.adaptors[adaptorID]
+	if !exists {
+		return fmt.Errorf("unsupported adaptor ID specified: %s", adaptorID)
 	}
 
-	if err := adaptor.HandleNodePoolDeletion(ctx, hwmgr, nodepool); err != nil {
-		return fmt.Errorf("failed HandleNodePoolDeletion for adaptorID %s: %w", adaptorID, err)
+	if err := adaptor.CheckReadiness(ctx, hwmgr); err != nil {
+		return fmt.Errorf("failed CheckReadiness for adaptorID %s: %w", adaptorID, err)
 	}
 
 	return nil
@@ -0,0 +1,125 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+// isNodePoolSpecChanged checks whether a provisioned NodePool has a spec change that has not yet been handled
+func isNodePoolSpecChanged(nodepool *hwmgmtv1alpha1.NodePool) bool {
+	provisionedCondition := meta.FindStatusCondition(nodepool.Status.Conditions, string(hwmgmtv1alpha1.Provisioned))
+	return provisionedCondition != nil &&
+		provisionedCondition.Status == metav1.ConditionTrue &&
+		nodepool.Generation != nodepool.Status.HwMgrPlugin.ObservedGeneration
+}
+
+// findUnsupportedChanges compares the NodePool spec against its allocated nodes, returning a description of each
+// change that requires a capability the adaptor does not have
+func findUnsupportedChanges(
+	capabilities pluginv1alpha1.AdaptorCapabilities,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	nodelist *hwmgmtv1alpha1.NodeList) []string {
+
+	var unsupported []string
+
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		count := 0
+		profileChanged := false
+		for _, node := range nodelist.Items {
+			if node.Spec.GroupName != nodegroup.NodePoolData.Name {
+				continue
+			}
+			count++
+			if node.Spec.HwProfile != nodegroup.NodePoolData.HwProfile {
+				profileChanged = true
+			}
+		}
+
+		if count < nodegroup.Size && !capabilities.ScaleOut {
+			unsupported = append(unsupported,
+				fmt.Sprintf("scale-out of nodegroup %s from %d to %d", nodegroup.NodePoolData.Name, count, nodegroup.Size))
+		}
+
+		if count > nodegroup.Size && !capabilities.ScaleIn {
+			unsupported = append(unsupported,
+				fmt.Sprintf("scale-in of nodegroup %s from %d to %d", nodegroup.NodePoolData.Name, count, nodegroup.Size))
+		}
+
+		if profileChanged && !capabilities.ProfileUpdate {
+			unsupported = append(unsupported,
+				fmt.Sprintf("hardware profile update of nodegroup %s to %s", nodegroup.NodePoolData.Name, nodegroup.NodePoolData.HwProfile))
+		}
+	}
+
+	return unsupported
+}
+
+// checkNodePoolSpecChange verifies that the adaptor is capable of handling any pending spec change of a provisioned
+// NodePool. An unsupported change is rejected with a Failed condition, and marked as handled so that the adaptor
+// does not attempt to process it. Returns true if the change was rejected.
+func (c *HwMgrAdaptorController) checkNodePoolSpecChange(
+	ctx context.Context,
+	adaptor adaptorinterface.HwMgrAdaptorIntf,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (bool, error) {
+
+	if !isNodePoolSpecChanged(nodepool) {
+		return false, nil
+	}
+
+	capabilities, err := adaptor.GetCapabilities(ctx, hwmgr)
+	if err != nil {
+		return false, fmt.Errorf("failed to get capabilities for adaptorID %s: %w", hwmgr.Spec.AdaptorID, err)
+	}
+
+	nodelist, err := utils.GetChildNodes(ctx, c.Logger, c.Client, nodepool)
+	if err != nil {
+		return false, fmt.Errorf("failed to get child nodes for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	unsupported := findUnsupportedChanges(capabilities, nodepool, nodelist)
+	if len(unsupported) == 0 {
+		return false, nil
+	}
+
+	message := fmt.Sprintf("Unsupported NodePool spec change for adaptor %s: %s",
+		hwmgr.Spec.AdaptorID, strings.Join(unsupported, ", "))
+	c.Logger.InfoContext(ctx, "Rejecting NodePool spec change", slog.String("reason", message))
+
+	if err := utils.UpdateNodePoolStatusCondition(ctx, c.Client, nodepool,
+		hwmgmtv1alpha1.Configured, hwmgmtv1alpha1.Failed, metav1.ConditionFalse, message); err != nil {
+		return true, fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	if err := utils.UpdateNodePoolPluginStatus(ctx, c.Client, nodepool); err != nil {
+		return true, fmt.Errorf("failed to update plugin status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+func TestAdaptors(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Adaptors Suite")
+}
+
+func newTestNode(groupname, hwprofile string) hwmgmtv1alpha1.Node {
+	return hwmgmtv1alpha1.Node{
+		Spec: hwmgmtv1alpha1.NodeSpec{
+			GroupName: groupname,
+			HwProfile: hwprofile,
+		},
+	}
+}
+
+var _ = Describe("Adaptor capabilities", func() {
+	var (
+		nodepool *hwmgmtv1alpha1.NodePool
+		nodelist *hwmgmtv1alpha1.NodeList
+	)
+
+	BeforeEach(func() {
+		nodepool = &hwmgmtv1alpha1.NodePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "np1", Generation: 2},
+			Spec: hwmgmtv1alpha1.NodePoolSpec{
+				NodeGroup: []hwmgmtv1alpha1.NodeGroup{
+					{
+						NodePoolData: hwmgmtv1alpha1.NodePoolData{Name: "controller", HwProfile: "profile-a"},
+						Size:         1,
+					},
+				},
+			},
+		}
+		nodelist = &hwmgmtv1alpha1.NodeList{
+			Items: []hwmgmtv1alpha1.Node{newTestNode("controller", "profile-a")},
+		}
+	})
+
+	It("detects a pending spec change only for a provisioned NodePool", func() {
+		Expect(isNodePoolSpecChanged(nodepool)).To(BeFalse())
+
+		nodepool.Status.Conditions = []metav1.Condition{{
+			Type:   string(hwmgmtv1alpha1.Provisioned),
+			Status: metav1.ConditionTrue,
+			Reason: string(hwmgmtv1alpha1.Completed),
+		}}
+		nodepool.Status.HwMgrPlugin.ObservedGeneration = 1
+		Expect(isNodePoolSpecChanged(nodepool)).To(BeTrue())
+
+		nodepool.Status.HwMgrPlugin.ObservedGeneration = 2
+		Expect(isNodePoolSpecChanged(nodepool)).To(BeFalse())
+	})
+
+	It("accepts a NodePool that matches its nodes", func() {
+		Expect(findUnsupportedChanges(pluginv1alpha1.AdaptorCapabilities{}, nodepool, nodelist)).To(BeEmpty())
+	})
+
+	It("rejects a profile update when unsupported", func() {
+		nodepool.Spec.NodeGroup[0].NodePoolData.HwProfile = "profile-b"
+
+		Expect(findUnsupportedChanges(pluginv1alpha1.AdaptorCapabilities{}, nodepool, nodelist)).To(
+			ConsistOf("hardware profile update of nodegroup controller to profile-b"))
+		Expect(findUnsupportedChanges(pluginv1alpha1.AdaptorCapabilities{ProfileUpdate: true}, nodepool, nodelist)).To(BeEmpty())
+	})
+
+	It("rejects scaling when unsupported", func() {
+		nodepool.Spec.NodeGroup[0].Size = 2
+		Expect(findUnsupportedChanges(pluginv1alpha1.AdaptorCapabilities{}, nodepool, nodelist)).To(
+			ConsistOf("scale-out of nodegroup controller from 1 to 2"))
+		Expect(findUnsupportedChanges(pluginv1alpha1.AdaptorCapabilities{ScaleOut: true}, nodepool, nodelist)).To(BeEmpty())
+
+		nodepool.Spec.NodeGroup[0].Size = 0
+		Expect(findUnsupportedChanges(pluginv1alpha1.AdaptorCapabilities{ScaleOut: true}, nodepool, nodelist)).To(
+			ConsistOf("scale-in of nodegroup controller from 1 to 0"))
+		Expect(findUnsupportedChanges(pluginv1alpha1.AdaptorCapabilities{ScaleIn: true}, nodepool, nodelist)).To(BeEmpty())
+	})
+})
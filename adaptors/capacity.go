@@ -0,0 +1,168 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+// ErrCapacityCheckUnsupported is returned when the adaptor of a hardware manager does not implement the capacity check
+var ErrCapacityCheckUnsupported = errors.New("capacity check not supported by adaptor")
+
+// getCapacityCheckHwMgrId gets the hardware manager selected for a nodegroup of a CapacityCheck
+func getCapacityCheckHwMgrId(check *pluginv1alpha1.CapacityCheck, nodegroup pluginv1alpha1.CapacityCheckNodeGroup) string {
+	if nodegroup.HwMgrId != "" {
+		return nodegroup.HwMgrId
+	}
+	return check.Spec.HwMgrId
+}
+
+// capacityCheckNodePools builds the NodePool that would be handed to each hardware manager selected by a CapacityCheck,
+// returning the hardware manager IDs in the order of their first nodegroup. The NodePools are not created.
+func capacityCheckNodePools(check *pluginv1alpha1.CapacityCheck) ([]string, map[string]*hwmgmtv1alpha1.NodePool) {
+	var hwMgrIds []string
+	nodepools := make(map[string]*hwmgmtv1alpha1.NodePool)
+
+	for _, nodegroup := range check.Spec.NodeGroups {
+		hwMgrId := getCapacityCheckHwMgrId(check, nodegroup)
+		nodepool, exists := nodepools[hwMgrId]
+		if !exists {
+			nodepool = &hwmgmtv1alpha1.NodePool{
+				ObjectMeta: metav1.ObjectMeta{Name: check.Name, Namespace: check.Namespace},
+				Spec: hwmgmtv1alpha1.NodePoolSpec{
+					HwMgrId:      hwMgrId,
+					LocationSpec: hwmgmtv1alpha1.LocationSpec{Site: check.Spec.Site},
+				},
+			}
+			nodepools[hwMgrId] = nodepool
+			hwMgrIds = append(hwMgrIds, hwMgrId)
+		}
+
+		nodepool.Spec.NodeGroup = append(nodepool.Spec.NodeGroup, hwmgmtv1alpha1.NodeGroup{
+			NodePoolData: hwmgmtv1alpha1.NodePoolData{
+				Name:           nodegroup.Name,
+				HwProfile:      nodegroup.HwProfile,
+				ResourcePoolId: nodegroup.ResourcePoolId,
+			},
+			Size: nodegroup.Size,
+		})
+	}
+
+	return hwMgrIds, nodepools
+}
+
+// computeNodeGroupCapacity reports the requested and available node counts of each nodegroup of a CapacityCheck, given
+// the free capacity of each resource pool per hardware manager. Nodegroups allocated from the same resource pool share
+// its capacity, in the order they are listed.
+func computeNodeGroupCapacity(
+	check *pluginv1alpha1.CapacityCheck,
+	capacity map[string]map[string]int) []pluginv1alpha1.NodeGroupCapacity {
+
+	remaining := make(map[string]map[string]int)
+	for hwMgrId, pools := range capacity {
+		remaining[hwMgrId] = make(map[string]int)
+		for poolId, free := range pools {
+			remaining[hwMgrId][poolId] = free
+		}
+	}
+
+	result := make([]pluginv1alpha1.NodeGroupCapacity, 0, len(check.Spec.NodeGroups))
+	for _, nodegroup := range check.Spec.NodeGroups {
+		hwMgrId := getCapacityCheckHwMgrId(check, nodegroup)
+		available := remaining[hwMgrId][nodegroup.ResourcePoolId]
+
+		result = append(result, pluginv1alpha1.NodeGroupCapacity{
+			Name:           nodegroup.Name,
+			HwMgrId:        hwMgrId,
+			ResourcePoolId: nodegroup.ResourcePoolId,
+			Requested:      nodegroup.Size,
+			Available:      available,
+		})
+
+		if remaining[hwMgrId] != nil {
+			remaining[hwMgrId][nodegroup.ResourcePoolId] = available - min(available, nodegroup.Size)
+		}
+	}
+
+	return result
+}
+
+// IsCapacityAvailable checks whether each nodegroup of a capacity check result could be satisfied
+func IsCapacityAvailable(nodegroups []pluginv1alpha1.NodeGroupCapacity) bool {
+	for _, nodegroup := range nodegroups {
+		if nodegroup.Requested > nodegroup.Available {
+			return false
+		}
+	}
+	return true
+}
+
+// getAvailableCapacity calls the adaptor of the hardware manager selected by the NodePool to get the free capacity of
+// the requested resource pools
+func (c *HwMgrAdaptorController) getAvailableCapacity(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) (map[string]int, error) {
+	hwmgr, err := c.getHwMgr(ctx, nodepool)
+	if err != nil {
+		return nil, err
+	}
+
+	adaptorID := string(hwmgr.Spec.AdaptorID)
+	adaptor, exists := c.adaptors[adaptorID]
+	if !exists {
+		return nil, fmt.Errorf("unsupported adaptor ID specified: %s", adaptorID)
+	}
+
+	checker, ok := adaptor.(adaptorinterface.CapacityChecker)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCapacityCheckUnsupported, adaptorID)
+	}
+
+	capacity, err := checker.GetAvailableCapacity(ctx, hwmgr, nodepool)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetAvailableCapacity for adaptorID %s: %w", adaptorID, err)
+	}
+
+	return capacity, nil
+}
+
+// CheckCapacity checks whether the NodePool request described by a CapacityCheck CR could currently be satisfied, by
+// querying the adaptor of each selected hardware manager for the free capacity of the requested resource pools. No
+// nodes are allocated.
+func (c *HwMgrAdaptorController) CheckCapacity(
+	ctx context.Context,
+	check *pluginv1alpha1.CapacityCheck) ([]pluginv1alpha1.NodeGroupCapacity, error) {
+
+	hwMgrIds, nodepools := capacityCheckNodePools(check)
+
+	capacity := make(map[string]map[string]int)
+	for _, hwMgrId := range hwMgrIds {
+		free, err := c.getAvailableCapacity(ctx, nodepools[hwMgrId])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get available capacity from HardwareManager %s: %w", hwMgrId, err)
+		}
+		capacity[hwMgrId] = free
+	}
+
+	return computeNodeGroupCapacity(check, capacity), nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+var _ = Describe("Capacity check", func() {
+	var check *pluginv1alpha1.CapacityCheck
+
+	BeforeEach(func() {
+		check = &pluginv1alpha1.CapacityCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "check", Namespace: "hwmgr"},
+			Spec: pluginv1alpha1.CapacityCheckSpec{
+				HwMgrId: "hwmgr-a",
+				Site:    "ottawa",
+				NodeGroups: []pluginv1alpha1.CapacityCheckNodeGroup{
+					{Name: "master", ResourcePoolId: "pool-1", HwProfile: "profile-a", Size: 3},
+					{Name: "worker", ResourcePoolId: "pool-1", HwProfile: "profile-b", Size: 2},
+					{Name: "storage", ResourcePoolId: "pool-2", Size: 1, HwMgrId: "hwmgr-b"},
+				},
+			},
+		}
+	})
+
+	It("builds a NodePool per hardware manager", func() {
+		hwMgrIds, nodepools := capacityCheckNodePools(check)
+		Expect(hwMgrIds).To(Equal([]string{"hwmgr-a", "hwmgr-b"}))
+
+		nodepool := nodepools["hwmgr-a"]
+		Expect(nodepool.Spec.HwMgrId).To(Equal("hwmgr-a"))
+		Expect(nodepool.Spec.Site).To(Equal("ottawa"))
+		Expect(nodepool.Spec.NodeGroup).To(HaveLen(2))
+		Expect(nodepool.Spec.NodeGroup[1].NodePoolData.Name).To(Equal("worker"))
+		Expect(nodepool.Spec.NodeGroup[1].NodePoolData.HwProfile).To(Equal("profile-b"))
+		Expect(nodepool.Spec.NodeGroup[1].Size).To(Equal(2))
+
+		Expect(nodepools["hwmgr-b"].Spec.NodeGroup).To(HaveLen(1))
+		Expect(nodepools["hwmgr-b"].Spec.NodeGroup[0].NodePoolData.ResourcePoolId).To(Equal("pool-2"))
+	})
+
+	It("shares the capacity of a resource pool across nodegroups", func() {
+		capacity := map[string]map[string]int{
+			"hwmgr-a": {"pool-1": 4},
+			"hwmgr-b": {"pool-2": 1},
+		}
+
+		result := computeNodeGroupCapacity(check, capacity)
+		Expect(result).To(Equal([]pluginv1alpha1.NodeGroupCapacity{
+			{Name: "master", HwMgrId: "hwmgr-a", ResourcePoolId: "pool-1", Requested: 3, Available: 4},
+			{Name: "worker", HwMgrId: "hwmgr-a", ResourcePoolId: "pool-1", Requested: 2, Available: 1},
+			{Name: "storage", HwMgrId: "hwmgr-b", ResourcePoolId: "pool-2", Requested: 1, Available: 1},
+		}))
+		Expect(IsCapacityAvailable(result)).To(BeFalse())
+
+		// The input capacity is left untouched
+		Expect(capacity["hwmgr-a"]["pool-1"]).To(Equal(4))
+
+		capacity["hwmgr-a"]["pool-1"] = 5
+		Expect(IsCapacityAvailable(computeNodeGroupCapacity(check, capacity))).To(BeTrue())
+	})
+
+	It("reports no capacity for unknown resource pools", func() {
+		result := computeNodeGroupCapacity(check, map[string]map[string]int{"hwmgr-a": {"pool-1": 5}})
+		Expect(result[2].Available).To(BeZero())
+		Expect(IsCapacityAvailable(result)).To(BeFalse())
+	})
+})
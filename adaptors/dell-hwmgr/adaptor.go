@@ -20,10 +20,14 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
+	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
 	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/controller"
 	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/hwmgrclient"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/notifications"
 	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,6 +44,9 @@ type Adaptor struct {
 	Logger    *slog.Logger
 	Namespace string
 	AdaptorID pluginv1alpha1.HardwareManagerAdaptorID
+
+	// receiver is the notification receiver, if enabled
+	receiver *notifications.Receiver
 }
 
 func NewAdaptor(client client.Client, scheme *runtime.Scheme, logger *slog.Logger, namespace string) *Adaptor {
@@ -51,15 +58,26 @@ func NewAdaptor(client client.Client, scheme *runtime.Scheme, logger *slog.Logge
 	}
 }
 
+// capabilities defines the NodePool operations supported by the Dell adaptor. Hardware profiles are updated
+// one node at a time via UpdateResourceProfile. Nodegroups are scaled by adding resources to, or releasing them from,
+// the resource group of the NodePool.
+var capabilities = pluginv1alpha1.AdaptorCapabilities{
+	ProfileUpdate: true,
+	ScaleOut:      true,
+	ScaleIn:       true,
+}
+
 // SetupAdaptor sets up the Dell Hardware Manager Adaptor
 func (a *Adaptor) SetupAdaptor(mgr ctrl.Manager) error {
 	a.Logger.Info("SetupAdaptor called for DellHwMgr")
 
 	if err := (&controller.HardwareManagerReconciler{
-		Client:    a.Client,
-		Scheme:    a.Scheme,
-		Logger:    a.Logger,
-		Namespace: a.Namespace,
+		Client:       a.Client,
+		Scheme:       a.Scheme,
+		Logger:       a.Logger,
+		Namespace:    a.Namespace,
+		Capabilities: capabilities,
+		Receiver:     a.receiver,
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("unable to setup dell-hwmgr adaptor: %w", err)
 	}
@@ -111,41 +129,241 @@ func (a *Adaptor) determineAction(ctx context.Context, nodepool *hwmgmtv1alpha1.
 func (a *Adaptor) HandleNodePool(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
 	result := utils.DoNotRequeue()
 
+	// Requests to the hardware manager are suspended while its circuit breaker is open, as reported by the
+	// HardwareManager Degraded condition, so the NodePool is requeued until a trial request is allowed
+	if breaker := hwmgrclient.GetCircuitBreakerStatus(hwmgr); breaker.State == hwmgrclient.CircuitOpen {
+		a.Logger.InfoContext(ctx, "Circuit breaker open for hardware manager, deferring NodePool processing",
+			slog.Time("retryAt", breaker.RetryAt))
+		return utils.RequeueWithCustomInterval(max(time.Until(breaker.RetryAt), time.Second)), nil
+	}
+
 	hwmgrClient, clientErr := hwmgrclient.NewClientWithResponses(ctx, a.Logger, a.Client, hwmgr)
 	if clientErr != nil {
-		// TODO: Improve client error handling to distinguish between connectivity errors, auth, etc
 		a.Logger.InfoContext(ctx, "NewClientWithResponses error", slog.String("error", clientErr.Error()))
-		return result, fmt.Errorf("failed to setup hwmgr client: %w", clientErr)
+		return a.handleRequestError(ctx, nodepool, fmt.Errorf("failed to setup hwmgr client: %w", clientErr))
 	}
 
+	var err error
 	switch a.determineAction(ctx, nodepool) {
 	case NodePoolFSMCreate:
-		return a.HandleNodePoolCreate(ctx, hwmgrClient, hwmgr, nodepool)
+		result, err = a.HandleNodePoolCreate(ctx, hwmgrClient, hwmgr, nodepool)
 	case NodePoolFSMProcessing:
-		return a.HandleNodePoolProcessing(ctx, hwmgrClient, hwmgr, nodepool)
+		result, err = a.HandleNodePoolProcessing(ctx, hwmgrClient, hwmgr, nodepool)
 	case NodePoolFSMSpecChanged:
-		return a.HandleNodePoolSpecChanged(ctx, hwmgrClient, hwmgr, nodepool)
+		result, err = a.HandleNodePoolSpecChanged(ctx, hwmgrClient, hwmgr, nodepool)
 	case NodePoolFSMNoop:
 		// Nothing to do
 		return result, nil
 	}
 
+	if err != nil {
+		return a.handleRequestError(ctx, nodepool, err)
+	}
 	return result, nil
 }
 
-func (a *Adaptor) HandleNodePoolDeletion(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) error {
+// handleRequestError determines how the processing of a NodePool proceeds after a failed hardware manager request.
+// Transient failures are returned, to be retried by the adaptor controller with a bounded backoff that honours the
+// delay requested by a rate limited response. A failure of the credentials or TLS configuration affects every NodePool
+// of the hardware manager, as reported by the HardwareManager Validation condition, so the NodePool is requeued at a
+// long interval until it is corrected. Any other failed request is permanent for the NodePool operation, which is
+// marked as failed rather than retried.
+func (a *Adaptor) handleRequestError(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, err error) (ctrl.Result, error) {
+	if _, ok := hwmgrclient.AsHwmgrError(err); !ok || hwmgrclient.IsTransient(err) {
+		return utils.DoNotRequeue(), err
+	}
+
+	if hwmgrclient.IsConfigurationError(err) {
+		a.Logger.InfoContext(ctx, "Hardware manager configuration error", slog.String("error", err.Error()))
+		return hwmgrclient.GetRequeue(err), nil
+	}
+
+	message := "Hardware manager request failed: " + err.Error()
+	if utils.IsNodePoolProvisionedCompleted(nodepool) {
+		return a.failNodePoolConfiguring(ctx, nodepool, message)
+	}
+
+	a.Logger.InfoContext(ctx, "NodePool provisioning failed", slog.String("reason", message))
+	if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+		hwmgmtv1alpha1.Provisioned, hwmgmtv1alpha1.Failed, metav1.ConditionFalse, message); err != nil {
+		return utils.RequeueWithMediumInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	return utils.DoNotRequeue(), nil
+}
+
+func (a *Adaptor) HandleNodePoolDeletion(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (bool, error) {
 	a.Logger.InfoContext(ctx, "Finalizing nodepool")
 
 	hwmgrClient, clientErr := hwmgrclient.NewClientWithResponses(ctx, a.Logger, a.Client, hwmgr)
 	if clientErr != nil {
-		// TODO: Improve client error handling to distinguish between connectivity errors, auth, etc
 		a.Logger.InfoContext(ctx, "NewClientWithResponses error", slog.String("error", clientErr.Error()))
-		return fmt.Errorf("failed to setup hwmgr client: %w", clientErr)
+		return false, fmt.Errorf("failed to setup hwmgr client: %w", clientErr)
+	}
+
+	released, err := a.ReleaseNodePool(ctx, hwmgrClient, hwmgr, nodepool)
+	if err != nil {
+		return false, fmt.Errorf("failed to release nodepool %s: %w", nodepool.Name, err)
+	}
+
+	return released, nil
+}
+
+// HandleNodePoolTimeout clears the jobIds of the NodePool and its nodes when provisioning or configuration times out,
+// as the hardware manager API provides no means to cancel a job. The NodePool is no longer processed, so the timed out
+// job is not checked again. As for a failed job, a node with a timed out profile update is reverted to its current
+// profile, so that the update is reissued if the NodePool is retried.
+func (a *Adaptor) HandleNodePoolTimeout(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) error {
+	if jobId := utils.GetJobId(nodepool); jobId != "" {
+		a.Logger.InfoContext(ctx, "Abandoning timed out job", slog.String("jobId", jobId))
+		utils.ClearJobId(nodepool)
+		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+			return fmt.Errorf("failed to clear annotation from nodepool %s: %w", nodepool.Name, err)
+		}
+	}
+
+	nodelist, err := utils.GetChildNodes(ctx, a.Logger, a.Client, nodepool)
+	if err != nil {
+		return fmt.Errorf("failed to get child nodes for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	for i := range nodelist.Items {
+		node := &nodelist.Items[i]
+		if jobId := utils.GetJobId(node); jobId != "" {
+			a.Logger.InfoContext(ctx, "Abandoning timed out job", slog.String("node", node.Name), slog.String("jobId", jobId))
+			patch := client.MergeFrom(node.DeepCopy())
+			if node.Status.HwProfile != "" {
+				node.Spec.HwProfile = node.Status.HwProfile
+			}
+			utils.ClearJobId(node)
+			if err := a.Client.Patch(ctx, node, patch); err != nil {
+				return fmt.Errorf("failed to patch Node %s in namespace %s: %w", node.Name, node.Namespace, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetCapabilities returns the NodePool operations supported by the Dell adaptor
+func (a *Adaptor) GetCapabilities(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) (pluginv1alpha1.AdaptorCapabilities, error) {
+	return capabilities, nil
+}
+
+// CheckReadiness verifies that a token can be acquired from the hardware manager and the resource pools queried
+func (a *Adaptor) CheckReadiness(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) error {
+	hwmgrClient, err := hwmgrclient.NewClientWithResponses(ctx, a.Logger, a.Client, hwmgr)
+	if err != nil {
+		return fmt.Errorf("failed to setup hwmgr client: %w", err)
 	}
 
-	if err := a.ReleaseNodePool(ctx, hwmgrClient, hwmgr, nodepool); err != nil {
-		return fmt.Errorf("failed to release nodepool %s: %w", nodepool.Name, err)
+	if _, err := hwmgrClient.GetResourcePools(ctx); err != nil {
+		return fmt.Errorf("failed to query resource pools: %w", err)
 	}
 
 	return nil
 }
+
+// CheckNodePoolDrift compares the NodePool and its Node CRs with the resources of its resource group in the hardware
+// manager. All nodes of the NodePool are reported as missing if the resource group no longer exists.
+func (a *Adaptor) CheckNodePoolDrift(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (*adaptorinterface.NodePoolDrift, error) {
+	hwmgrClient, err := hwmgrclient.NewClientWithResponses(ctx, a.Logger, a.Client, hwmgr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup hwmgr client: %w", err)
+	}
+
+	exists, err := hwmgrClient.ResourceGroupExists(ctx, nodepool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for resource group: %w", err)
+	}
+
+	var allocated []string
+	if exists {
+		rg, err := hwmgrClient.GetResourceGroup(ctx, nodepool)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resource group: %w", err)
+		}
+		if rg.ResourceSelectors != nil {
+			for _, resourceSelector := range *rg.ResourceSelectors {
+				if resourceSelector.Resources == nil {
+					continue
+				}
+				for _, resource := range *resourceSelector.Resources {
+					allocated = append(allocated, *resource.Id)
+				}
+			}
+		}
+	}
+
+	nodelist, err := utils.GetChildNodes(ctx, a.Logger, a.Client, nodepool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child nodes for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	missing, unexpected := utils.FindNodeDrift(nodepool.Status.Properties.NodeNames, nodelist, allocated,
+		func(node *hwmgmtv1alpha1.Node) string { return node.Spec.HwMgrNodeId })
+
+	return &adaptorinterface.NodePoolDrift{MissingNodes: missing, UnexpectedNodes: unexpected}, nil
+}
+
+// RemediateNodePoolDrift deletes the Node CRs and bmc-secrets of the missing nodes of the NodePool. When the provisioning
+// is restarted, the resource group is recreated if it no longer exists, and Node CRs are created for any resources of
+// the resource group without one.
+func (a *Adaptor) RemediateNodePoolDrift(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	drift *adaptorinterface.NodePoolDrift) error {
+
+	for _, nodename := range drift.MissingNodes {
+		if err := a.DeleteNode(ctx, nodename); err != nil {
+			return err
+		}
+	}
+
+	nodepool.Status.Properties.NodeNames = slices.DeleteFunc(slices.Clone(nodepool.Status.Properties.NodeNames),
+		func(nodename string) bool { return slices.Contains(drift.MissingNodes, nodename) })
+	if err := utils.UpdateNodePoolProperties(ctx, a.Client, nodepool); err != nil {
+		return fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	return nil
+}
+
+// GetAvailableCapacity counts the resources of each resource pool requested by the NodePool that are not a member of
+// any resource group
+func (a *Adaptor) GetAvailableCapacity(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (map[string]int, error) {
+	hwmgrClient, err := hwmgrclient.NewClientWithResponses(ctx, a.Logger, a.Client, hwmgr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup hwmgr client: %w", err)
+	}
+
+	capacity := make(map[string]int)
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		poolId := nodegroup.NodePoolData.ResourcePoolId
+		if _, exists := capacity[poolId]; exists {
+			continue
+		}
+
+		pool, err := hwmgrClient.GetResourcePool(ctx, poolId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resource pool: %w", err)
+		}
+
+		if nodepool.Spec.Site != "" && pool.SiteId != nil && *pool.SiteId != nodepool.Spec.Site {
+			return nil, fmt.Errorf("resource pool %s is not at site %s", poolId, nodepool.Spec.Site)
+		}
+
+		capacity[poolId] = 0
+		if pool.Resources != nil {
+			for _, resource := range *pool.Resources {
+				if hwmgrclient.IsResourceFree(resource) {
+					capacity[poolId]++
+				}
+			}
+		}
+	}
+
+	return capacity, nil
+}
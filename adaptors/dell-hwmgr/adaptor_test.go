@@ -0,0 +1,60 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dellhwmgr
+
+import (
+	"context"
+	"log/slog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+)
+
+var _ = Describe("Dell NodePool timeouts", func() {
+	It("abandons the timed out jobs and reverts the profile updates", func() {
+		ctx := context.Background()
+
+		nodepool := newNodePool("np1", "job-1")
+		updating := newNode("node-1", "np1", "resource-1", "job-2")
+		updating.Spec.HwProfile = "profile-new"
+		updating.Status.HwProfile = "profile-old"
+		updated := newNode("node-2", "np1", "resource-2", "")
+		updated.Spec.HwProfile = "profile-new"
+		updated.Status.HwProfile = "profile-new"
+
+		fakeClient := newTestClient(nodepool, updating, updated)
+		adaptor := NewAdaptor(fakeClient, fakeClient.Scheme(), slog.New(slog.NewTextHandler(GinkgoWriter, nil)), testNamespace)
+
+		Expect(adaptor.HandleNodePoolTimeout(ctx, nil, nodepool)).To(Succeed())
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(nodepool), nodepool)).To(Succeed())
+		Expect(utils.GetJobId(nodepool)).To(BeEmpty())
+
+		// The node is selected again for the update if the NodePool is retried
+		node := &hwmgmtv1alpha1.Node{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(updating), node)).To(Succeed())
+		Expect(utils.GetJobId(node)).To(BeEmpty())
+		Expect(node.Spec.HwProfile).To(Equal("profile-old"))
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(updated), node)).To(Succeed())
+		Expect(node.Spec.HwProfile).To(Equal("profile-new"))
+	})
+})
@@ -21,28 +21,48 @@ import (
 	"fmt"
 	"log/slog"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/hwmgrclient"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/logging"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/notifications"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
 )
 
+// SubscriptionFinalizer holds the deletion of a HardwareManager subscribed to notifications until its resource
+// subscription has been removed from the hardware manager
+const SubscriptionFinalizer = "oran-hwmgr-plugin/subscription-finalizer"
+
 // HardwareManagerReconciler reconciles a HardwareManager object
 type HardwareManagerReconciler struct {
 	client.Client
-	Scheme    *runtime.Scheme
-	Logger    *slog.Logger
-	Namespace string
-	AdaptorID pluginv1alpha1.HardwareManagerAdaptorID
+	Scheme       *runtime.Scheme
+	Logger       *slog.Logger
+	Namespace    string
+	AdaptorID    pluginv1alpha1.HardwareManagerAdaptorID
+	Capabilities pluginv1alpha1.AdaptorCapabilities
+
+	// Receiver is the notification receiver the hardware managers push notifications to, if enabled
+	Receiver *notifications.Receiver
 }
 
 //+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=hardwaremanagers,verbs=get;list;watch;create;update;patch;delete
@@ -62,7 +82,8 @@ func (r *HardwareManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	hwmgr := &pluginv1alpha1.HardwareManager{}
 	if err = r.Client.Get(ctx, req.NamespacedName, hwmgr); err != nil {
 		if errors.IsNotFound(err) {
-			// The NodePool has likely been deleted
+			// The HardwareManager has likely been deleted, so drop its cached client
+			hwmgrclient.EvictClient(req.NamespacedName)
 			err = nil
 			return
 		}
@@ -82,7 +103,13 @@ func (r *HardwareManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	ctx = logging.AppendCtx(ctx, slog.String("hwmgr", hwmgr.Name))
 
+	if !hwmgr.DeletionTimestamp.IsZero() {
+		result, err = r.handleDeletion(ctx, hwmgr)
+		return
+	}
+
 	hwmgr.Status.ObservedGeneration = hwmgr.Generation
+	hwmgr.Status.Capabilities = r.Capabilities.DeepCopy()
 
 	if hwmgr.Spec.DellData == nil {
 		// Invalid data
@@ -100,16 +127,50 @@ func (r *HardwareManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	result = utils.RequeueWithLongInterval()
 
+	// Check that the auth secret provides the credentials for the configured grant type
+	authSecret, secretErr := utils.GetSecret(ctx, r.Client, hwmgr.Spec.DellData.AuthSecret, hwmgr.Namespace)
+	if secretErr == nil {
+		secretErr = hwmgrclient.ValidateAuthSecret(hwmgr, authSecret)
+	}
+	if secretErr != nil {
+		if updateErr := utils.UpdateHardwareManagerStatusCondition(ctx, r.Client, hwmgr,
+			pluginv1alpha1.ConditionTypes.Validation,
+			pluginv1alpha1.ConditionReasons.Failed,
+			metav1.ConditionFalse,
+			"Invalid auth secret - "+secretErr.Error()); updateErr != nil {
+			err = fmt.Errorf("failed to update status for hardware manager (%s) with validation failure: %w", hwmgr.Name, updateErr)
+			return
+		}
+		r.Logger.Error("Invalid auth secret for hardware manager", slog.String("name", hwmgr.Name), slog.String("error", secretErr.Error()))
+		return
+	}
+
+	// While the circuit breaker is open, requests to the hardware manager are suspended, so validation is deferred
+	// until a trial request is allowed
+	breaker := hwmgrclient.GetCircuitBreakerStatus(hwmgr)
+	setDegradedCondition(hwmgr, breaker)
+	if breaker.State == hwmgrclient.CircuitOpen {
+		result = utils.RequeueWithCustomInterval(max(time.Until(breaker.RetryAt), time.Second))
+		if updateErr := utils.UpdateK8sCRStatus(ctx, r.Client, hwmgr); updateErr != nil {
+			err = fmt.Errorf("failed to update status for hardware manager (%s) with degraded condition: %w", hwmgr.Name, updateErr)
+			return
+		}
+		r.Logger.InfoContext(ctx, "Circuit breaker open for hardware manager", slog.Time("retryAt", breaker.RetryAt),
+			slog.String("lastFailure", breaker.LastFailure))
+		return
+	}
+
 	r.Logger.InfoContext(ctx, "Validating client connection", slog.String("apiUrl", hwmgr.Spec.DellData.ApiUrl))
 
 	client, clientErr := hwmgrclient.NewClientWithResponses(ctx, r.Logger, r.Client, hwmgr)
 	if clientErr != nil {
 		r.Logger.InfoContext(ctx, "NewClientWithResponses error", slog.String("error", clientErr.Error()))
+		result = hwmgrclient.GetRequeue(clientErr)
 		if updateErr := utils.UpdateHardwareManagerStatusCondition(ctx, r.Client, hwmgr,
 			pluginv1alpha1.ConditionTypes.Validation,
-			pluginv1alpha1.ConditionReasons.Failed,
+			hwmgrclient.GetConditionReason(clientErr),
 			metav1.ConditionFalse,
-			"Authentication failure - "+clientErr.Error()); updateErr != nil {
+			getClientErrorMessage(clientErr)); updateErr != nil {
 			err = fmt.Errorf("failed to update status for hardware manager (%s) with authentication failure: %w", hwmgr.Name, updateErr)
 			return
 		}
@@ -120,9 +181,10 @@ func (r *HardwareManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	pools, clientErr := client.GetResourcePools(ctx)
 	if clientErr != nil {
 		r.Logger.InfoContext(ctx, "GetResourcePools error", slog.String("error", clientErr.Error()))
+		result = hwmgrclient.GetRequeue(clientErr)
 		if updateErr := utils.UpdateHardwareManagerStatusCondition(ctx, r.Client, hwmgr,
 			pluginv1alpha1.ConditionTypes.Validation,
-			pluginv1alpha1.ConditionReasons.Failed,
+			hwmgrclient.GetConditionReason(clientErr),
 			metav1.ConditionFalse,
 			"Failed to query resource pools - "+clientErr.Error()); updateErr != nil {
 			err = fmt.Errorf("failed to update status for hardware manager (%s) with authentication failure: %w", hwmgr.Name, updateErr)
@@ -154,6 +216,12 @@ func (r *HardwareManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		}
 	}
 
+	// The Subscribed condition is recorded with the validation result below
+	if subscriptionErr := r.reconcileSubscription(ctx, client, hwmgr); subscriptionErr != nil {
+		r.Logger.InfoContext(ctx, "Failed to reconcile notification subscription", slog.String("error", subscriptionErr.Error()))
+		result = hwmgrclient.GetRequeue(subscriptionErr)
+	}
+
 	if updateErr := utils.UpdateHardwareManagerStatusCondition(ctx, r.Client, hwmgr,
 		pluginv1alpha1.ConditionTypes.Validation,
 		pluginv1alpha1.ConditionReasons.Completed,
@@ -166,6 +234,201 @@ func (r *HardwareManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	return
 }
 
+// getClientErrorMessage returns the Validation condition message for a failure to establish an authenticated
+// connection to the hardware manager
+func getClientErrorMessage(clientErr error) string {
+	if hwmgrErr, ok := hwmgrclient.AsHwmgrError(clientErr); ok &&
+		hwmgrErr.Kind != hwmgrclient.ErrorKindUnauthorized && hwmgrErr.Kind != hwmgrclient.ErrorKindForbidden {
+		return "Connection failure - " + clientErr.Error()
+	}
+	return "Authentication failure - " + clientErr.Error()
+}
+
+// setDegradedCondition sets the Degraded condition from the circuit breaker for the requests to the hardware manager.
+// A half-open circuit remains degraded until its trial request succeeds.
+func setDegradedCondition(hwmgr *pluginv1alpha1.HardwareManager, breaker hwmgrclient.CircuitBreakerStatus) {
+	if breaker.State == hwmgrclient.CircuitClosed {
+		utils.SetStatusCondition(&hwmgr.Status.Conditions,
+			string(pluginv1alpha1.ConditionTypes.Degraded),
+			string(pluginv1alpha1.ConditionReasons.CircuitClosed),
+			metav1.ConditionFalse,
+			"Requests to the hardware manager are allowed")
+		return
+	}
+
+	message := fmt.Sprintf("Requests to the hardware manager are suspended after %d consecutive failures, last failure: %s",
+		breaker.Failures, breaker.LastFailure)
+	if breaker.State == hwmgrclient.CircuitOpen {
+		message += fmt.Sprintf(". Retrying at %s", breaker.RetryAt.UTC().Format(time.RFC3339))
+	}
+	utils.SetStatusCondition(&hwmgr.Status.Conditions,
+		string(pluginv1alpha1.ConditionTypes.Degraded),
+		string(pluginv1alpha1.ConditionReasons.CircuitOpen),
+		metav1.ConditionTrue,
+		message)
+}
+
+// reconcileSubscription maintains the resource subscription through which the hardware manager pushes notifications
+// for the jobs of the tenant and the resources allocated to Nodes, and sets the Subscribed condition accordingly. While
+// the HardwareManager is not subscribed, the adaptor polls the status of its jobs at the short interval.
+func (r *HardwareManagerReconciler) reconcileSubscription(
+	ctx context.Context,
+	client *hwmgrclient.HardwareManagerClient,
+	hwmgr *pluginv1alpha1.HardwareManager) error {
+
+	id := hwmgrclient.GetSubscriptionId(hwmgr)
+	config := hwmgr.Spec.DellData.Notifications
+	if config == nil {
+		if meta.FindStatusCondition(hwmgr.Status.Conditions, string(pluginv1alpha1.ConditionTypes.Subscribed)) != nil {
+			// Notifications have been disabled. Removing the subscription is best effort, as notifications received
+			// for it only trigger an extra reconcile.
+			if err := client.UnsubscribeResources(ctx, id, nil); err != nil {
+				r.Logger.InfoContext(ctx, "Failed to remove notification subscription", slog.String("error", err.Error()))
+			}
+			meta.RemoveStatusCondition(&hwmgr.Status.Conditions, string(pluginv1alpha1.ConditionTypes.Subscribed))
+		}
+		return r.updateSubscriptionFinalizer(ctx, hwmgr, false)
+	}
+
+	if r.Receiver == nil {
+		utils.SetStatusCondition(&hwmgr.Status.Conditions,
+			string(pluginv1alpha1.ConditionTypes.Subscribed),
+			string(pluginv1alpha1.ConditionReasons.Unsupported),
+			metav1.ConditionFalse,
+			"The notification receiver of the plugin is not enabled")
+		return nil
+	}
+
+	if err := r.updateSubscriptionFinalizer(ctx, hwmgr, true); err != nil {
+		return err
+	}
+
+	resources, err := r.getAllocatedResources(ctx, hwmgr)
+	if err != nil {
+		return err
+	}
+
+	callback := strings.TrimSuffix(config.CallbackUrl, "/") + notifications.Path(r.AdaptorID, hwmgr.Name)
+	if err := client.ReconcileSubscription(ctx, id, callback, resources); err != nil {
+		utils.SetStatusCondition(&hwmgr.Status.Conditions,
+			string(pluginv1alpha1.ConditionTypes.Subscribed),
+			string(hwmgrclient.GetConditionReason(err)),
+			metav1.ConditionFalse,
+			"Failed to subscribe to notifications - "+err.Error())
+		return err
+	}
+
+	utils.SetStatusCondition(&hwmgr.Status.Conditions,
+		string(pluginv1alpha1.ConditionTypes.Subscribed),
+		string(pluginv1alpha1.ConditionReasons.Completed),
+		metav1.ConditionTrue,
+		fmt.Sprintf("Subscribed to notifications for %d resources", len(resources)))
+	return nil
+}
+
+// updateSubscriptionFinalizer adds or removes the subscription finalizer. The finalizers are patched from a copy of the
+// HardwareManager, so that the status being reconciled is not overwritten by the response.
+func (r *HardwareManagerReconciler) updateSubscriptionFinalizer(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, add bool) error {
+	updated := hwmgr.DeepCopy()
+	changed := controllerutil.RemoveFinalizer(updated, SubscriptionFinalizer)
+	if add {
+		changed = controllerutil.AddFinalizer(updated, SubscriptionFinalizer)
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := r.Client.Patch(ctx, updated, client.MergeFrom(hwmgr)); err != nil {
+		return fmt.Errorf("failed to update finalizers of hardware manager %s: %w", hwmgr.Name, err)
+	}
+
+	hwmgr.Finalizers = updated.Finalizers
+	hwmgr.ResourceVersion = updated.ResourceVersion
+	return nil
+}
+
+// handleDeletion removes the notification subscription of a deleted HardwareManager from the hardware manager before
+// releasing the finalizer. A subscription that cannot be removed because of the HardwareManager configuration is
+// abandoned, rather than blocking the deletion until the configuration is corrected.
+func (r *HardwareManagerReconciler) handleDeletion(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(hwmgr, SubscriptionFinalizer) {
+		hwmgrclient.EvictClient(client.ObjectKeyFromObject(hwmgr))
+		return utils.DoNotRequeue(), nil
+	}
+
+	r.Logger.InfoContext(ctx, "Removing notification subscription of deleted hardware manager")
+
+	if err := r.unsubscribe(ctx, hwmgr); err != nil {
+		if hwmgrclient.IsTransient(err) {
+			r.Logger.InfoContext(ctx, "Failed to remove notification subscription, retrying", slog.String("error", err.Error()))
+			return hwmgrclient.GetRequeue(err), nil
+		}
+		r.Logger.ErrorContext(ctx, "Abandoning notification subscription", slog.String("error", err.Error()))
+	}
+
+	if err := r.updateSubscriptionFinalizer(ctx, hwmgr, false); err != nil {
+		return utils.RequeueWithShortInterval(), err
+	}
+
+	hwmgrclient.EvictClient(client.ObjectKeyFromObject(hwmgr))
+	return utils.DoNotRequeue(), nil
+}
+
+// unsubscribe deletes the notification subscription of the HardwareManager from the hardware manager
+func (r *HardwareManagerReconciler) unsubscribe(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) error {
+	if hwmgr.Spec.DellData == nil {
+		return nil
+	}
+
+	hwmgrClient, err := hwmgrclient.NewClientWithResponses(ctx, r.Logger, r.Client, hwmgr)
+	if err != nil {
+		return fmt.Errorf("failed to create client for hardware manager %s: %w", hwmgr.Name, err)
+	}
+
+	if err := hwmgrClient.UnsubscribeResources(ctx, hwmgrclient.GetSubscriptionId(hwmgr), nil); err != nil {
+		return fmt.Errorf("failed to remove notification subscription: %w", err)
+	}
+	return nil
+}
+
+// getAllocatedResources returns the sorted IDs of the resources allocated to the Nodes of the HardwareManager
+func (r *HardwareManagerReconciler) getAllocatedResources(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) ([]string, error) {
+	var nodes hwmgmtv1alpha1.NodeList
+	if err := r.Client.List(ctx, &nodes, client.InNamespace(hwmgr.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list Nodes: %w", err)
+	}
+
+	var resources []string
+	for _, node := range nodes.Items {
+		if node.Spec.HwMgrId == hwmgr.Name && node.Spec.HwMgrNodeId != "" {
+			resources = append(resources, node.Spec.HwMgrNodeId)
+		}
+	}
+	slices.Sort(resources)
+	return slices.Compact(resources), nil
+}
+
+// mapNodeToHardwareManager enqueues the HardwareManager of a created or deleted Node, to update the resources of its
+// notification subscription
+func (r *HardwareManagerReconciler) mapNodeToHardwareManager(ctx context.Context, obj client.Object) []reconcile.Request {
+	node, ok := obj.(*hwmgmtv1alpha1.Node)
+	if !ok || node.Spec.HwMgrId == "" {
+		return nil
+	}
+
+	hwmgr := &pluginv1alpha1.HardwareManager{}
+	key := types.NamespacedName{Namespace: node.Namespace, Name: node.Spec.HwMgrId}
+	if err := r.Client.Get(ctx, key, hwmgr); err != nil {
+		return nil
+	}
+
+	if hwmgr.Spec.AdaptorID != r.AdaptorID || hwmgr.Spec.DellData == nil || hwmgr.Spec.DellData.Notifications == nil {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: key}}
+}
+
 func filterEvents(adaptorID pluginv1alpha1.HardwareManagerAdaptorID) predicate.Predicate {
 	return predicate.NewPredicateFuncs(func(object client.Object) bool {
 		hwmgr := object.(*pluginv1alpha1.HardwareManager)
@@ -179,9 +442,18 @@ func (r *HardwareManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.Logger.Info("Setting up Dell controller", slog.String("adaptorId", string(r.AdaptorID)))
 	if err := ctrl.NewControllerManagedBy(mgr).
 		Named(string(r.AdaptorID)).
-		For(&pluginv1alpha1.HardwareManager{}).
-		WithEventFilter(filterEvents(r.AdaptorID)).
-		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})).
+		For(&pluginv1alpha1.HardwareManager{}, builder.WithPredicates(
+			filterEvents(r.AdaptorID),
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}))).
+		// Reconcile the HardwareManager when a Node is allocated or released, to update its notification subscription
+		Watches(&hwmgmtv1alpha1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.mapNodeToHardwareManager),
+			builder.WithPredicates(predicate.Funcs{
+				UpdateFunc:  func(event.UpdateEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+			})).
+		// Reconcile the HardwareManager when its circuit breaker is opened or closed, to update the Degraded condition
+		WatchesRawSource(source.Channel(hwmgrclient.CircuitBreakerEvents(), &handler.EnqueueRequestForObject{})).
 		Complete(r); err != nil {
 		return fmt.Errorf("failed to setup controller for %s: %w", r.AdaptorID, err)
 	}
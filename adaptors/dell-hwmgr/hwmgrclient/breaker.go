@@ -0,0 +1,356 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+const (
+	defaultRequestsPerSecond     = 10
+	defaultBurst                 = 20
+	defaultMaxConcurrentRequests = 10
+	defaultFailureThreshold      = 5
+	defaultOpenDuration          = time.Minute
+
+	// circuitProbeInterval is the delay before retrying a request that was rejected while the trial request of a
+	// half-open circuit is in flight
+	circuitProbeInterval = 5 * time.Second
+)
+
+// CircuitState is the state of the circuit breaker for the requests to a hardware manager
+type CircuitState string
+
+const (
+	// CircuitClosed allows all requests
+	CircuitClosed CircuitState = "Closed"
+	// CircuitOpen rejects all requests, after consecutive failures
+	CircuitOpen CircuitState = "Open"
+	// CircuitHalfOpen allows a single trial request once the open duration has elapsed. The circuit is closed if the
+	// trial request succeeds, or opened again if it fails.
+	CircuitHalfOpen CircuitState = "HalfOpen"
+)
+
+// CircuitBreakerStatus reports the circuit breaker for the requests to a hardware manager
+type CircuitBreakerStatus struct {
+	State CircuitState
+	// Failures is the number of consecutive failed requests
+	Failures int
+	// LastFailure describes the most recent failed request
+	LastFailure string
+	// RetryAt is the time an open circuit allows a trial request
+	RetryAt time.Time
+}
+
+// requestGuard bounds the rate and concurrency of the requests to a hardware manager, and suspends the requests when
+// they are consistently failing
+type requestGuard struct {
+	name    types.NamespacedName
+	uid     types.UID
+	limiter *rate.Limiter
+	now     func() time.Time
+
+	mu               sync.Mutex
+	slots            chan struct{}
+	failureThreshold int
+	openDuration     time.Duration
+	state            CircuitState
+	failures         int
+	lastFailure      string
+	retryAt          time.Time
+	probing          bool
+}
+
+// requestGuards holds the request guard for each HardwareManager. Unlike the cached client, the guard is kept when the
+// client is recreated, so the circuit breaker state is not reset by a change to the configuration.
+var requestGuards = struct {
+	sync.Mutex
+	guards map[types.NamespacedName]*requestGuard
+}{
+	guards: make(map[types.NamespacedName]*requestGuard),
+}
+
+// circuitEvents notifies the HardwareManager controller of changes to the state of a circuit breaker
+var circuitEvents = make(chan event.GenericEvent, 100)
+
+// CircuitBreakerEvents returns the channel notified with the HardwareManager whenever its circuit breaker is opened or
+// closed
+func CircuitBreakerEvents() <-chan event.GenericEvent {
+	return circuitEvents
+}
+
+func newRequestGuard(hwmgr *pluginv1alpha1.HardwareManager, now func() time.Time) *requestGuard {
+	return &requestGuard{
+		name:    types.NamespacedName{Namespace: hwmgr.Namespace, Name: hwmgr.Name},
+		uid:     hwmgr.UID,
+		limiter: rate.NewLimiter(defaultRequestsPerSecond, defaultBurst),
+		now:     now,
+		slots:   make(chan struct{}, defaultMaxConcurrentRequests),
+		state:   CircuitClosed,
+	}
+}
+
+// getRequestGuard returns the request guard for the HardwareManager, updated with its current configuration
+func getRequestGuard(hwmgr *pluginv1alpha1.HardwareManager) *requestGuard {
+	requestGuards.Lock()
+	defer requestGuards.Unlock()
+
+	name := types.NamespacedName{Namespace: hwmgr.Namespace, Name: hwmgr.Name}
+	guard, exists := requestGuards.guards[name]
+	if !exists || guard.uid != hwmgr.UID {
+		guard = newRequestGuard(hwmgr, time.Now)
+		requestGuards.guards[name] = guard
+	}
+
+	guard.configure(hwmgr.Spec.DellData)
+	return guard
+}
+
+// GetCircuitBreakerStatus returns the status of the circuit breaker for the requests to the HardwareManager
+func GetCircuitBreakerStatus(hwmgr *pluginv1alpha1.HardwareManager) CircuitBreakerStatus {
+	requestGuards.Lock()
+	guard, exists := requestGuards.guards[types.NamespacedName{Namespace: hwmgr.Namespace, Name: hwmgr.Name}]
+	requestGuards.Unlock()
+
+	if !exists || guard.uid != hwmgr.UID {
+		return CircuitBreakerStatus{State: CircuitClosed}
+	}
+	return guard.status()
+}
+
+func evictRequestGuard(name types.NamespacedName) {
+	requestGuards.Lock()
+	defer requestGuards.Unlock()
+
+	delete(requestGuards.guards, name)
+}
+
+// configure applies the rate limit and circuit breaker configuration of the HardwareManager, using the defaults for
+// any values that are not set
+func (g *requestGuard) configure(dellData *pluginv1alpha1.DellData) {
+	requestsPerSecond, burst, maxConcurrent := defaultRequestsPerSecond, defaultBurst, defaultMaxConcurrentRequests
+	if dellData.RateLimit != nil {
+		if dellData.RateLimit.RequestsPerSecond > 0 {
+			requestsPerSecond = int(dellData.RateLimit.RequestsPerSecond)
+		}
+		if dellData.RateLimit.Burst > 0 {
+			burst = int(dellData.RateLimit.Burst)
+		}
+		if dellData.RateLimit.MaxConcurrentRequests > 0 {
+			maxConcurrent = int(dellData.RateLimit.MaxConcurrentRequests)
+		}
+	}
+
+	failureThreshold, openDuration := defaultFailureThreshold, defaultOpenDuration
+	if dellData.CircuitBreaker != nil {
+		if dellData.CircuitBreaker.FailureThreshold > 0 {
+			failureThreshold = int(dellData.CircuitBreaker.FailureThreshold)
+		}
+		if dellData.CircuitBreaker.OpenDuration != nil && dellData.CircuitBreaker.OpenDuration.Duration > 0 {
+			openDuration = dellData.CircuitBreaker.OpenDuration.Duration
+		}
+	}
+
+	g.limiter.SetLimit(rate.Limit(requestsPerSecond))
+	g.limiter.SetBurst(burst)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if cap(g.slots) != maxConcurrent {
+		// Requests in flight release their slot to the previous channel
+		g.slots = make(chan struct{}, maxConcurrent)
+	}
+	g.failureThreshold = failureThreshold
+	g.openDuration = openDuration
+}
+
+func (g *requestGuard) status() CircuitBreakerStatus {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	status := CircuitBreakerStatus{
+		State:       g.state,
+		Failures:    g.failures,
+		LastFailure: g.lastFailure,
+		RetryAt:     g.retryAt,
+	}
+	if status.State == CircuitOpen && !g.now().Before(g.retryAt) {
+		status.State = CircuitHalfOpen
+	}
+	return status
+}
+
+// allow checks whether the circuit breaker allows a request, which is then the trial request if the circuit is
+// half-open
+func (g *requestGuard) allow() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch g.state {
+	case CircuitOpen:
+		if now := g.now(); now.Before(g.retryAt) {
+			return g.circuitOpenError(g.retryAt.Sub(now))
+		}
+		g.state = CircuitHalfOpen
+		g.probing = true
+	case CircuitHalfOpen:
+		if g.probing {
+			return g.circuitOpenError(circuitProbeInterval)
+		}
+		g.probing = true
+	}
+
+	return nil
+}
+
+func (g *requestGuard) circuitOpenError(retryAfter time.Duration) *HwmgrError {
+	return &HwmgrError{
+		Kind:       ErrorKindCircuitOpen,
+		Operation:  "request",
+		RetryAfter: retryAfter,
+		message: fmt.Sprintf("requests suspended after %d consecutive failures, last failure: %s",
+			g.failures, g.lastFailure),
+	}
+}
+
+// acquire waits for the rate limiter and a free request slot, returning the function to release the slot
+func (g *requestGuard) acquire(ctx context.Context) (func(), error) {
+	if err := g.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limit wait failed: %w", err)
+	}
+
+	g.mu.Lock()
+	slots := g.slots
+	g.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return sync.OnceFunc(func() { <-slots }), nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("request slot wait failed: %w", ctx.Err())
+	}
+}
+
+// abandon clears the trial request of a half-open circuit that was not sent, allowing another
+func (g *requestGuard) abandon() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.probing = false
+}
+
+// record updates the circuit breaker with the outcome of a request. Connection failures and responses with a transient
+// error status are counted as failures, while any other response resets the count. A request cancelled by the caller
+// is not counted.
+func (g *requestGuard) record(ctx context.Context, rsp *http.Response, err error) {
+	var failure string
+	switch {
+	case err != nil && ctx.Err() != nil:
+		g.abandon()
+		return
+	case err != nil:
+		failure = err.Error()
+	case (&HwmgrError{Kind: getErrorKind(rsp.StatusCode)}).Transient():
+		failure = fmt.Sprintf("%d %s", rsp.StatusCode, http.StatusText(rsp.StatusCode))
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.probing = false
+
+	if failure == "" {
+		if g.state != CircuitClosed {
+			g.state = CircuitClosed
+			g.notify()
+		}
+		g.failures = 0
+		return
+	}
+
+	g.failures++
+	g.lastFailure = failure
+	if g.state == CircuitHalfOpen || (g.state == CircuitClosed && g.failures >= g.failureThreshold) {
+		g.state = CircuitOpen
+		g.retryAt = g.now().Add(g.openDuration)
+		g.notify()
+	}
+}
+
+// notify queues an event for the HardwareManager. The event is dropped if the queue is full, as the controller
+// periodically reconciles the HardwareManager regardless.
+func (g *requestGuard) notify() {
+	select {
+	case circuitEvents <- event.GenericEvent{Object: &pluginv1alpha1.HardwareManager{
+		ObjectMeta: metav1.ObjectMeta{Namespace: g.name.Namespace, Name: g.name.Name},
+	}}:
+	default:
+	}
+}
+
+// guardTransport applies the request guard of the hardware manager to each request
+type guardTransport struct {
+	base  http.RoundTripper
+	guard *requestGuard
+}
+
+// releaseOnClose releases the request slot once the response body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *guardTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.guard.allow(); err != nil {
+		return nil, err
+	}
+
+	release, err := t.guard.acquire(req.Context())
+	if err != nil {
+		t.guard.abandon()
+		return nil, err
+	}
+
+	rsp, err := t.base.RoundTrip(req)
+	t.guard.record(req.Context(), rsp, err)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	rsp.Body = &releaseOnClose{ReadCloser: rsp.Body, release: release}
+	return rsp, nil
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/time/rate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+var _ = Describe("Hardware manager request guard", func() {
+	var (
+		ctx        context.Context
+		hwmgr      *pluginv1alpha1.HardwareManager
+		guard      *requestGuard
+		httpClient *http.Client
+		server     *httptest.Server
+		status     atomic.Int32
+		now        time.Time
+	)
+
+	get := func() (int, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		Expect(err).ToNot(HaveOccurred())
+		rsp, err := httpClient.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		defer rsp.Body.Close()
+		_, _ = io.Copy(io.Discard, rsp.Body)
+		return rsp.StatusCode, nil
+	}
+
+	drainEvents := func() int {
+		count := 0
+		for {
+			select {
+			case <-circuitEvents:
+				count++
+			default:
+				return count
+			}
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Now()
+		status.Store(http.StatusOK)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(int(status.Load()))
+		}))
+		DeferCleanup(server.Close)
+
+		hwmgr = &pluginv1alpha1.HardwareManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "dell-1", Namespace: "oran-hwmgr-plugin", UID: "uid-1"},
+			Spec: pluginv1alpha1.HardwareManagerSpec{
+				AdaptorID: pluginv1alpha1.SupportedAdaptors.Dell,
+				DellData: &pluginv1alpha1.DellData{
+					ApiUrl: server.URL,
+					CircuitBreaker: &pluginv1alpha1.CircuitBreaker{
+						FailureThreshold: 3,
+						OpenDuration:     &metav1.Duration{Duration: time.Minute},
+					},
+				},
+			},
+		}
+
+		guard = newRequestGuard(hwmgr, func() time.Time { return now })
+		guard.configure(hwmgr.Spec.DellData)
+		httpClient = &http.Client{Transport: &guardTransport{base: http.DefaultTransport, guard: guard}}
+		drainEvents()
+	})
+
+	It("applies the configured limits, defaulting any that are not set", func() {
+		Expect(guard.limiter.Limit()).To(Equal(rate.Limit(defaultRequestsPerSecond)))
+		Expect(guard.limiter.Burst()).To(Equal(defaultBurst))
+		Expect(cap(guard.slots)).To(Equal(defaultMaxConcurrentRequests))
+		Expect(guard.failureThreshold).To(Equal(3))
+
+		hwmgr.Spec.DellData.RateLimit = &pluginv1alpha1.RateLimit{RequestsPerSecond: 2, MaxConcurrentRequests: 1}
+		hwmgr.Spec.DellData.CircuitBreaker = nil
+		guard.configure(hwmgr.Spec.DellData)
+		Expect(guard.limiter.Limit()).To(Equal(rate.Limit(2)))
+		Expect(guard.limiter.Burst()).To(Equal(defaultBurst))
+		Expect(cap(guard.slots)).To(Equal(1))
+		Expect(guard.failureThreshold).To(Equal(defaultFailureThreshold))
+		Expect(guard.openDuration).To(Equal(defaultOpenDuration))
+	})
+
+	It("opens the circuit after consecutive failures and suspends requests", func() {
+		status.Store(http.StatusServiceUnavailable)
+		for i := 0; i < 2; i++ {
+			code, err := get()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(code).To(Equal(http.StatusServiceUnavailable))
+		}
+		Expect(guard.status().State).To(Equal(CircuitClosed))
+
+		// A client error is a response from a functioning hardware manager, which resets the count
+		status.Store(http.StatusNotFound)
+		_, err := get()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(guard.status().Failures).To(BeZero())
+
+		status.Store(http.StatusBadGateway)
+		for i := 0; i < 3; i++ {
+			_, err = get()
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		breaker := guard.status()
+		Expect(breaker.State).To(Equal(CircuitOpen))
+		Expect(breaker.Failures).To(Equal(3))
+		Expect(breaker.LastFailure).To(Equal("502 Bad Gateway"))
+		Expect(breaker.RetryAt).To(Equal(now.Add(time.Minute)))
+		Expect(drainEvents()).To(Equal(1))
+
+		_, err = get()
+		hwmgrErr := newRequestError("get resource pools", err)
+		Expect(hwmgrErr.Kind).To(Equal(ErrorKindCircuitOpen))
+		Expect(hwmgrErr.Operation).To(Equal("get resource pools"))
+		Expect(hwmgrErr.RetryAfter).To(Equal(time.Minute))
+		Expect(hwmgrErr.Transient()).To(BeTrue())
+		Expect(hwmgrErr.Error()).To(ContainSubstring("after 3 consecutive failures, last failure: 502 Bad Gateway"))
+	})
+
+	It("closes the circuit when the trial request succeeds", func() {
+		status.Store(http.StatusInternalServerError)
+		for i := 0; i < 3; i++ {
+			_, _ = get()
+		}
+		Expect(guard.status().State).To(Equal(CircuitOpen))
+		drainEvents()
+
+		// A failed trial request opens the circuit again
+		now = now.Add(time.Minute)
+		Expect(guard.status().State).To(Equal(CircuitHalfOpen))
+		_, err := get()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(guard.status().State).To(Equal(CircuitOpen))
+		Expect(guard.status().RetryAt).To(Equal(now.Add(time.Minute)))
+		Expect(drainEvents()).To(Equal(1))
+
+		now = now.Add(time.Minute)
+		status.Store(http.StatusOK)
+		code, err := get()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(code).To(Equal(http.StatusOK))
+
+		breaker := guard.status()
+		Expect(breaker.State).To(Equal(CircuitClosed))
+		Expect(breaker.Failures).To(BeZero())
+		Expect(drainEvents()).To(Equal(1))
+	})
+
+	It("allows a single trial request while the circuit is half-open", func() {
+		guard.state = CircuitHalfOpen
+		Expect(guard.allow()).To(Succeed())
+
+		hwmgrErr, ok := AsHwmgrError(guard.allow())
+		Expect(ok).To(BeTrue())
+		Expect(hwmgrErr.Kind).To(Equal(ErrorKindCircuitOpen))
+		Expect(hwmgrErr.RetryAfter).To(Equal(circuitProbeInterval))
+
+		// A trial request that was not sent allows another
+		guard.abandon()
+		Expect(guard.allow()).To(Succeed())
+	})
+
+	It("bounds the number of concurrent requests", func() {
+		hwmgr.Spec.DellData.RateLimit = &pluginv1alpha1.RateLimit{MaxConcurrentRequests: 2}
+		guard.configure(hwmgr.Spec.DellData)
+
+		var inFlight, maxInFlight atomic.Int32
+		release := make(chan struct{})
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				peak := maxInFlight.Load()
+				if current <= peak || maxInFlight.CompareAndSwap(peak, current) {
+					break
+				}
+			}
+			<-release
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := get()
+				Expect(err).ToNot(HaveOccurred())
+			}()
+		}
+
+		Eventually(inFlight.Load).Should(Equal(int32(2)))
+		Consistently(inFlight.Load, 200*time.Millisecond).Should(Equal(int32(2)))
+		close(release)
+		wg.Wait()
+		Expect(maxInFlight.Load()).To(Equal(int32(2)))
+	})
+
+	It("reports a closed circuit for an unknown HardwareManager", func() {
+		Expect(GetCircuitBreakerStatus(hwmgr).State).To(Equal(CircuitClosed))
+
+		registered := getRequestGuard(hwmgr)
+		DeferCleanup(evictRequestGuard, guard.name)
+		registered.state = CircuitOpen
+		registered.retryAt = time.Now().Add(time.Minute)
+		Expect(GetCircuitBreakerStatus(hwmgr).State).To(Equal(CircuitOpen))
+
+		// A recreated HardwareManager starts with a new guard
+		recreated := hwmgr.DeepCopy()
+		recreated.UID = "uid-2"
+		Expect(GetCircuitBreakerStatus(recreated).State).To(Equal(CircuitClosed))
+		Expect(getRequestGuard(recreated)).ToNot(BeIdenticalTo(registered))
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrclient
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+)
+
+// clientVersion identifies the configuration a cached client was created from. The cached client is replaced when any
+// of these change.
+type clientVersion struct {
+	uid               types.UID
+	generation        int64
+	authSecretVersion string
+	caBundleVersion   string
+	logMessages       bool
+}
+
+type cachedClient struct {
+	version clientVersion
+	client  *HardwareManagerClient
+}
+
+// clientCache holds the authenticated client for each HardwareManager, so the token is reused across reconciles
+var clientCache = struct {
+	sync.Mutex
+	clients map[types.NamespacedName]*cachedClient
+}{
+	clients: make(map[types.NamespacedName]*cachedClient),
+}
+
+func getClientVersion(hwmgr *pluginv1alpha1.HardwareManager, authSecret *corev1.Secret, caBundle *corev1.ConfigMap) clientVersion {
+	version := clientVersion{
+		uid:               hwmgr.UID,
+		generation:        hwmgr.Generation,
+		authSecretVersion: authSecret.ResourceVersion,
+		logMessages:       utils.IsHardwareManagerLogMessagesEnabled(hwmgr),
+	}
+	if caBundle != nil {
+		version.caBundleVersion = caBundle.ResourceVersion
+	}
+	return version
+}
+
+func getCachedClient(hwmgr *pluginv1alpha1.HardwareManager, version clientVersion) *HardwareManagerClient {
+	clientCache.Lock()
+	defer clientCache.Unlock()
+
+	cached, exists := clientCache.clients[types.NamespacedName{Namespace: hwmgr.Namespace, Name: hwmgr.Name}]
+	if !exists || cached.version != version {
+		return nil
+	}
+	return cached.client
+}
+
+func setCachedClient(hwmgr *pluginv1alpha1.HardwareManager, version clientVersion, client *HardwareManagerClient) {
+	clientCache.Lock()
+	defer clientCache.Unlock()
+
+	clientCache.clients[types.NamespacedName{Namespace: hwmgr.Namespace, Name: hwmgr.Name}] = &cachedClient{
+		version: version,
+		client:  client,
+	}
+}
+
+// EvictClient removes the cached client and request guard for a deleted HardwareManager
+func EvictClient(name types.NamespacedName) {
+	clientCache.Lock()
+	defer clientCache.Unlock()
+
+	delete(clientCache.clients, name)
+	evictRequestGuard(name)
+}
@@ -18,12 +18,11 @@ package hwmgrclient
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 
-	"github.com/oapi-codegen/oapi-codegen/v2/pkg/securityprovider"
 	hwmgrapi "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/generated"
 	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
@@ -35,6 +34,13 @@ import (
 const (
 	RoleKey       = "role"
 	DefaultTenant = "default_tenant"
+
+	// resourceGroupsPath is the location within a resource of the resource groups of which it is a member
+	resourceGroupsPath = "/Resource/Groups/Group"
+
+	// Keys of the auth secret
+	AuthSecretClientIdKey     = "client-id"
+	AuthSecretClientSecretKey = "client-secret"
 )
 
 type JobStatus int
@@ -53,6 +59,7 @@ type HardwareManagerClient struct {
 	Logger      *slog.Logger
 	Namespace   string
 	hwmgr       *pluginv1alpha1.HardwareManager
+	tokens      *tokenSource
 }
 
 // GetTenant gets the tenant parameter from the hwmgr configuration
@@ -64,120 +71,159 @@ func (c *HardwareManagerClient) GetTenant() string {
 	return DefaultTenant
 }
 
-// GetToken sends a request to the hardware manager to request an authentication token
+// GetToken returns the cached authentication token, requesting a new token from the hardware manager if needed
 func (c *HardwareManagerClient) GetToken(ctx context.Context) (string, error) {
-	clientSecrets, err := utils.GetSecret(ctx, c.rtclient, c.hwmgr.Spec.DellData.AuthSecret, c.Namespace)
-	if err != nil {
-		return "", fmt.Errorf("failed to get client secret: %w", err)
-	}
-
-	clientId, err := utils.GetSecretField(clientSecrets, "client-id")
-	if err != nil {
-		return "", fmt.Errorf("failed to get client-id from secret: %s, %w", c.hwmgr.Spec.DellData.AuthSecret, err)
-	}
+	return c.tokens.Token(ctx)
+}
 
-	username, err := utils.GetSecretField(clientSecrets, corev1.BasicAuthUsernameKey)
-	if err != nil {
-		return "", fmt.Errorf("failed to get %s from secret: %s, %w", corev1.BasicAuthUsernameKey, c.hwmgr.Spec.DellData.AuthSecret, err)
+// GetGrantType returns the OAuth grant type configured for the hardware manager, defaulting to the password grant
+func GetGrantType(dellData *pluginv1alpha1.DellData) pluginv1alpha1.OAuthGrantType {
+	if dellData.GrantType == "" {
+		return pluginv1alpha1.OAuthGrantTypes.Password
 	}
+	return dellData.GrantType
+}
 
-	password, err := utils.GetSecretField(clientSecrets, corev1.BasicAuthPasswordKey)
-	if err != nil {
-		return "", fmt.Errorf("failed to get %s from secret: %s, %w", corev1.BasicAuthPasswordKey, c.hwmgr.Spec.DellData.AuthSecret, err)
+// GetAuthSecretKeys returns the keys the auth secret must provide for the grant type
+func GetAuthSecretKeys(grantType pluginv1alpha1.OAuthGrantType) []string {
+	if grantType == pluginv1alpha1.OAuthGrantTypes.ClientCredentials {
+		return []string{AuthSecretClientIdKey, AuthSecretClientSecretKey}
 	}
+	return []string{AuthSecretClientIdKey, corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey}
+}
 
-	grant_type := string(pluginv1alpha1.OAuthGrantTypes.Password)
-
-	req := hwmgrapi.GetTokenJSONRequestBody{
-		ClientId:  &clientId,
-		Username:  &username,
-		Password:  &password,
-		GrantType: &grant_type,
+// ValidateAuthSecret checks that the auth secret provides the keys required by the configured grant type
+func ValidateAuthSecret(hwmgr *pluginv1alpha1.HardwareManager, authSecret *corev1.Secret) error {
+	grantType := GetGrantType(hwmgr.Spec.DellData)
+	for _, key := range GetAuthSecretKeys(grantType) {
+		if _, err := utils.GetSecretField(authSecret, key); err != nil {
+			return fmt.Errorf("failed to get %s from secret: %s, required for %s grant: %w", key, authSecret.Name, grantType, err)
+		}
 	}
+	return nil
+}
 
-	tokenrsp, err := c.HwmgrClient.GetTokenWithResponse(ctx, req)
-	if err != nil {
-		return "", fmt.Errorf("failed to get token: response: %v, err: %w", tokenrsp, err)
+// getOAuthClientConfig builds the OAuth client config from the HardwareManager and its auth secret
+func getOAuthClientConfig(hwmgr *pluginv1alpha1.HardwareManager, authSecret *corev1.Secret) (utils.OAuthClientConfig, error) {
+	if err := ValidateAuthSecret(hwmgr, authSecret); err != nil {
+		return utils.OAuthClientConfig{}, err
 	}
 
-	if tokenrsp.StatusCode() != http.StatusOK {
-		return "", fmt.Errorf("token request failed with status %s (%d), message=%s",
-			tokenrsp.Status(), tokenrsp.StatusCode(), string(tokenrsp.Body))
+	config := utils.OAuthClientConfig{
+		ClientId:  string(authSecret.Data[AuthSecretClientIdKey]),
+		TokenUrl:  hwmgr.Spec.DellData.TokenUrl,
+		Scopes:    hwmgr.Spec.DellData.Scopes,
+		GrantType: GetGrantType(hwmgr.Spec.DellData),
 	}
 
-	var tokenData hwmgrapi.RhprotoGetTokenResponseBody
-	if err := json.Unmarshal(tokenrsp.Body, &tokenData); err != nil {
-		return "", fmt.Errorf("failed to parse token: response: %v, err: %w", tokenrsp, err)
+	if config.GrantType == pluginv1alpha1.OAuthGrantTypes.ClientCredentials {
+		config.ClientSecret = string(authSecret.Data[AuthSecretClientSecretKey])
+	} else {
+		config.Username = string(authSecret.Data[corev1.BasicAuthUsernameKey])
+		config.Password = string(authSecret.Data[corev1.BasicAuthPasswordKey])
 	}
 
-	if tokenData.AccessToken == nil {
-		return "", fmt.Errorf("failed to get token: access_token field empty: %v", tokenrsp)
-	}
-	return *tokenData.AccessToken, nil
+	return config, nil
 }
 
-// NewClientWithResponses creates an authenticated client connected to the hardware manager
+// NewClientWithResponses returns an authenticated client connected to the hardware manager. The client is cached per
+// HardwareManager and reused until the HardwareManager spec, the auth secret or the CA bundle changes, so that the
+// token is only requested again when it is about to expire.
 func NewClientWithResponses(
 	ctx context.Context,
 	logger *slog.Logger,
 	rtclient client.Client,
 	hwmgr *pluginv1alpha1.HardwareManager) (*HardwareManagerClient, error) {
 
-	hwmgrClient := HardwareManagerClient{
-		rtclient:  rtclient,
-		Logger:    logger,
-		Namespace: hwmgr.Namespace,
-		hwmgr:     hwmgr,
+	authSecret, err := utils.GetSecret(ctx, rtclient, hwmgr.Spec.DellData.AuthSecret, hwmgr.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token for %s: failed to get client secret: %w", hwmgr.Name, err)
 	}
 
 	// If the HardwareManager CR includes certificates, get the bundle to add to the client
-	var caBundle string
+	var caBundleCm *corev1.ConfigMap
 	if hwmgr.Spec.DellData.CaBundleName != nil {
-		cm, err := utils.GetConfigmap(ctx, rtclient, *hwmgr.Spec.DellData.CaBundleName, hwmgr.Namespace)
+		caBundleCm, err = utils.GetConfigmap(ctx, rtclient, *hwmgr.Spec.DellData.CaBundleName, hwmgr.Namespace)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get configmap: %s", err.Error())
 		}
+	}
 
-		caBundle, err = utils.GetConfigMapField(cm, "ca-bundle.pem")
+	version := getClientVersion(hwmgr, authSecret, caBundleCm)
+	hwmgrClient := getCachedClient(hwmgr, version)
+	if hwmgrClient == nil {
+		hwmgrClient, err = newClient(logger, rtclient, hwmgr, authSecret, caBundleCm)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get certificate bundle from configmap: %s", err.Error())
+			return nil, err
 		}
+		setCachedClient(hwmgr, version, hwmgrClient)
 	}
 
-	config := utils.OAuthClientConfig{
-		CaBundle: []byte(caBundle),
+	if _, err := hwmgrClient.GetToken(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get token for %s: %w", hwmgr.Name, err)
 	}
 
-	tr, err := utils.GetTransportWithCaBundle(config, hwmgr.Spec.DellData.InsecureSkipTLSVerify, utils.IsHardwareManagerLogMessagesEnabled(hwmgr))
+	return hwmgrClient, nil
+}
+
+// newClient creates a client connected to the hardware manager, which adds the bearer token to each request
+func newClient(
+	logger *slog.Logger,
+	rtclient client.Client,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	authSecret *corev1.Secret,
+	caBundleCm *corev1.ConfigMap) (*HardwareManagerClient, error) {
+
+	config, err := getOAuthClientConfig(hwmgr, authSecret)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get http transport: %w", err)
+		return nil, fmt.Errorf("failed to get token for %s: %w", hwmgr.Name, err)
 	}
 
-	httpClient := &http.Client{Transport: tr}
+	if caBundleCm != nil {
+		caBundle, err := utils.GetConfigMapField(caBundleCm, "ca-bundle.pem")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get certificate bundle from configmap: %s", err.Error())
+		}
+		config.CaBundle = []byte(caBundle)
+	}
 
-	// Create the hwmgrapi client, along with a bearer token
-	hwmgrClient.HwmgrClient, err = hwmgrapi.NewClientWithResponses(
-		hwmgr.Spec.DellData.ApiUrl,
-		hwmgrapi.WithHTTPClient(httpClient))
+	tr, err := utils.GetTransportWithCaBundle(config, hwmgr.Spec.DellData.InsecureSkipTLSVerify, utils.IsHardwareManagerLogMessagesEnabled(hwmgr))
 	if err != nil {
-		return nil, fmt.Errorf("failed to setup client to %s: %w", hwmgr.Spec.DellData.ApiUrl, err)
+		return nil, fmt.Errorf("failed to get http transport: %w", err)
 	}
 
-	token, err := hwmgrClient.GetToken(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get token for %s: %w", hwmgr.Name, err)
+	// All requests, including token requests, are subject to the rate limit and circuit breaker of the hardware manager
+	guarded := &guardTransport{base: tr, guard: getRequestGuard(hwmgr)}
+
+	// Tokens are requested from the token API of the hardware manager, unless an OAuth token endpoint is configured
+	var requester tokenRequester
+	if config.TokenUrl != "" {
+		requester, err = newOAuthTokenRequester(&http.Client{Transport: guarded}, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup token client for %s: %w", hwmgr.Name, err)
+		}
+	} else {
+		tokenClient, err := hwmgrapi.NewClientWithResponses(
+			hwmgr.Spec.DellData.ApiUrl,
+			hwmgrapi.WithHTTPClient(&http.Client{Transport: guarded}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup client to %s: %w", hwmgr.Spec.DellData.ApiUrl, err)
+		}
+		requester = newHwmgrTokenRequester(tokenClient, config)
 	}
 
-	bearerAuth, err := securityprovider.NewSecurityProviderBearerToken(token)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create security provider for %s: %w", hwmgr.Name, err)
+	hwmgrClient := HardwareManagerClient{
+		rtclient:  rtclient,
+		Logger:    logger,
+		Namespace: hwmgr.Namespace,
+		hwmgr:     hwmgr.DeepCopy(),
+		tokens:    newTokenSource(requester, logger.With(slog.String("hwmgr", hwmgr.Name))),
 	}
 
-	// Create a new client with an intercept to add the bearer token
+	// Create the client with a transport to add the bearer token
 	hwmgrClient.HwmgrClient, err = hwmgrapi.NewClientWithResponses(
 		hwmgr.Spec.DellData.ApiUrl,
-		hwmgrapi.WithHTTPClient(httpClient),
-		hwmgrapi.WithRequestEditorFn(bearerAuth.Intercept))
+		hwmgrapi.WithHTTPClient(&http.Client{Transport: &authTransport{base: guarded, tokens: hwmgrClient.tokens}}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup auth client for %s: %w", hwmgr.Name, err)
 	}
@@ -191,19 +237,46 @@ func (c *HardwareManagerClient) GetResourceGroup(ctx context.Context, nodepool *
 	rgId := *rg.ResourceGroup.Id
 	tenant := c.GetTenant()
 
+	op := "get resource group " + rgId
+
 	response, err := c.HwmgrClient.GetResourceGroupWithResponse(ctx, tenant, rgId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get resource group %s: response: %v, err: %w", rgId, response, err)
+		return nil, newRequestError(op, err)
 	}
 
 	if response.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("resource group get failed with status %s (%d), message=%s",
-			response.Status(), response.StatusCode(), string(response.Body))
+		return nil, newResponseError(op, response.HTTPResponse, response.Body)
+	}
+
+	if response.JSON200 == nil {
+		return nil, newMalformedResponseError(op, "resource group missing from response")
 	}
 
 	return response.JSON200, nil
 }
 
+// ResourceGroupExists queries the hardware manager to check whether the resource group for the nodepool exists
+func (c *HardwareManagerClient) ResourceGroupExists(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) (bool, error) {
+	rgId := ResourceGroupIdFromNodePool(nodepool)
+	tenant := c.GetTenant()
+
+	op := "query for resource group " + rgId
+
+	response, err := c.HwmgrClient.GetResourceGroupWithResponse(ctx, tenant, rgId)
+	if err != nil {
+		return false, newRequestError(op, err)
+	}
+
+	switch response.StatusCode() {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, newResponseError(op, response.HTTPResponse, response.Body)
+	}
+}
+
 // ResourceGroupIdFromNodePool returns the resource group identifier corresponding to the specified nodepool
 func ResourceGroupIdFromNodePool(nodepool *hwmgmtv1alpha1.NodePool) string {
 	return fmt.Sprintf("rhplugin-rg-%s", nodepool.Spec.CloudID)
@@ -253,32 +326,34 @@ func (c *HardwareManagerClient) ResourceGroupFromNodePool(nodepool *hwmgmtv1alph
 }
 
 // CreateResourceGroup sends a request to the hardware manager, returns a jobId
-// TODO: Improve error handling for different status codes
 func (c *HardwareManagerClient) CreateResourceGroup(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) (string, error) {
 	rg := c.ResourceGroupFromNodePool(nodepool)
 	rgId := *rg.ResourceGroup.Id
 	tenant := c.GetTenant()
+	op := "create resource group " + rgId
 
 	// First check whether the resource group already exists
-	response, err := c.HwmgrClient.GetResourceGroupWithResponse(ctx, tenant, rgId)
+	exists, err := c.ResourceGroupExists(ctx, nodepool)
 	if err != nil {
-		return "", fmt.Errorf("failed to query for resource group %s: response: %v, err: %w", rgId, response, err)
+		return "", err
 	}
 
-	if response.StatusCode() == http.StatusOK {
-		return "", fmt.Errorf("resource group %s already exists", rgId)
+	if exists {
+		return "", &HwmgrError{Kind: ErrorKindConflict, Operation: op, message: "resource group already exists"}
 	}
 
 	// Send a request to the hardware manager to create the resource group
 	rgResponse, err := c.HwmgrClient.CreateResourceGroupWithResponse(ctx, tenant, *rg)
 	if err != nil {
-		return "", fmt.Errorf("failed to create resource group %s, api failure: response: %v, err: %w", rgId, response, err)
+		return "", newRequestError(op, err)
 	}
 
 	if rgResponse.StatusCode() != http.StatusOK {
-		// TODO: Remove this log
-		c.Logger.InfoContext(ctx, "Failure from CreateResourceGroupWithResponse", slog.String("message", *rgResponse.JSONDefault.Message), slog.Any("response", rgResponse.JSONDefault))
-		return "", fmt.Errorf("failed to create resource group %s, bad status: %s, code: %d, response: %v", rgId, rgResponse.Status(), rgResponse.StatusCode(), rgResponse)
+		return "", newResponseError(op, rgResponse.HTTPResponse, rgResponse.Body)
+	}
+
+	if rgResponse.JSON200 == nil || rgResponse.JSON200.Jobid == nil {
+		return "", newMalformedResponseError(op, "jobid missing from response")
 	}
 
 	// Return the job ID for the request
@@ -289,19 +364,21 @@ func (c *HardwareManagerClient) CreateResourceGroup(ctx context.Context, nodepoo
 func (c *HardwareManagerClient) CheckJobStatus(ctx context.Context, jobId string) (JobStatus, string, error) {
 	failReason := ""
 	tenant := c.GetTenant()
+	op := "query job status " + jobId
+
 	response, err := c.HwmgrClient.VerifyRequestStatusWithResponse(ctx, tenant, jobId)
 	if err != nil {
-		return JobStatusUnknown, failReason, fmt.Errorf("failed to query for job status: id: %s, response: %v, err: %w", jobId, response, err)
+		return JobStatusUnknown, failReason, newRequestError(op, err)
 	}
 
 	if response.StatusCode() != http.StatusOK {
-		return JobStatusUnknown, failReason, fmt.Errorf("job query failed for %s: %s", jobId, *response.JSONDefault.Message)
+		return JobStatusUnknown, failReason, newResponseError(op, response.HTTPResponse, response.Body)
 	}
 
 	status := response.JSON200
 	if status == nil || status.Brief == nil || status.Brief.Status == nil {
 		c.Logger.InfoContext(ctx, "Job progress check missing data", slog.Any("status", status))
-		return JobStatusUnknown, failReason, fmt.Errorf("job progress check missing data, jobId=%s: %w", jobId, err)
+		return JobStatusUnknown, failReason, newMalformedResponseError(op, "job progress check missing data")
 	}
 
 	// Process the status response
@@ -340,9 +417,19 @@ func (c *HardwareManagerClient) DeleteResourceGroup(ctx context.Context, nodepoo
 	rgId := ResourceGroupIdFromNodePool(nodepool)
 	tenant := c.GetTenant()
 
+	op := "delete resource group " + rgId
+
 	response, err := c.HwmgrClient.DeleteResourceGroupWithResponse(ctx, tenant, rgId)
-	if err != nil || response.StatusCode() != http.StatusOK {
-		return "", fmt.Errorf("failed to delete resource group %s: response: %v, err: %w", rgId, response, err)
+	if err != nil {
+		return "", newRequestError(op, err)
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		return "", newResponseError(op, response.HTTPResponse, response.Body)
+	}
+
+	if response.JSON200 == nil || response.JSON200.Jobid == nil {
+		return "", newMalformedResponseError(op, "jobid missing from response")
 	}
 
 	return *response.JSON200.Jobid, nil
@@ -352,30 +439,96 @@ func (c *HardwareManagerClient) DeleteResourceGroup(ctx context.Context, nodepoo
 func (c *HardwareManagerClient) GetResourcePools(ctx context.Context) (*hwmgrapi.ApiprotoResourcePoolsResp, error) {
 	tenant := c.GetTenant()
 	body := hwmgrapi.GetResourcePoolsJSONRequestBody{}
+	op := "get resource pools"
+
 	response, err := c.HwmgrClient.GetResourcePoolsWithResponse(ctx, tenant, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get resource pools: response: %v, err: %w", response, err)
+		return nil, newRequestError(op, err)
 	}
 
 	if response.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("resource pool get failed with status %s (%d), message=%s",
-			response.Status(), response.StatusCode(), string(response.Body))
+		return nil, newResponseError(op, response.HTTPResponse, response.Body)
+	}
+
+	if response.JSON200 == nil {
+		return nil, newMalformedResponseError(op, "resource pools missing from response")
 	}
 
 	return response.JSON200, nil
 }
 
+// GetResourcePool queries the hardware manager to get a resource pool, including its resources
+func (c *HardwareManagerClient) GetResourcePool(ctx context.Context, poolId string) (*hwmgrapi.ApiprotoResourcePool, error) {
+	tenant := c.GetTenant()
+	op := "get resource pool " + poolId
+
+	response, err := c.HwmgrClient.GetResourcePoolWithResponse(ctx, tenant, poolId)
+	if err != nil {
+		return nil, newRequestError(op, err)
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		return nil, newResponseError(op, response.HTTPResponse, response.Body)
+	}
+
+	if response.JSON200 == nil || response.JSON200.ResourcePool == nil {
+		return nil, newMalformedResponseError(op, "resource pool missing from response")
+	}
+
+	return response.JSON200.ResourcePool, nil
+}
+
+// IsResourceFree checks whether a resource is available for allocation, not being a member of any resource group
+func IsResourceFree(resource hwmgrapi.ApiprotoResource) bool {
+	return resource.Groups == nil || resource.Groups.Group == nil || len(*resource.Groups.Group) == 0
+}
+
+// GetFreeResources queries the hardware manager for the free resources of a resource pool with the role label of the
+// nodegroup, as matched by the resource selector of the nodegroup in a resource group
+func (c *HardwareManagerClient) GetFreeResources(ctx context.Context, poolId, role string) ([]hwmgrapi.ApiprotoResource, error) {
+	pool, err := c.GetResourcePool(ctx, poolId)
+	if err != nil {
+		return nil, err
+	}
+
+	var free []hwmgrapi.ApiprotoResource
+	if pool.Resources != nil {
+		for _, resource := range *pool.Resources {
+			if resource.Id != nil && IsResourceFree(resource) && hasLabel(resource.Labels, RoleKey, role) {
+				free = append(free, resource)
+			}
+		}
+	}
+
+	return free, nil
+}
+
+// hasLabel checks whether the labels include the key with the value
+func hasLabel(labels *[]hwmgrapi.ApiprotoLabel, key, value string) bool {
+	if labels == nil {
+		return false
+	}
+	return slices.ContainsFunc(*labels, func(label hwmgrapi.ApiprotoLabel) bool {
+		return label.Key != nil && *label.Key == key && label.Value != nil && *label.Value == value
+	})
+}
+
 // GetSecret queries the hardware manager to get the Secret data
 func (c *HardwareManagerClient) GetSecret(ctx context.Context, secretKey string) (*hwmgrapi.RhprotoGetSecretsResponseBody, error) {
 	tenant := c.GetTenant()
+	op := "get secret " + secretKey
+
 	response, err := c.HwmgrClient.GetSecretsWithResponse(ctx, tenant, secretKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get secret %s: response: %v, err: %w", secretKey, response, err)
+		return nil, newRequestError(op, err)
 	}
 
 	if response.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("get secret failed with status %s (%d), message=%s",
-			response.Status(), response.StatusCode(), string(response.Body))
+		return nil, newResponseError(op, response.HTTPResponse, response.Body)
+	}
+
+	if response.JSON200 == nil {
+		return nil, newMalformedResponseError(op, "secret missing from response")
 	}
 
 	return response.JSON200, nil
@@ -392,7 +545,14 @@ func (c *HardwareManagerClient) ValidateResourceGroup(
 		for _, nodegroup := range nodepool.Spec.NodeGroup {
 			nodegroupName := nodegroup.NodePoolData.Name
 			if resource, exists := resourceSelector[nodegroupName]; exists {
-				if resource.NumResources != nil {
+				if resource.Resources != nil {
+					// Ensure expected number of nodes are present. A resource group resized by scaling the nodegroup
+					// retains its original number of resources, so its members are counted.
+					if nodegroup.Size != len(*resource.Resources) {
+						return fmt.Errorf("invalid num of resources for node %s\n expected: %d found: %d",
+							nodegroupName, nodegroup.Size, len(*resource.Resources))
+					}
+				} else if resource.NumResources != nil {
 					// Ensure expected number of nodes are present
 					if float32(nodegroup.Size) != *resource.NumResources {
 						return fmt.Errorf("invalid num of resources for node %s\n expected: %f found: %f",
@@ -425,14 +585,19 @@ func (c *HardwareManagerClient) ValidateResourceGroup(
 // GetResource queries the hardware manager to get the resource data
 func (c *HardwareManagerClient) GetResource(ctx context.Context, node *hwmgmtv1alpha1.Node) (*hwmgrapi.ApiprotoGetResourceResp, error) {
 	tenant := c.GetTenant()
+	op := "get resource " + node.Spec.HwMgrNodeId
+
 	response, err := c.HwmgrClient.GetResourceWithResponse(ctx, tenant, node.Spec.HwMgrNodeId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get resource: response: %v, err: %w", response, err)
+		return nil, newRequestError(op, err)
 	}
 
 	if response.StatusCode() != http.StatusOK {
-		return nil, fmt.Errorf("resource get failed with status %s (%d), message=%s",
-			response.Status(), response.StatusCode(), string(response.Body))
+		return nil, newResponseError(op, response.HTTPResponse, response.Body)
+	}
+
+	if response.JSON200 == nil {
+		return nil, newMalformedResponseError(op, "resource missing from response")
 	}
 
 	return response.JSON200, nil
@@ -440,29 +605,70 @@ func (c *HardwareManagerClient) GetResource(ctx context.Context, node *hwmgmtv1a
 
 // UpdateResourceProfile sends a request to update the resource profile for a node
 func (c *HardwareManagerClient) UpdateResourceProfile(ctx context.Context, node *hwmgmtv1alpha1.Node, newHwProfile string) (string, error) {
-	tenant := c.GetTenant()
-
-	op := "replace"
+	patchOp := "replace"
 	path := "/Resource/ResourceProfileID"
 	value := []map[string]interface{}{{"resourceProfileID": newHwProfile}}
+	update := hwmgrapi.ApiprotoUpdateResource{
+		Op:    &patchOp,
+		Path:  &path,
+		Value: &value,
+	}
+	op := "update resource profile of " + node.Spec.HwMgrNodeId
+
+	return c.updateResource(ctx, op, node.Spec.HwMgrNodeId, update)
+}
+
+// AddResourceToGroup sends a request to add a free resource to the resource group of the nodepool, returns a jobId
+func (c *HardwareManagerClient) AddResourceToGroup(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, resourceId string) (string, error) {
+	rgId := ResourceGroupIdFromNodePool(nodepool)
+	op := fmt.Sprintf("add resource %s to resource group %s", resourceId, rgId)
+
+	return c.updateResource(ctx, op, resourceId, resourceGroupMembershipUpdate("add", rgId))
+}
+
+// RemoveResourceFromGroup sends a request to release a resource from the resource group of the nodepool, returns a jobId
+func (c *HardwareManagerClient) RemoveResourceFromGroup(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, resourceId string) (string, error) {
+	rgId := ResourceGroupIdFromNodePool(nodepool)
+	op := fmt.Sprintf("remove resource %s from resource group %s", resourceId, rgId)
+
+	return c.updateResource(ctx, op, resourceId, resourceGroupMembershipUpdate("remove", rgId))
+}
+
+// resourceGroupMembershipUpdate builds the update of the resource groups of which a resource is a member
+func resourceGroupMembershipUpdate(patchOp, rgId string) hwmgrapi.ApiprotoUpdateResource {
+	path := resourceGroupsPath
+	value := []map[string]interface{}{{"group": rgId}}
+	return hwmgrapi.ApiprotoUpdateResource{
+		Op:    &patchOp,
+		Path:  &path,
+		Value: &value,
+	}
+}
+
+// updateResource sends a request to apply an update to a resource, returns a jobId
+func (c *HardwareManagerClient) updateResource(
+	ctx context.Context,
+	op string,
+	resourceId string,
+	update hwmgrapi.ApiprotoUpdateResource) (string, error) {
+
+	tenant := c.GetTenant()
 	body := hwmgrapi.UpdateResourceJSONRequestBody{
-		ResourceName: &node.Spec.HwMgrNodeId,
-		Resource: &[]hwmgrapi.ApiprotoUpdateResource{
-			{
-				Op:    &op,
-				Path:  &path,
-				Value: &value,
-			},
-		},
+		ResourceName: &resourceId,
+		Resource:     &[]hwmgrapi.ApiprotoUpdateResource{update},
 	}
+
 	response, err := c.HwmgrClient.UpdateResourceWithResponse(ctx, tenant, body)
 	if err != nil {
-		return "", fmt.Errorf("failed to get resource: response: %v, err: %w", response, err)
+		return "", newRequestError(op, err)
 	}
 
 	if response.StatusCode() != http.StatusOK {
-		return "", fmt.Errorf("resource get failed with status %s (%d), message=%s",
-			response.Status(), response.StatusCode(), string(response.Body))
+		return "", newResponseError(op, response.HTTPResponse, response.Body)
+	}
+
+	if response.JSON200 == nil || response.JSON200.Response == nil || response.JSON200.Response.Jobid == nil {
+		return "", newMalformedResponseError(op, "jobid missing from response")
 	}
 
 	return *response.JSON200.Response.Jobid, nil
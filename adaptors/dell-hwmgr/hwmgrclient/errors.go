@@ -0,0 +1,304 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	hwmgrapi "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/generated"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+)
+
+// ErrorKind classifies a failed request to the hardware manager
+type ErrorKind string
+
+const (
+	// ErrorKindUnreachable is a failure to connect to the hardware manager, or a request that timed out
+	ErrorKindUnreachable ErrorKind = "Unreachable"
+	// ErrorKindTLS is a failure to establish a TLS connection, such as an untrusted certificate
+	ErrorKindTLS ErrorKind = "TLSError"
+	// ErrorKindUnauthorized is a rejected token, or a failure to acquire one with the configured credentials
+	ErrorKindUnauthorized ErrorKind = "Unauthorized"
+	// ErrorKindForbidden is a request the credentials are not permitted to make
+	ErrorKindForbidden ErrorKind = "Forbidden"
+	// ErrorKindNotFound is a request for a resource that does not exist
+	ErrorKindNotFound ErrorKind = "NotFound"
+	// ErrorKindConflict is a request that conflicts with the current state of a resource
+	ErrorKindConflict ErrorKind = "Conflict"
+	// ErrorKindRateLimited is a request rejected by the rate limiting of the hardware manager
+	ErrorKindRateLimited ErrorKind = "RateLimited"
+	// ErrorKindServerError is an internal failure of the hardware manager, or of a gateway in front of it
+	ErrorKindServerError ErrorKind = "ServerError"
+	// ErrorKindMalformedResponse is a response that is missing expected data or cannot be parsed
+	ErrorKindMalformedResponse ErrorKind = "MalformedResponse"
+	// ErrorKindInvalidRequest is a request rejected by the hardware manager for any other reason
+	ErrorKindInvalidRequest ErrorKind = "InvalidRequest"
+	// ErrorKindCircuitOpen is a request that was not sent, as requests are suspended by the open circuit breaker
+	ErrorKindCircuitOpen ErrorKind = "CircuitOpen"
+)
+
+// HwmgrError is a failed request to the hardware manager
+type HwmgrError struct {
+	Kind ErrorKind
+	// Operation describes the failed request
+	Operation string
+	// StatusCode is the HTTP status of the response, or zero if no response was received
+	StatusCode int
+	// RpcStatus holds the error details from the response body, if provided
+	RpcStatus *hwmgrapi.RhprotoGooglerpcStatus
+	// RetryAfter is the delay requested by the Retry-After header of a rate limited or unavailable response
+	RetryAfter time.Duration
+
+	message string
+	err     error
+}
+
+func (e *HwmgrError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s failed: %s (%d): %s", e.Operation, e.Kind, e.StatusCode, e.message)
+	}
+	return fmt.Sprintf("%s failed: %s: %s", e.Operation, e.Kind, e.message)
+}
+
+func (e *HwmgrError) Unwrap() error {
+	return e.err
+}
+
+// Transient checks whether the request may succeed if retried without any change to the configuration
+func (e *HwmgrError) Transient() bool {
+	switch e.Kind {
+	case ErrorKindUnreachable, ErrorKindRateLimited, ErrorKindServerError, ErrorKindCircuitOpen:
+		return true
+	default:
+		return false
+	}
+}
+
+// RetryDelay returns the delay requested by the hardware manager before retrying the request
+func (e *HwmgrError) RetryDelay() time.Duration {
+	return e.RetryAfter
+}
+
+// HwmgrError is classified by the adaptor controller as a retriable error
+var _ utils.RetriableError = (*HwmgrError)(nil)
+
+// AsHwmgrError returns the HwmgrError in the chain of the error, if any
+func AsHwmgrError(err error) (*HwmgrError, bool) {
+	var hwmgrErr *HwmgrError
+	if errors.As(err, &hwmgrErr) {
+		return hwmgrErr, true
+	}
+	return nil, false
+}
+
+// IsTransient checks whether the error is a failed hardware manager request that may succeed if retried. Errors that
+// are not from a hardware manager request are not considered transient.
+func IsTransient(err error) bool {
+	hwmgrErr, ok := AsHwmgrError(err)
+	return ok && hwmgrErr.Transient()
+}
+
+// IsConfigurationError checks whether the error is a failed hardware manager request caused by the credentials or TLS
+// configuration of the HardwareManager, which affects every request until the configuration is corrected
+func IsConfigurationError(err error) bool {
+	hwmgrErr, ok := AsHwmgrError(err)
+	return ok && (hwmgrErr.Kind == ErrorKindUnauthorized || hwmgrErr.Kind == ErrorKindTLS)
+}
+
+// GetConditionReason returns the condition reason reporting the error, which is the kind of a failed hardware manager
+// request
+func GetConditionReason(err error) pluginv1alpha1.ConditionReason {
+	if hwmgrErr, ok := AsHwmgrError(err); ok {
+		return pluginv1alpha1.ConditionReason(hwmgrErr.Kind)
+	}
+	return pluginv1alpha1.ConditionReasons.Failed
+}
+
+// GetRequeue returns the requeue after a failed hardware manager request. A transient failure is retried after the
+// delay requested by the hardware manager, or a short interval, while a permanent failure is retried at a long
+// interval, as it requires a change to the configuration.
+func GetRequeue(err error) ctrl.Result {
+	hwmgrErr, ok := AsHwmgrError(err)
+	switch {
+	case !ok:
+		return utils.RequeueWithMediumInterval()
+	case hwmgrErr.RetryAfter > 0:
+		return utils.RequeueWithCustomInterval(hwmgrErr.RetryAfter)
+	case hwmgrErr.Transient():
+		return utils.RequeueWithShortInterval()
+	default:
+		return utils.RequeueWithLongInterval()
+	}
+}
+
+// newRequestError creates an HwmgrError for a request that received no response. An HwmgrError already in the chain,
+// such as a failure to acquire a token, is returned as is, other than a request rejected by the circuit breaker, which
+// is reported for the operation.
+func newRequestError(operation string, err error) *HwmgrError {
+	if hwmgrErr, ok := AsHwmgrError(err); ok {
+		if hwmgrErr.Kind == ErrorKindCircuitOpen {
+			circuitErr := *hwmgrErr
+			circuitErr.Operation = operation
+			return &circuitErr
+		}
+		return hwmgrErr
+	}
+
+	kind := ErrorKindUnreachable
+	var (
+		unknownAuthorityErr x509.UnknownAuthorityError
+		hostnameErr         x509.HostnameError
+		certInvalidErr      x509.CertificateInvalidError
+		verificationErr     *tls.CertificateVerificationError
+		recordHeaderErr     tls.RecordHeaderError
+	)
+	if errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &certInvalidErr) ||
+		errors.As(err, &verificationErr) || errors.As(err, &recordHeaderErr) {
+		kind = ErrorKindTLS
+	}
+
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) && retrieveErr.Response != nil {
+		return newResponseError(operation, retrieveErr.Response, retrieveErr.Body)
+	}
+
+	return &HwmgrError{
+		Kind:      kind,
+		Operation: operation,
+		message:   err.Error(),
+		err:       err,
+	}
+}
+
+// newResponseError creates an HwmgrError for a response with an unexpected status
+func newResponseError(operation string, rsp *http.Response, body []byte) *HwmgrError {
+	hwmgrErr := &HwmgrError{
+		Kind:       getErrorKind(rsp.StatusCode),
+		Operation:  operation,
+		StatusCode: rsp.StatusCode,
+		RpcStatus:  parseRpcStatus(body),
+		message:    string(body),
+	}
+
+	if hwmgrErr.RpcStatus != nil && hwmgrErr.RpcStatus.Message != nil && *hwmgrErr.RpcStatus.Message != "" {
+		hwmgrErr.message = *hwmgrErr.RpcStatus.Message
+	}
+
+	if hwmgrErr.Kind == ErrorKindRateLimited || rsp.StatusCode == http.StatusServiceUnavailable {
+		hwmgrErr.RetryAfter = parseRetryAfter(rsp.Header.Get("Retry-After"), time.Now())
+	}
+
+	return hwmgrErr
+}
+
+// newMalformedResponseError creates an HwmgrError for a response that is missing expected data
+func newMalformedResponseError(operation string, format string, args ...interface{}) *HwmgrError {
+	err := fmt.Errorf(format, args...)
+	return &HwmgrError{
+		Kind:       ErrorKindMalformedResponse,
+		Operation:  operation,
+		StatusCode: http.StatusOK,
+		message:    err.Error(),
+		err:        err,
+	}
+}
+
+// getErrorKind classifies a response by its HTTP status
+func getErrorKind(statusCode int) ErrorKind {
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		return ErrorKindUnauthorized
+	case statusCode == http.StatusForbidden:
+		return ErrorKindForbidden
+	case statusCode == http.StatusNotFound:
+		return ErrorKindNotFound
+	case statusCode == http.StatusConflict:
+		return ErrorKindConflict
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorKindRateLimited
+	case statusCode == http.StatusRequestTimeout:
+		return ErrorKindUnreachable
+	case statusCode >= http.StatusInternalServerError:
+		return ErrorKindServerError
+	case statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices:
+		// A successful status with an unexpected body
+		return ErrorKindMalformedResponse
+	default:
+		return ErrorKindInvalidRequest
+	}
+}
+
+// parseRpcStatus parses the error details from a response body. The hardware manager APIs provide the details either
+// as an object or a list, so a list is returned under the "details" key.
+func parseRpcStatus(body []byte) *hwmgrapi.RhprotoGooglerpcStatus {
+	var raw struct {
+		Code    *int32          `json:"code"`
+		Message *string         `json:"message"`
+		Details json.RawMessage `json:"details"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil || (raw.Code == nil && raw.Message == nil) {
+		return nil
+	}
+
+	status := &hwmgrapi.RhprotoGooglerpcStatus{
+		Code:    raw.Code,
+		Message: raw.Message,
+	}
+
+	if len(raw.Details) > 0 {
+		details := make(map[string]interface{})
+		if err := json.Unmarshal(raw.Details, &details); err != nil {
+			var list []interface{}
+			if err := json.Unmarshal(raw.Details, &list); err == nil {
+				details = map[string]interface{}{"details": list}
+			}
+		}
+		status.Details = &details
+	}
+
+	return status
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrclient
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+)
+
+var _ = Describe("Hardware manager errors", func() {
+	newResponse := func(statusCode int, header http.Header) *http.Response {
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{StatusCode: statusCode, Header: header}
+	}
+
+	DescribeTable("classifies responses by status",
+		func(statusCode int, kind ErrorKind, transient bool) {
+			err := newResponseError("get resource group rg-1", newResponse(statusCode, nil), nil)
+			Expect(err.Kind).To(Equal(kind))
+			Expect(err.StatusCode).To(Equal(statusCode))
+			Expect(err.Transient()).To(Equal(transient))
+		},
+		Entry("unauthorized", http.StatusUnauthorized, ErrorKindUnauthorized, false),
+		Entry("forbidden", http.StatusForbidden, ErrorKindForbidden, false),
+		Entry("not found", http.StatusNotFound, ErrorKindNotFound, false),
+		Entry("conflict", http.StatusConflict, ErrorKindConflict, false),
+		Entry("rate limited", http.StatusTooManyRequests, ErrorKindRateLimited, true),
+		Entry("request timeout", http.StatusRequestTimeout, ErrorKindUnreachable, true),
+		Entry("internal error", http.StatusInternalServerError, ErrorKindServerError, true),
+		Entry("bad gateway", http.StatusBadGateway, ErrorKindServerError, true),
+		Entry("bad request", http.StatusBadRequest, ErrorKindInvalidRequest, false),
+	)
+
+	It("carries the error details of the response", func() {
+		body := []byte(`{"code":5,"message":"resource group not found","details":{"domain":"RM","reason":"NotFound"}}`)
+		err := newResponseError("get resource group rg-1", newResponse(http.StatusNotFound, nil), body)
+		Expect(err.RpcStatus).NotTo(BeNil())
+		Expect(err.RpcStatus.Code).To(HaveValue(BeEquivalentTo(5)))
+		Expect(err.RpcStatus.Details).To(HaveValue(HaveKeyWithValue("reason", "NotFound")))
+		Expect(err.Error()).To(Equal("get resource group rg-1 failed: NotFound (404): resource group not found"))
+
+		body = []byte(`{"code":3,"message":"invalid tenant","details":[{"@type":"type.googleapis.com/google.rpc.BadRequest"}]}`)
+		err = newResponseError("get resource pools", newResponse(http.StatusBadRequest, nil), body)
+		Expect(err.RpcStatus.Details).To(HaveValue(HaveKey("details")))
+		Expect(err.Error()).To(ContainSubstring("invalid tenant"))
+
+		err = newResponseError("get resource pools", newResponse(http.StatusBadGateway, nil), []byte("<html>bad gateway</html>"))
+		Expect(err.RpcStatus).To(BeNil())
+		Expect(err.Error()).To(ContainSubstring("<html>bad gateway</html>"))
+	})
+
+	It("honours the Retry-After of a rate limited response", func() {
+		err := newResponseError("get resource pools",
+			newResponse(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"20"}}), nil)
+		Expect(err.RetryAfter).To(Equal(20 * time.Second))
+		Expect(GetRequeue(err).RequeueAfter).To(Equal(20 * time.Second))
+
+		// The adaptor controller honours the delay when retrying the wrapped error
+		wrapped := fmt.Errorf("failed to get resource pools: %w", err)
+		Expect(utils.IsTransientError(wrapped)).To(BeTrue())
+		Expect(utils.GetRetryDelay(wrapped)).To(Equal(20 * time.Second))
+
+		now := time.Date(2024, 11, 1, 12, 0, 0, 0, time.UTC)
+		Expect(parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)).To(Equal(time.Minute))
+		Expect(parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now)).To(BeZero())
+		Expect(parseRetryAfter("soon", now)).To(BeZero())
+	})
+
+	It("classifies requests that received no response", func() {
+		connErr := &url.Error{Op: "Post", URL: "https://hwmgr.example.com", Err: errors.New("connection refused")}
+		err := newRequestError("get resource pools", connErr)
+		Expect(err.Kind).To(Equal(ErrorKindUnreachable))
+		Expect(err.Transient()).To(BeTrue())
+		Expect(errors.Is(err, connErr)).To(BeTrue())
+
+		tlsErr := &url.Error{Op: "Post", URL: "https://hwmgr.example.com", Err: x509.UnknownAuthorityError{}}
+		err = newRequestError("get resource pools", tlsErr)
+		Expect(err.Kind).To(Equal(ErrorKindTLS))
+		Expect(IsConfigurationError(err)).To(BeTrue())
+
+		// A failure to acquire a token keeps its classification
+		tokenErr := newResponseError(tokenOperation, newResponse(http.StatusUnauthorized, nil), nil)
+		err = newRequestError("get resource pools", &url.Error{Op: "Post", URL: "https://hwmgr.example.com", Err: tokenErr})
+		Expect(err).To(BeIdenticalTo(tokenErr))
+	})
+
+	It("maps errors to condition reasons and requeues", func() {
+		err := fmt.Errorf("failed to get token for dell-1: %w",
+			newResponseError(tokenOperation, newResponse(http.StatusUnauthorized, nil), nil))
+		Expect(GetConditionReason(err)).To(Equal(pluginv1alpha1.ConditionReason("Unauthorized")))
+		Expect(GetRequeue(err).RequeueAfter).To(Equal(5 * time.Minute))
+		Expect(IsTransient(err)).To(BeFalse())
+
+		err = newResponseError("get resource pools", newResponse(http.StatusServiceUnavailable, nil), nil)
+		Expect(GetConditionReason(err)).To(Equal(pluginv1alpha1.ConditionReason("ServerError")))
+		Expect(GetRequeue(err).RequeueAfter).To(Equal(15 * time.Second))
+		Expect(IsTransient(err)).To(BeTrue())
+
+		err = errors.New("failed to get configmap")
+		Expect(GetConditionReason(err)).To(Equal(pluginv1alpha1.ConditionReasons.Failed))
+		Expect(IsTransient(err)).To(BeFalse())
+		Expect(utils.IsTransientError(err)).To(BeFalse())
+	})
+})
@@ -0,0 +1,181 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	hwmgrapi "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/generated"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+const (
+	// NotificationEventTypeJob is a change to the status of a job
+	NotificationEventTypeJob = "Job"
+	// NotificationEventTypeResource is a change to the state of a subscribed resource
+	NotificationEventTypeResource = "Resource"
+)
+
+// Notification is the body of a notification pushed by the hardware manager to the callback of a resource subscription
+type Notification struct {
+	SubscriptionId string              `json:"SubscriptionId"`
+	Events         []NotificationEvent `json:"Events"`
+}
+
+// NotificationEvent is a change to a job or subscribed resource of the tenant
+type NotificationEvent struct {
+	Type string `json:"Type"`
+	// JobId is the job whose status changed, for a job event
+	JobId string `json:"JobId,omitempty"`
+	// ResourceId is the resource whose state changed, for a resource event
+	ResourceId string `json:"ResourceId,omitempty"`
+	// Status is the new status of the job or resource
+	Status string `json:"Status,omitempty"`
+}
+
+// subscriptionRequest is the body of a request to subscribe resources. The generated SubscribeResourcesJSONBody does not
+// include the callback the notifications are pushed to.
+type subscriptionRequest struct {
+	Id        string   `json:"Id"`
+	Resources []string `json:"Resources"`
+	Callback  string   `json:"Callback,omitempty"`
+}
+
+// GetSubscriptionId returns the ID of the resource subscription of the HardwareManager
+func GetSubscriptionId(hwmgr *pluginv1alpha1.HardwareManager) string {
+	return "oran-hwmgr-plugin-" + string(hwmgr.UID)
+}
+
+// GetSubscribedResources queries the hardware manager for the resources of the subscription, returning false if the
+// subscription does not exist
+func (c *HardwareManagerClient) GetSubscribedResources(ctx context.Context, id string) ([]string, bool, error) {
+	tenant := c.GetTenant()
+	op := "get resource subscription " + id
+
+	response, err := c.HwmgrClient.GetResourceSubscriptionWithResponse(ctx, tenant, id)
+	if err != nil {
+		return nil, false, newRequestError(op, err)
+	}
+
+	if response.StatusCode() == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		return nil, false, newResponseError(op, response.HTTPResponse, response.Body)
+	}
+
+	if response.JSON200 == nil {
+		return nil, false, newMalformedResponseError(op, "resource subscription missing from response")
+	}
+
+	if response.JSON200.ResourceSubscription == nil || len(*response.JSON200.ResourceSubscription) == 0 {
+		return nil, false, nil
+	}
+
+	resources := []string{}
+	for _, subscription := range *response.JSON200.ResourceSubscription {
+		if subscription.Resources == nil {
+			continue
+		}
+		for _, resource := range *subscription.Resources {
+			if resource.Resource != nil {
+				resources = append(resources, *resource.Resource)
+			}
+		}
+	}
+
+	return resources, true, nil
+}
+
+// SubscribeResources adds resources to the subscription, creating the subscription if it does not exist. The callback
+// is updated on each request, so that a change to the callback URL is applied to an existing subscription.
+func (c *HardwareManagerClient) SubscribeResources(ctx context.Context, id, callback string, resources []string) error {
+	tenant := c.GetTenant()
+	op := "subscribe resources for " + id
+
+	body, err := json.Marshal(subscriptionRequest{
+		Id:        id,
+		Resources: resources,
+		Callback:  callback,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode subscription request: %w", err)
+	}
+
+	response, err := c.HwmgrClient.SubscribeResourcesWithBodyWithResponse(ctx, tenant, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return newRequestError(op, err)
+	}
+
+	if response.StatusCode() != http.StatusOK {
+		return newResponseError(op, response.HTTPResponse, response.Body)
+	}
+
+	return nil
+}
+
+// UnsubscribeResources removes resources from the subscription. If no resources are given, the subscription is deleted.
+func (c *HardwareManagerClient) UnsubscribeResources(ctx context.Context, id string, resources []string) error {
+	tenant := c.GetTenant()
+	op := "unsubscribe resources for " + id
+
+	body := hwmgrapi.UnsubscribeResourcesJSONRequestBody{Id: &id}
+	if len(resources) > 0 {
+		body.Resources = &resources
+	}
+
+	response, err := c.HwmgrClient.UnsubscribeResourcesWithResponse(ctx, tenant, body)
+	if err != nil {
+		return newRequestError(op, err)
+	}
+
+	if response.StatusCode() != http.StatusOK && response.StatusCode() != http.StatusNotFound {
+		return newResponseError(op, response.HTTPResponse, response.Body)
+	}
+
+	return nil
+}
+
+// ReconcileSubscription ensures that the subscription exists with the callback, covering exactly the given resources
+func (c *HardwareManagerClient) ReconcileSubscription(ctx context.Context, id, callback string, resources []string) error {
+	subscribed, _, err := c.GetSubscribedResources(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := c.SubscribeResources(ctx, id, callback, resources); err != nil {
+		return err
+	}
+
+	var removed []string
+	for _, resource := range subscribed {
+		if !slices.Contains(resources, resource) {
+			removed = append(removed, resource)
+		}
+	}
+	if len(removed) > 0 {
+		return c.UnsubscribeResources(ctx, id, removed)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hwmgrapi "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/generated"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	dellserver "github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/dell-hwmgr/dell-server"
+	apiserver "github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/dell-hwmgr/dell-server/generated"
+)
+
+var _ = Describe("Resource subscriptions", func() {
+	var (
+		ctx           context.Context
+		ds            *dellserver.DellServer
+		hwmgrClient   *HardwareManagerClient
+		id            string
+		callback      string
+		mu            sync.Mutex
+		notifications []Notification
+	)
+
+	received := func() []Notification {
+		mu.Lock()
+		defer mu.Unlock()
+		return notifications
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		notifications = nil
+
+		ds = dellserver.NewDellServer()
+		server := httptest.NewServer(apiserver.HandlerWithOptions(ds, apiserver.GorillaServerOptions{}))
+		DeferCleanup(server.Close)
+
+		receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var notification Notification
+			Expect(json.NewDecoder(r.Body).Decode(&notification)).To(Succeed())
+			mu.Lock()
+			notifications = append(notifications, notification)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		DeferCleanup(receiver.Close)
+		callback = receiver.URL + "/notifications/dell-hwmgr/dell-1"
+
+		apiClient, err := hwmgrapi.NewClientWithResponses(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+
+		hwmgr := &pluginv1alpha1.HardwareManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "dell-1", Namespace: "oran-hwmgr-plugin", UID: "uid-1"},
+			Spec: pluginv1alpha1.HardwareManagerSpec{
+				AdaptorID: pluginv1alpha1.SupportedAdaptors.Dell,
+				DellData:  &pluginv1alpha1.DellData{ApiUrl: server.URL},
+			},
+		}
+		hwmgrClient = &HardwareManagerClient{HwmgrClient: apiClient, hwmgr: hwmgr}
+		id = GetSubscriptionId(hwmgr)
+	})
+
+	It("reports a missing subscription", func() {
+		resources, exists, err := hwmgrClient.GetSubscribedResources(ctx, id)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+		Expect(resources).To(BeEmpty())
+
+		// Deleting a missing subscription succeeds
+		Expect(hwmgrClient.UnsubscribeResources(ctx, id, nil)).To(Succeed())
+	})
+
+	It("reconciles the subscribed resources", func() {
+		Expect(hwmgrClient.ReconcileSubscription(ctx, id, callback, []string{"node-1", "node-2"})).To(Succeed())
+		resources, exists, err := hwmgrClient.GetSubscribedResources(ctx, id)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeTrue())
+		Expect(resources).To(ConsistOf("node-1", "node-2"))
+
+		Expect(hwmgrClient.ReconcileSubscription(ctx, id, callback, []string{"node-2", "node-3"})).To(Succeed())
+		resources, _, err = hwmgrClient.GetSubscribedResources(ctx, id)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources).To(ConsistOf("node-2", "node-3"))
+
+		Expect(hwmgrClient.UnsubscribeResources(ctx, id, nil)).To(Succeed())
+		_, exists, err = hwmgrClient.GetSubscribedResources(ctx, id)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("receives the notifications for the jobs of the tenant and the subscribed resources", func() {
+		Expect(hwmgrClient.ReconcileSubscription(ctx, id, callback, []string{"node-1"})).To(Succeed())
+
+		Expect(ds.EmitResourceNotification("node-2", "Failed")).To(Succeed())
+		Expect(received()).To(BeEmpty())
+
+		Expect(ds.EmitResourceNotification("node-1", "Failed")).To(Succeed())
+		Expect(ds.EmitJobNotification(DefaultTenant, "job-1", "Completed")).To(Succeed())
+		Expect(ds.EmitJobNotification("other-tenant", "job-2", "Completed")).To(Succeed())
+
+		Expect(received()).To(Equal([]Notification{
+			{
+				SubscriptionId: id,
+				Events:         []NotificationEvent{{Type: NotificationEventTypeResource, ResourceId: "node-1", Status: "Failed"}},
+			},
+			{
+				SubscriptionId: id,
+				Events:         []NotificationEvent{{Type: NotificationEventTypeJob, JobId: "job-1", Status: "Completed"}},
+			},
+		}))
+	})
+})
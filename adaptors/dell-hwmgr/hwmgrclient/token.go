@@ -0,0 +1,279 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	hwmgrapi "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/generated"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+)
+
+const (
+	// tokenRefreshMargin is how long before its expiry a cached token is proactively refreshed
+	tokenRefreshMargin = 30 * time.Second
+
+	grantTypeRefreshToken = "refresh_token"
+
+	tokenOperation = "request token"
+)
+
+// tokenRequest is the body of a request to the token API of the hardware manager. The generated
+// GetTokenJSONRequestBody covers only the password grant.
+type tokenRequest struct {
+	ClientId     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	GrantType    string `json:"grant_type"`
+	Username     string `json:"username,omitempty"`
+	Password     string `json:"password,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// tokenRequester requests a new token, using the refresh token if one is provided
+type tokenRequester func(ctx context.Context, refreshToken string) (*hwmgrapi.RhprotoGetTokenResponseBody, error)
+
+// newHwmgrTokenRequester returns a tokenRequester for the token API of the hardware manager
+func newHwmgrTokenRequester(client *hwmgrapi.ClientWithResponses, config utils.OAuthClientConfig) tokenRequester {
+	return func(ctx context.Context, refreshToken string) (*hwmgrapi.RhprotoGetTokenResponseBody, error) {
+		req := tokenRequest{
+			ClientId:     config.ClientId,
+			ClientSecret: config.ClientSecret,
+			GrantType:    string(config.GrantType),
+			Scope:        strings.Join(config.Scopes, " "),
+		}
+		switch {
+		case refreshToken != "":
+			req.GrantType = grantTypeRefreshToken
+			req.RefreshToken = refreshToken
+		case config.GrantType == pluginv1alpha1.OAuthGrantTypes.Password:
+			req.Username = config.Username
+			req.Password = config.Password
+		}
+
+		body, err := json.Marshal(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode token request: %w", err)
+		}
+
+		tokenrsp, err := client.GetTokenWithBodyWithResponse(ctx, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return nil, newRequestError(tokenOperation, err)
+		}
+
+		if tokenrsp.StatusCode() != http.StatusOK {
+			return nil, newResponseError(tokenOperation, tokenrsp.HTTPResponse, tokenrsp.Body)
+		}
+
+		var tokenData hwmgrapi.RhprotoGetTokenResponseBody
+		if err := json.Unmarshal(tokenrsp.Body, &tokenData); err != nil {
+			return nil, newMalformedResponseError(tokenOperation, "failed to parse token: %w", err)
+		}
+
+		return &tokenData, nil
+	}
+}
+
+// newOAuthTokenRequester returns a tokenRequester for the OAuth token endpoint at the token URL of the config. The
+// refresh token is not used, as a new token is requested with the credentials instead.
+func newOAuthTokenRequester(httpClient *http.Client, config utils.OAuthClientConfig) (tokenRequester, error) {
+	tokenConfig, err := utils.GetOAuthTokenConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oauth config: %w", err)
+	}
+
+	return func(ctx context.Context, _ string) (*hwmgrapi.RhprotoGetTokenResponseBody, error) {
+		token, err := tokenConfig.Token(context.WithValue(ctx, oauth2.HTTPClient, httpClient))
+		if err != nil {
+			return nil, newRequestError(tokenOperation+" from "+config.TokenUrl, err)
+		}
+
+		tokenData := hwmgrapi.RhprotoGetTokenResponseBody{
+			AccessToken: &token.AccessToken,
+			TokenType:   &token.TokenType,
+		}
+		if !token.Expiry.IsZero() {
+			expiresIn := int64(time.Until(token.Expiry).Seconds())
+			tokenData.ExpiresIn = &expiresIn
+		}
+
+		return &tokenData, nil
+	}, nil
+}
+
+// tokenSource caches the access token for a hardware manager, tracking its lifetime. A new token is requested, using
+// the refresh token when available, shortly before the cached token expires.
+type tokenSource struct {
+	mu            sync.Mutex
+	request       tokenRequester
+	logger        *slog.Logger
+	now           func() time.Time
+	accessToken   string
+	refreshAt     time.Time
+	refreshToken  string
+	refreshExpiry time.Time
+}
+
+func newTokenSource(request tokenRequester, logger *slog.Logger) *tokenSource {
+	return &tokenSource{
+		request: request,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Token returns the cached access token, requesting a new one if there is no token or it is about to expire
+func (t *tokenSource) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if t.accessToken != "" && (t.refreshAt.IsZero() || now.Before(t.refreshAt)) {
+		return t.accessToken, nil
+	}
+
+	if t.refreshToken != "" && (t.refreshExpiry.IsZero() || now.Before(t.refreshExpiry)) {
+		err := t.requestToken(ctx, t.refreshToken)
+		if err == nil {
+			return t.accessToken, nil
+		}
+
+		// Fall back to requesting a new token with the credentials
+		t.logger.InfoContext(ctx, "Failed to refresh token", slog.String("error", err.Error()))
+	}
+
+	if err := t.requestToken(ctx, ""); err != nil {
+		return "", err
+	}
+
+	return t.accessToken, nil
+}
+
+// Invalidate discards the cached access token if it matches the specified token, which has been rejected by the
+// hardware manager. The token may already have been replaced by a concurrent request.
+func (t *tokenSource) Invalidate(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.accessToken == token {
+		t.accessToken = ""
+		t.refreshAt = time.Time{}
+	}
+}
+
+// requestToken requests a new token and caches the returned tokens. The caller must hold the lock.
+func (t *tokenSource) requestToken(ctx context.Context, refreshToken string) error {
+	issued := t.now()
+
+	tokenData, err := t.request(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	if tokenData.AccessToken == nil || *tokenData.AccessToken == "" {
+		return newMalformedResponseError(tokenOperation, "access_token field empty")
+	}
+
+	t.accessToken = *tokenData.AccessToken
+
+	// A token without an expiry is used until it is rejected by the hardware manager
+	t.refreshAt = time.Time{}
+	if tokenData.ExpiresIn != nil && *tokenData.ExpiresIn > 0 {
+		lifetime := time.Duration(*tokenData.ExpiresIn) * time.Second
+		t.refreshAt = issued.Add(lifetime - min(tokenRefreshMargin, lifetime/5))
+	}
+
+	t.refreshToken = ""
+	t.refreshExpiry = time.Time{}
+	if tokenData.RefreshToken != nil {
+		t.refreshToken = *tokenData.RefreshToken
+		if tokenData.RefreshExpiresIn != nil && *tokenData.RefreshExpiresIn > 0 {
+			t.refreshExpiry = issued.Add(time.Duration(*tokenData.RefreshExpiresIn) * time.Second)
+		}
+	}
+
+	t.logger.InfoContext(ctx, "Acquired hardware manager token", slog.Time("refreshAt", t.refreshAt))
+	return nil
+}
+
+// authTransport adds the bearer token to each request to the hardware manager. If the token is rejected, a new token
+// is requested and the request is retried once.
+type authTransport struct {
+	base   http.RoundTripper
+	tokens *tokenSource
+}
+
+func withBearerToken(req *http.Request, token string) *http.Request {
+	authReq := req.Clone(req.Context())
+	authReq.Header.Set("Authorization", "Bearer "+token)
+	return authReq
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.tokens.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := t.base.RoundTrip(withBearerToken(req, token))
+	if err != nil || rsp.StatusCode != http.StatusUnauthorized {
+		return rsp, err
+	}
+
+	if req.Body != nil && req.GetBody == nil {
+		// The request body cannot be replayed
+		return rsp, nil
+	}
+
+	// Buffer the rejected response, so it is returned intact if the retry is not possible, and close the body to free
+	// its request slot for the token request
+	body, err := io.ReadAll(rsp.Body)
+	rsp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	rsp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// The token may have been revoked before its expiry, so re-authenticate and retry
+	t.tokens.Invalidate(token)
+	token, err = t.tokens.Token(req.Context())
+	if err != nil {
+		return rsp, nil
+	}
+
+	retry := withBearerToken(req, token)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return rsp, nil
+		}
+	}
+
+	return t.base.RoundTrip(retry)
+}
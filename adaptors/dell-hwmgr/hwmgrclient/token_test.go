@@ -0,0 +1,281 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+func TestHwmgrClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Hardware Manager Client Suite")
+}
+
+// fakeHardwareManager serves the token and resource pool endpoints of the hardware manager API
+type fakeHardwareManager struct {
+	mu             sync.Mutex
+	issued         int
+	validToken     string
+	expiresIn      int64
+	passwordGrants int
+	clientGrants   int
+	refreshGrants  int
+	poolRequests   int
+}
+
+func (f *fakeHardwareManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.URL.Path {
+	case "/identity/v1/tenant/Fulcrum/token/create":
+		var req map[string]string
+		Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+		switch req["grant_type"] {
+		case grantTypeRefreshToken:
+			Expect(req["refresh_token"]).To(Equal(fmt.Sprintf("refresh-%d", f.issued)))
+			f.refreshGrants++
+		case string(pluginv1alpha1.OAuthGrantTypes.ClientCredentials):
+			Expect(req["client_secret"]).To(Equal("client-secret"))
+			Expect(req).NotTo(HaveKey("password"))
+			f.clientGrants++
+		default:
+			Expect(req["password"]).To(Equal("secret"))
+			f.passwordGrants++
+		}
+		f.issued++
+		f.validToken = fmt.Sprintf("token-%d", f.issued)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":       f.validToken,
+			"expires_in":         f.expiresIn,
+			"refresh_token":      fmt.Sprintf("refresh-%d", f.issued),
+			"refresh_expires_in": 3600,
+		})
+	case "/v1/tenants/default_tenant/search/resourcepools":
+		f.poolRequests++
+		var req map[string]any
+		Expect(json.NewDecoder(r.Body).Decode(&req)).To(Succeed())
+		if r.Header.Get("Authorization") != "Bearer "+f.validToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":401,"message":"invalid token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	case "/oauth/token":
+		// A standard OAuth token endpoint, with a form encoded request
+		Expect(r.ParseForm()).To(Succeed())
+		Expect(r.PostForm.Get("grant_type")).To(Equal(string(pluginv1alpha1.OAuthGrantTypes.ClientCredentials)))
+		Expect(r.PostForm.Get("client_secret")).To(Equal("client-secret"))
+		Expect(r.PostForm.Get("scope")).To(Equal("hwmgr.read hwmgr.write"))
+		f.clientGrants++
+		f.issued++
+		f.validToken = fmt.Sprintf("token-%d", f.issued)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": f.validToken,
+			"token_type":   "Bearer",
+			"expires_in":   f.expiresIn,
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeHardwareManager) counts() (int, int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.passwordGrants, f.refreshGrants, f.poolRequests
+}
+
+var _ = Describe("Hardware manager client cache", func() {
+	var (
+		ctx      context.Context
+		fakeHwmr *fakeHardwareManager
+		server   *httptest.Server
+		c        client.Client
+		hwmgr    *pluginv1alpha1.HardwareManager
+		logger   *slog.Logger
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		logger = slog.New(slog.NewTextHandler(GinkgoWriter, nil))
+
+		fakeHwmr = &fakeHardwareManager{expiresIn: 300}
+		server = httptest.NewServer(fakeHwmr)
+		DeferCleanup(server.Close)
+
+		hwmgr = &pluginv1alpha1.HardwareManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "dell-1", Namespace: "default", UID: "uid-1", Generation: 1},
+			Spec: pluginv1alpha1.HardwareManagerSpec{
+				AdaptorID: pluginv1alpha1.SupportedAdaptors.Dell,
+				DellData: &pluginv1alpha1.DellData{
+					ApiUrl:                server.URL,
+					AuthSecret:            "dell-1",
+					InsecureSkipTLSVerify: true,
+				},
+			},
+		}
+		DeferCleanup(EvictClient, types.NamespacedName{Namespace: hwmgr.Namespace, Name: hwmgr.Name})
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "dell-1", Namespace: "default"},
+			Data: map[string][]byte{
+				"client-id":                 []byte("myclient"),
+				corev1.BasicAuthUsernameKey: []byte("admin"),
+				corev1.BasicAuthPasswordKey: []byte("secret"),
+				AuthSecretClientSecretKey:   []byte("client-secret"),
+			},
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(pluginv1alpha1.AddToScheme(scheme)).To(Succeed())
+		c = fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	})
+
+	It("reuses the cached token across reconciles", func() {
+		for range 3 {
+			hwmgrClient, err := NewClientWithResponses(ctx, logger, c, hwmgr)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = hwmgrClient.GetResourcePools(ctx)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		passwordGrants, refreshGrants, poolRequests := fakeHwmr.counts()
+		Expect(passwordGrants).To(Equal(1))
+		Expect(refreshGrants).To(Equal(0))
+		Expect(poolRequests).To(Equal(3))
+	})
+
+	It("refreshes the token before it expires", func() {
+		hwmgrClient, err := NewClientWithResponses(ctx, logger, c, hwmgr)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Within the refresh margin of the 300s token lifetime
+		hwmgrClient.tokens.now = func() time.Time { return time.Now().Add(280 * time.Second) }
+
+		token, err := hwmgrClient.GetToken(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token).To(Equal("token-2"))
+
+		passwordGrants, refreshGrants, _ := fakeHwmr.counts()
+		Expect(passwordGrants).To(Equal(1))
+		Expect(refreshGrants).To(Equal(1))
+	})
+
+	It("creates a new client when the HardwareManager or auth secret changes", func() {
+		first, err := NewClientWithResponses(ctx, logger, c, hwmgr)
+		Expect(err).NotTo(HaveOccurred())
+
+		hwmgr.Generation++
+		second, err := NewClientWithResponses(ctx, logger, c, hwmgr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second).NotTo(BeIdenticalTo(first))
+
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "dell-1"}, secret)).To(Succeed())
+		secret.Data[corev1.BasicAuthUsernameKey] = []byte("operator")
+		Expect(c.Update(ctx, secret)).To(Succeed())
+
+		third, err := NewClientWithResponses(ctx, logger, c, hwmgr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(third).NotTo(BeIdenticalTo(second))
+
+		passwordGrants, _, _ := fakeHwmr.counts()
+		Expect(passwordGrants).To(Equal(3))
+	})
+
+	It("re-authenticates and retries once when the token is rejected", func() {
+		hwmgrClient, err := NewClientWithResponses(ctx, logger, c, hwmgr)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Revoke the cached token
+		fakeHwmr.mu.Lock()
+		fakeHwmr.validToken = "revoked"
+		fakeHwmr.mu.Unlock()
+
+		_, err = hwmgrClient.GetResourcePools(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		passwordGrants, refreshGrants, poolRequests := fakeHwmr.counts()
+		Expect(passwordGrants + refreshGrants).To(Equal(2))
+		Expect(poolRequests).To(Equal(2))
+	})
+
+	It("requests a token from the hardware manager with the client_credentials grant", func() {
+		hwmgr.Spec.DellData.GrantType = pluginv1alpha1.OAuthGrantTypes.ClientCredentials
+
+		hwmgrClient, err := NewClientWithResponses(ctx, logger, c, hwmgr)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = hwmgrClient.GetResourcePools(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeHwmr.mu.Lock()
+		defer fakeHwmr.mu.Unlock()
+		Expect(fakeHwmr.clientGrants).To(Equal(1))
+		Expect(fakeHwmr.passwordGrants).To(Equal(0))
+	})
+
+	It("requests a token from the configured OAuth token endpoint", func() {
+		hwmgr.Spec.DellData.GrantType = pluginv1alpha1.OAuthGrantTypes.ClientCredentials
+		hwmgr.Spec.DellData.TokenUrl = server.URL + "/oauth/token"
+		hwmgr.Spec.DellData.Scopes = []string{"hwmgr.read", "hwmgr.write"}
+
+		hwmgrClient, err := NewClientWithResponses(ctx, logger, c, hwmgr)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = hwmgrClient.GetResourcePools(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeHwmr.mu.Lock()
+		defer fakeHwmr.mu.Unlock()
+		Expect(fakeHwmr.clientGrants).To(Equal(1))
+		Expect(fakeHwmr.passwordGrants).To(Equal(0))
+	})
+
+	It("rejects an auth secret missing the keys for the grant type", func() {
+		secret := &corev1.Secret{}
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "dell-1"}, secret)).To(Succeed())
+		delete(secret.Data, AuthSecretClientSecretKey)
+		Expect(c.Update(ctx, secret)).To(Succeed())
+
+		hwmgr.Spec.DellData.GrantType = pluginv1alpha1.OAuthGrantTypes.ClientCredentials
+		_, err := NewClientWithResponses(ctx, logger, c, hwmgr)
+		Expect(err).To(MatchError(ContainSubstring("failed to get client-secret from secret")))
+	})
+})
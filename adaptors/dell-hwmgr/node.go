@@ -31,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -99,6 +100,65 @@ func (a *Adaptor) AllocateNode(
 	return nodename, nil
 }
 
+// AdoptNode reconciles a Node CR created for the NodePool by a previous attempt at processing the resource group,
+// ensuring its bmc-secret and status are complete. A Node CR belonging to a different NodePool is not adopted.
+func (a *Adaptor) AdoptNode(
+	ctx context.Context,
+	hwmgrClient *hwmgrclient.HardwareManagerClient,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	nodename string,
+	resource hwmgrapi.RhprotoResource) error {
+	ctx = logging.AppendCtx(ctx, slog.String("nodename", nodename))
+
+	node, err := utils.GetNode(ctx, a.Logger, a.Client, a.Namespace, nodename)
+	if err != nil {
+		return fmt.Errorf("failed to get node %s: %w", nodename, err)
+	}
+
+	if node.Spec.NodePool != nodepool.Name {
+		return fmt.Errorf("node %s is allocated to nodepool %s", nodename, node.Spec.NodePool)
+	}
+
+	a.Logger.InfoContext(ctx, "Adopting node from previous attempt")
+
+	if err := a.CreateBMCSecret(ctx, hwmgrClient, nodepool, nodename, resource); err != nil {
+		return fmt.Errorf("failed to create bmc-secret when adopting node %s: %w", nodename, err)
+	}
+
+	if err := a.SetInitialNodeStatus(ctx, nodename, resource); err != nil {
+		return fmt.Errorf("failed to update node status (%s): %w", *resource.Id, err)
+	}
+
+	return nil
+}
+
+// DeleteNode deletes the Node CR and bmc-secret of a node, if present
+func (a *Adaptor) DeleteNode(ctx context.Context, nodename string) error {
+	a.Logger.InfoContext(ctx, "Deleting node", slog.String("nodename", nodename))
+
+	node := &hwmgmtv1alpha1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodename,
+			Namespace: a.Namespace,
+		},
+	}
+	if err := a.Client.Delete(ctx, node); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete node %s: %w", nodename, err)
+	}
+
+	bmcSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bmcSecretName(nodename),
+			Namespace: a.Namespace,
+		},
+	}
+	if err := a.Client.Delete(ctx, bmcSecret); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete bmc-secret for node %s: %w", nodename, err)
+	}
+
+	return nil
+}
+
 // parseExtensionInterfaces parses interface data from the Extensions object in the resource
 func (a *Adaptor) parseExtensionInterfaces(resource hwmgrapi.RhprotoResource) ([]ExtensionInterface, error) {
 	if resource.Extensions == nil {
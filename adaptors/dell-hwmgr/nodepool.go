@@ -18,24 +18,31 @@ package dellhwmgr
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
+	"strings"
 	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	hwmgrapi "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/generated"
 	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/hwmgrclient"
 	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/logging"
 	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 // ValidateNodePool performs basic validation of the nodepool data
 func (a *Adaptor) ValidateNodePool(nodepool *hwmgmtv1alpha1.NodePool) error {
+	if errs := utils.ValidateNodePoolSpec(nodepool); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
 	return nil
 }
 
@@ -63,11 +70,12 @@ func (a *Adaptor) HandleNodePoolCreate(
 		return utils.DoNotRequeue(), nil
 	}
 
-	if err := a.ProcessNewNodePool(ctx, hwmgrClient, hwmgr, nodepool); err != nil {
-		a.Logger.Error("failed createNodePool", "err", err)
+	processErr := a.ProcessNewNodePool(ctx, hwmgrClient, hwmgr, nodepool)
+	if processErr != nil {
+		a.Logger.Error("failed createNodePool", "err", processErr)
 		conditionReason = hwmgmtv1alpha1.Failed
 		conditionStatus = metav1.ConditionFalse
-		message = "Creation request failed: " + err.Error()
+		message = "Creation request failed: " + processErr.Error()
 	} else {
 		conditionReason = hwmgmtv1alpha1.InProgress
 		conditionStatus = metav1.ConditionFalse
@@ -86,6 +94,11 @@ func (a *Adaptor) HandleNodePoolCreate(
 			fmt.Errorf("failed to update hwMgrPlugin observedGeneration for NodePool %s: Status: %w",
 				nodepool.Name, err)
 	}
+
+	if hwmgrclient.IsTransient(processErr) {
+		// The request may succeed if reissued, once the hardware manager recovers
+		return utils.DoNotRequeue(), processErr
+	}
 	return utils.DoNotRequeue(), nil
 }
 
@@ -97,6 +110,17 @@ func (a *Adaptor) ProcessNewNodePool(ctx context.Context,
 
 	a.Logger.InfoContext(ctx, "Processing ProcessNewNodePool request")
 
+	// The resource group may already exist from a previous attempt, in which case it is reconciled by the processing
+	// of the NodePool
+	exists, err := hwmgrClient.ResourceGroupExists(ctx, nodepool)
+	if err != nil {
+		return fmt.Errorf("failed to check for resource group: %w", err)
+	}
+	if exists {
+		a.Logger.InfoContext(ctx, "Resource group already exists")
+		return nil
+	}
+
 	jobId, err := hwmgrClient.CreateResourceGroup(ctx, nodepool)
 	if err != nil {
 		return fmt.Errorf("failed CreateResourceGroup: %w", err)
@@ -125,7 +149,21 @@ func (a *Adaptor) HandleNodePoolProcessing(
 
 	jobId := utils.GetJobId(nodepool)
 	if jobId == "" {
-		return result, fmt.Errorf("jobId annotation is missing or empty from nodepool %s", nodepool.Name)
+		// There is no job in progress when the resource group already existed, or when the NodePool is being retried
+		// after a failed or timed out job
+		exists, err := hwmgrClient.ResourceGroupExists(ctx, nodepool)
+		if err != nil {
+			return result, fmt.Errorf("failed to check for resource group: %w", err)
+		}
+		if !exists {
+			a.Logger.InfoContext(ctx, "Resource group not found, reissuing creation request")
+			if err := a.ProcessNewNodePool(ctx, hwmgrClient, hwmgr, nodepool); err != nil {
+				return result, fmt.Errorf("failed to reissue creation request for nodepool %s: %w", nodepool.Name, err)
+			}
+			return jobPollRequeue(hwmgr), nil
+		}
+
+		return a.reconcileResourceGroup(ctx, hwmgrClient, nodepool)
 	}
 
 	ctx = logging.AppendCtx(ctx, slog.String("jobId", jobId))
@@ -140,9 +178,16 @@ func (a *Adaptor) HandleNodePoolProcessing(
 	// Process the status response
 	switch status {
 	case hwmgrclient.JobStatusInProgress:
-		return utils.RequeueWithShortInterval(), nil
+		return jobPollRequeue(hwmgr), nil
 	case hwmgrclient.JobStatusFailed:
 		a.Logger.InfoContext(ctx, "Resource group creation failed", slog.String("failReason", failReason))
+
+		// Clear the jobId so that the creation is reissued if the NodePool is retried
+		utils.ClearJobId(nodepool)
+		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to clear annotation from nodepool %s: %w", nodepool.Name, err)
+		}
+
 		if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
 			hwmgmtv1alpha1.Provisioned, hwmgmtv1alpha1.Failed, metav1.ConditionFalse,
 			fmt.Sprintf("Resource group creation failed: %s", failReason)); err != nil {
@@ -157,7 +202,17 @@ func (a *Adaptor) HandleNodePoolProcessing(
 		return result, fmt.Errorf("failed to check job progress, jobId=%s: %s", jobId, failReason)
 	}
 
-	// The job has completed. Get the resource group data from the hardware manager
+	return a.reconcileResourceGroup(ctx, hwmgrClient, nodepool)
+}
+
+// reconcileResourceGroup queries the hardware manager for the resource group of a NodePool, creating the Node CRs
+// corresponding to the allocated nodes. Node CRs left over from a previous attempt for the same NodePool are adopted.
+func (a *Adaptor) reconcileResourceGroup(
+	ctx context.Context,
+	hwmgrClient *hwmgrclient.HardwareManagerClient,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	// Get the resource group data from the hardware manager
 	rg, err := hwmgrClient.GetResourceGroup(ctx, nodepool)
 	if err != nil {
 		a.Logger.InfoContext(ctx, "Failed GetResourceGroup", slog.String("error", err.Error()))
@@ -169,7 +224,10 @@ func (a *Adaptor) HandleNodePoolProcessing(
 				fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
 		}
 
-		return utils.DoNotRequeue(), nil
+		if !hwmgrclient.IsTransient(err) {
+			return utils.DoNotRequeue(), nil
+		}
+		return utils.DoNotRequeue(), fmt.Errorf("failed to get resource group: %w", err)
 	}
 
 	a.Logger.InfoContext(ctx, fmt.Sprintf("Validating ResourceGroup %s with nodepool %s", *rg.Id, nodepool.Name))
@@ -202,9 +260,11 @@ func (a *Adaptor) HandleNodePoolProcessing(
 						slog.String("nodename", nodename),
 						slog.String("nodeId", *node.Id))
 					continue
-				} else {
-					// TODO: Validate that the CR is current. For now, fail, as something went wrong
+				}
+
+				if err := a.AdoptNode(ctx, hwmgrClient, nodepool, nodename, node); err != nil {
 					a.Logger.InfoContext(ctx, "Node previously allocated, but not in nodepool properties",
+						slog.String("error", err.Error()),
 						slog.String("nodename", nodename),
 						slog.String("nodeId", *node.Id))
 					if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
@@ -216,6 +276,9 @@ func (a *Adaptor) HandleNodePoolProcessing(
 
 					return utils.DoNotRequeue(), nil
 				}
+
+				nodepool.Status.Properties.NodeNames = append(nodepool.Status.Properties.NodeNames, nodename)
+				continue
 			}
 			if nodename, err := a.AllocateNode(ctx, hwmgrClient, nodepool, node, nodegroupName); err != nil {
 				a.Logger.InfoContext(ctx, "Failed allocating node", slog.String("err", err.Error()))
@@ -252,162 +315,441 @@ func (a *Adaptor) HandleNodePoolProcessing(
 		return ctrl.Result{}, fmt.Errorf("failed to clear annotation from nodepool %s: %w", nodepool.Name, err)
 	}
 
-	result = utils.DoNotRequeue()
-
-	return result, nil
+	return utils.DoNotRequeue(), nil
 }
 
-// ReleaseNodePool frees resources allocated to a NodePool
+// ReleaseNodePool frees resources allocated to a NodePool. The resource group deletion is tracked via the jobId
+// recorded in the deleteJobId annotation, returning true once the resource group has been deleted.
 func (a *Adaptor) ReleaseNodePool(ctx context.Context,
 	hwmgrClient *hwmgrclient.HardwareManagerClient,
 	hwmgr *pluginv1alpha1.HardwareManager,
-	nodepool *hwmgmtv1alpha1.NodePool) error {
+	nodepool *hwmgmtv1alpha1.NodePool) (bool, error) {
 
 	a.Logger.InfoContext(ctx, "Processing ReleaseNodePool request")
 
-	// Issue a resource group deletion request to the hardware manager
-	jobId, err := hwmgrClient.DeleteResourceGroup(ctx, nodepool)
-	if err != nil {
-		return fmt.Errorf("failed CreateResourceGroup: %w", err)
+	jobId := utils.GetDeleteJobId(nodepool)
+	if jobId == "" {
+		exists, err := hwmgrClient.ResourceGroupExists(ctx, nodepool)
+		if err != nil {
+			return false, fmt.Errorf("failed to check for resource group: %w", err)
+		}
+		if !exists {
+			a.Logger.InfoContext(ctx, "Resource group not found, nothing to release")
+			return true, nil
+		}
+
+		// Issue a resource group deletion request to the hardware manager
+		jobId, err = hwmgrClient.DeleteResourceGroup(ctx, nodepool)
+		if hwmgrErr, ok := hwmgrclient.AsHwmgrError(err); ok && hwmgrErr.Kind == hwmgrclient.ErrorKindNotFound {
+			a.Logger.InfoContext(ctx, "Resource group already deleted, nothing to release")
+			return true, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed DeleteResourceGroup: %w", err)
+		}
+
+		// Add the jobId in an annotation
+		utils.SetDeleteJobId(nodepool, jobId)
+
+		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+			return false, fmt.Errorf("failed to annotate nodepool %s: %w", nodepool.Name, err)
+		}
+
+		return false, nil
 	}
 
 	ctx = logging.AppendCtx(ctx, slog.String("jobId", jobId))
+	a.Logger.InfoContext(ctx, "Checking deletion job progress")
 
-	// TODO: Can we even requeue the finalizer? If so, we should have a separate annotation for this jobID.
-	// For now, poll until the job finishes
-	finished := false
-	for !finished {
-		time.Sleep(time.Second * 10)
-		a.Logger.InfoContext(ctx, "Checking deletion job progress")
-
-		status, failReason, err := hwmgrClient.CheckJobStatus(ctx, jobId)
-		if err != nil {
-			a.Logger.InfoContext(ctx, "Deletion job progress check failed", slog.String("error", err.Error()))
-			return fmt.Errorf("deletion job progress check failed: %w", err)
+	status, failReason, err := hwmgrClient.CheckJobStatus(ctx, jobId)
+	if err != nil {
+		// The hardware manager may clear the job as soon as the resource group is deleted, so fall back to checking
+		// for the resource group itself
+		a.Logger.InfoContext(ctx, "Deletion job progress check failed", slog.String("error", err.Error()))
+		exists, rgErr := hwmgrClient.ResourceGroupExists(ctx, nodepool)
+		if rgErr != nil {
+			return false, fmt.Errorf("deletion job progress check failed: %w", errors.Join(err, rgErr))
 		}
+		return !exists, nil
+	}
 
-		// TODO: Currently, the hardware manager is clearing the job immediately on deletion, so the check fails
-		a.Logger.InfoContext(ctx, "Deletion job progress check returned", slog.Any("status", status), slog.String("failReason", failReason))
+	switch status {
+	case hwmgrclient.JobStatusCompleted:
+		a.Logger.InfoContext(ctx, "Deletion job has completed")
+		return true, nil
+	case hwmgrclient.JobStatusFailed:
+		// Clear the jobId so that the deletion is reissued on the next attempt
+		utils.ClearDeleteJobId(nodepool)
+		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+			return false, fmt.Errorf("failed to clear annotation from nodepool %s: %w", nodepool.Name, err)
+		}
+		return false, fmt.Errorf("resource group deletion job failed: %s", failReason)
+	default:
+		a.Logger.InfoContext(ctx, "Deletion job in progress", slog.Any("status", status))
+		return false, nil
 	}
+}
 
-	return nil
+// profileUpdateFailure describes a failed profile update job
+type profileUpdateFailure struct {
+	message  string
+	rollback bool
 }
 
-func (a *Adaptor) handleNodePoolConfiguring(
+// checkProfileUpdates checks the update jobs of the nodes in flight, completing the nodes whose job has completed.
+// A node whose job has failed is reverted to its current profile, so that the update is reissued if the NodePool is
+// retried, and is annotated as failed if markFailed is set. Returns the number of jobs still in progress, along with
+// the failures.
+func (a *Adaptor) checkProfileUpdates(
 	ctx context.Context,
 	hwmgrClient *hwmgrclient.HardwareManagerClient,
-	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
-
-	var result ctrl.Result
-
-	a.Logger.InfoContext(ctx, "Handling Node Pool Configuring")
-
-	nodelist, err := utils.GetChildNodes(ctx, a.Logger, a.Client, nodepool)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to get child nodes for Node Pool %s: %w", nodepool.Name, err)
-	}
+	inProgress []*hwmgmtv1alpha1.Node,
+	markFailed bool) (int, []profileUpdateFailure, error) {
 
-	a.Logger.InfoContext(ctx, "Checking for node with profile update in-progress")
-
-	// Search for a node that is currently being updated
-	if node := utils.FindNodeUpdateInProgress(nodelist); node != nil {
-		// A node has an update already in progress
+	var pending int
+	var failures []profileUpdateFailure
 
+	for _, node := range inProgress {
 		jobId := utils.GetJobId(node)
-		if jobId == "" {
-			return result, fmt.Errorf("jobId annotation is missing or empty from node %s", node.Name)
-		}
 
 		// Query the hardware manager for the job status
 		status, failReason, err := hwmgrClient.CheckJobStatus(ctx, jobId)
 		if err != nil {
 			a.Logger.InfoContext(ctx, "Profile update job progress check failed", slog.String("error", err.Error()))
-			return result, fmt.Errorf("failed to check profile update job progress, jobId=%s: %w", jobId, err)
+			return 0, nil, fmt.Errorf("failed to check profile update job progress, jobId=%s: %w", jobId, err)
 		}
 
 		// Process the status response
 		switch status {
 		case hwmgrclient.JobStatusInProgress:
-			return utils.RequeueWithShortInterval(), nil
+			pending++
+			continue
 		case hwmgrclient.JobStatusFailed:
-			a.Logger.InfoContext(ctx, "Profile update creation failed", slog.String("failReason", failReason))
-			if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
-				hwmgmtv1alpha1.Configured,
-				hwmgmtv1alpha1.Failed,
-				metav1.ConditionFalse,
-				fmt.Sprintf("Profile update creation failed: %s", failReason)); err != nil {
-				return utils.RequeueWithMediumInterval(),
-					fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+			a.Logger.InfoContext(ctx, "Profile update creation failed",
+				slog.String("nodename", node.Name),
+				slog.String("jobId", jobId),
+				slog.String("failReason", failReason))
+
+			failures = append(failures, profileUpdateFailure{
+				message:  fmt.Sprintf("node %s, jobId=%s: %s", node.Name, jobId, failReason),
+				rollback: node.Spec.HwProfile == node.GetAnnotations()[utils.PreviousHwProfileAnnotation],
+			})
+
+			patch := client.MergeFrom(node.DeepCopy())
+			node.Spec.HwProfile = node.Status.HwProfile
+			utils.ClearJobId(node)
+			if markFailed {
+				annotations := node.GetAnnotations()
+				annotations[utils.ProfileUpdateFailedAnnotation] = failReason
+				node.SetAnnotations(annotations)
 			}
-			// TODO: Mark the config change as failed
-			return result, fmt.Errorf("profile update creation failed, jobId=%s: %s", jobId, failReason)
+			if err := a.Client.Patch(ctx, node, patch); err != nil {
+				return 0, nil, fmt.Errorf("failed to patch Node %s in namespace %s: %w", node.Name, node.Namespace, err)
+			}
+			continue
 		case hwmgrclient.JobStatusCompleted:
-			a.Logger.InfoContext(ctx, "Profile update job has completed")
+			a.Logger.InfoContext(ctx, "Profile update job has completed", slog.String("jobId", jobId))
 		default:
 			a.Logger.InfoContext(ctx, "Profile update check returned unknown status", slog.String("failReason", failReason))
-			return result, fmt.Errorf("failed to check profile update job progress, jobId=%s: %s", jobId, failReason)
+			return 0, nil, fmt.Errorf("failed to check profile update job progress, jobId=%s: %s", jobId, failReason)
 		}
 
 		// Node update is complete
 		a.Logger.InfoContext(ctx, "Node update complete", slog.String("nodename", node.Name))
 		node.Status.HwProfile = node.Spec.HwProfile
 		if err := utils.UpdateK8sCRStatus(ctx, a.Client, node); err != nil {
-			return ctrl.Result{}, fmt.Errorf("failed to update status for node %s: %w", node.Name, err)
+			return 0, nil, fmt.Errorf("failed to update status for node %s: %w", node.Name, err)
 		}
 
 		utils.ClearJobId(node)
 		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, node, nil, utils.PATCH); err != nil {
-			return ctrl.Result{}, fmt.Errorf("failed to clear annotation from node %s: %w", node.Name, err)
+			return 0, nil, fmt.Errorf("failed to clear annotation from node %s: %w", node.Name, err)
 		}
+	}
 
-		return utils.RequeueImmediately(), nil
+	return pending, failures, nil
+}
+
+// issueProfileUpdate starts the update of the node to the given profile, recording the jobId in an annotation. The
+// profile of the node before the rollout is recorded, unless already set by an earlier attempt, so that the update can
+// be rolled back.
+func (a *Adaptor) issueProfileUpdate(
+	ctx context.Context,
+	hwmgrClient *hwmgrclient.HardwareManagerClient,
+	node *hwmgmtv1alpha1.Node,
+	newHwProfile string) error {
+
+	a.Logger.InfoContext(ctx, "Issuing profile update to node",
+		slog.String("hwMgrNodeId", node.Spec.HwMgrNodeId),
+		slog.String("curHwProfile", node.Spec.HwProfile),
+		slog.String("newHwProfile", newHwProfile))
+
+	jobId, err := hwmgrClient.UpdateResourceProfile(ctx, node, newHwProfile)
+	if err != nil {
+		return fmt.Errorf("failed to update resource for node %s: %w", node.Name, err)
 	}
 
-	a.Logger.InfoContext(ctx, "Checking for nodes to update")
+	a.Logger.InfoContext(ctx, "Updating Node CR with new profile",
+		slog.String("nodename", node.Name),
+		slog.String("newHwProfile", newHwProfile),
+		slog.String("jobId", jobId),
+	)
 
-	// There are no nodes currently in-progress, so we can look for the next one to start updating
-	for _, nodegroup := range nodepool.Spec.NodeGroup {
-		newHwProfile := nodegroup.NodePoolData.HwProfile
-		node := utils.FindNextNodeToUpdate(nodelist, nodegroup.NodePoolData.Name, newHwProfile)
-		if node == nil {
-			// No more nodes to update in this nodegroup
+	// Copy the current node object for patching
+	patch := client.MergeFrom(node.DeepCopy())
+
+	// Record the current profile, and the jobId, in annotations
+	annotations := node.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	if _, exists := annotations[utils.PreviousHwProfileAnnotation]; !exists {
+		annotations[utils.PreviousHwProfileAnnotation] = node.Spec.HwProfile
+	}
+	node.SetAnnotations(annotations)
+	utils.SetJobId(node, jobId)
+
+	// Set the new profile in the spec
+	node.Spec.HwProfile = newHwProfile
+
+	if err = a.Client.Patch(ctx, node, patch); err != nil {
+		return fmt.Errorf("failed to patch Node %s in namespace %s: %w", node.Name, node.Namespace, err)
+	}
+
+	return nil
+}
+
+// clearRolloutAnnotations removes the annotations tracking a rollout from the NodePool and its nodes, once the rollout
+// has completed, failed or been rolled back
+func (a *Adaptor) clearRolloutAnnotations(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	nodelist *hwmgmtv1alpha1.NodeList) error {
+
+	for i := range nodelist.Items {
+		node := &nodelist.Items[i]
+		annotations := node.GetAnnotations()
+		_, hasPrevious := annotations[utils.PreviousHwProfileAnnotation]
+		_, hasFailed := annotations[utils.ProfileUpdateFailedAnnotation]
+		if !hasPrevious && !hasFailed {
 			continue
 		}
 
-		a.Logger.InfoContext(ctx, "Issuing profile update to node",
-			slog.String("hwMgrNodeId", node.Spec.HwMgrNodeId),
-			slog.String("curHwProfile", node.Spec.HwProfile),
-			slog.String("newHwProfile", newHwProfile))
+		delete(annotations, utils.PreviousHwProfileAnnotation)
+		delete(annotations, utils.ProfileUpdateFailedAnnotation)
+		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, node, nil, utils.PATCH); err != nil {
+			return fmt.Errorf("failed to clear annotations from node %s: %w", node.Name, err)
+		}
+	}
 
-		jobId, err := hwmgrClient.UpdateResourceProfile(ctx, node, newHwProfile)
+	annotations := nodepool.GetAnnotations()
+	_, hasCompleted := annotations[utils.RolloutBatchCompletedAnnotation]
+	_, hasRollback := annotations[utils.RolloutRollbackAnnotation]
+	if hasCompleted || hasRollback {
+		delete(annotations, utils.RolloutBatchCompletedAnnotation)
+		delete(annotations, utils.RolloutRollbackAnnotation)
+		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+			return fmt.Errorf("failed to clear annotations from nodepool %s: %w", nodepool.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// handleProfileUpdateFailure applies the failure policy of the rollout when profile update jobs have failed. A failed
+// rollback, or a failed update under the Halt policy, stops the rollout, marking the configuration as failed. Under the
+// Rollback policy, the NodePool is annotated so that the nodes already updated are restored to their previous profile.
+// Returns true if the failures were handled.
+func (a *Adaptor) handleProfileUpdateFailure(
+	ctx context.Context,
+	strategy *utils.RolloutStrategy,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	nodelist *hwmgmtv1alpha1.NodeList,
+	failures []profileUpdateFailure) (bool, ctrl.Result, error) {
+
+	_, rollingBack := nodepool.GetAnnotations()[utils.RolloutRollbackAnnotation]
+
+	var messages []string
+	for _, failure := range failures {
+		// While rolling back, the failure of an update that was in flight when the rollout failed is ignored, as
+		// the node is left on its previous profile
+		if !rollingBack || failure.rollback {
+			messages = append(messages, failure.message)
+		}
+	}
+	if len(messages) == 0 {
+		return false, ctrl.Result{}, nil
+	}
+	failReason := strings.Join(messages, "; ")
+
+	if rollingBack {
+		if err := a.clearRolloutAnnotations(ctx, nodepool, nodelist); err != nil {
+			return true, utils.RequeueWithShortInterval(), err
+		}
+		if _, err := a.failNodePoolConfiguring(ctx, nodepool, fmt.Sprintf("Rollback of profile update failed: %s", failReason)); err != nil {
+			return true, utils.RequeueWithShortInterval(), err
+		}
+		return true, ctrl.Result{}, fmt.Errorf("profile update rollback failed: %s", failReason)
+	}
+
+	if strategy.FailurePolicy == utils.RolloutFailurePolicyRollback {
+		a.Logger.InfoContext(ctx, "Rolling back profile update", slog.String("failReason", failReason))
+		annotations := nodepool.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[utils.RolloutRollbackAnnotation] = failReason
+		nodepool.SetAnnotations(annotations)
+		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+			return true, utils.RequeueWithShortInterval(), fmt.Errorf("failed to annotate nodepool %s: %w", nodepool.Name, err)
+		}
+		return true, utils.RequeueImmediately(), nil
+	}
+
+	updated := utils.FindNodesUpdated(nodelist)
+	message := fmt.Sprintf("Profile update failed, halted with %d of %d nodes updated: %s",
+		len(updated), len(nodelist.Items), failReason)
+	if _, err := a.failNodePoolConfiguring(ctx, nodepool, message); err != nil {
+		return true, utils.RequeueWithShortInterval(), err
+	}
+	return true, ctrl.Result{}, fmt.Errorf("profile update creation failed: %s", failReason)
+}
+
+// rollbackProfileUpdates restores the nodes updated by a failed rollout to their previous profile, in batches. Once
+// all nodes are restored, the configuration is marked as failed, reporting the rollback.
+func (a *Adaptor) rollbackProfileUpdates(
+	ctx context.Context,
+	hwmgrClient *hwmgrclient.HardwareManagerClient,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	strategy *utils.RolloutStrategy,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	nodelist *hwmgmtv1alpha1.NodeList) (ctrl.Result, error) {
+
+	if batch := strategy.SelectRollbackBatch(nodepool, nodelist); len(batch) > 0 {
+		if remaining := strategy.GetBatchPauseRemaining(nodepool, time.Now()); remaining > 0 {
+			a.Logger.InfoContext(ctx, "Pausing before the next batch of profile rollbacks", slog.Duration("remaining", remaining))
+			return utils.RequeueWithCustomInterval(remaining), nil
+		}
+
+		if wait, result, err := utils.WaitForMaintenanceWindow(ctx, a.Client, hwmgr, nodepool); wait || err != nil {
+			return result, err
+		}
+
+		for _, node := range batch {
+			if err := a.issueProfileUpdate(ctx, hwmgrClient, node, node.GetAnnotations()[utils.PreviousHwProfileAnnotation]); err != nil {
+				return utils.RequeueWithShortInterval(), err
+			}
+		}
+
+		// Requeue to check rollback progress
+		return utils.RequeueWithMediumInterval(), nil
+	}
+
+	a.Logger.InfoContext(ctx, "All updated nodes have been rolled back to their previous profile")
+	failReason := nodepool.GetAnnotations()[utils.RolloutRollbackAnnotation]
+	if err := a.clearRolloutAnnotations(ctx, nodepool, nodelist); err != nil {
+		return utils.RequeueWithShortInterval(), err
+	}
+
+	return a.failNodePoolConfiguring(ctx, nodepool, fmt.Sprintf("Profile update failed and was rolled back: %s", failReason))
+}
+
+func (a *Adaptor) handleNodePoolConfiguring(
+	ctx context.Context,
+	hwmgrClient *hwmgrclient.HardwareManagerClient,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	var result ctrl.Result
+
+	a.Logger.InfoContext(ctx, "Handling Node Pool Configuring")
+
+	strategy, err := utils.GetRolloutStrategy(nodepool)
+	if err != nil {
+		return a.failNodePoolConfiguring(ctx, nodepool, err.Error())
+	}
+
+	nodelist, err := utils.GetChildNodes(ctx, a.Logger, a.Client, nodepool)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get child nodes for Node Pool %s: %w", nodepool.Name, err)
+	}
+
+	_, rollingBack := nodepool.GetAnnotations()[utils.RolloutRollbackAnnotation]
+
+	a.Logger.InfoContext(ctx, "Checking for nodes with profile update in-progress")
+
+	// Check the progress of the batch of updates in flight
+	if inProgress := utils.FindNodesUpdateInProgress(nodelist); len(inProgress) > 0 {
+		markFailed := strategy.FailurePolicy == utils.RolloutFailurePolicyContinue && !rollingBack
+		pending, failures, err := a.checkProfileUpdates(ctx, hwmgrClient, inProgress, markFailed)
 		if err != nil {
-			return utils.RequeueWithShortInterval(), fmt.Errorf("failed to update resource for node %s: %w", node.Name, err)
+			return utils.RequeueWithShortInterval(), err
+		}
+
+		if len(failures) > 0 && !markFailed {
+			if handled, result, err := a.handleProfileUpdateFailure(ctx, strategy, nodepool, nodelist, failures); handled {
+				return result, err
+			}
 		}
 
-		a.Logger.InfoContext(ctx, "Updating Node CR with new profile",
-			slog.String("nodename", node.Name),
-			slog.String("newHwProfile", newHwProfile),
-			slog.String("jobId", jobId),
-		)
+		if pending > 0 {
+			return jobPollRequeue(hwmgr), nil
+		}
 
-		// Copy the current node object for patching
-		patch := client.MergeFrom(node.DeepCopy())
+		// The batch is complete. Record the completion time, from which the pause before the next batch is measured.
+		a.Logger.InfoContext(ctx, "Batch of profile updates complete", slog.Int("nodes", len(inProgress)))
+		utils.SetRolloutBatchCompleted(nodepool, time.Now())
+		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+			return utils.RequeueWithShortInterval(), fmt.Errorf("failed to annotate nodepool %s: %w", nodepool.Name, err)
+		}
 
-		// Set the new profile in the spec
-		node.Spec.HwProfile = newHwProfile
+		return utils.RequeueImmediately(), nil
+	}
 
-		// Record the jobId in an annotation
-		utils.SetJobId(node, jobId)
+	if rollingBack {
+		return a.rollbackProfileUpdates(ctx, hwmgrClient, hwmgr, strategy, nodepool, nodelist)
+	}
 
-		if err = a.Client.Patch(ctx, node, patch); err != nil {
-			return utils.RequeueWithShortInterval(), fmt.Errorf("failed to patch Node %s in namespace %s: %w", node.Name, node.Namespace, err)
+	a.Logger.InfoContext(ctx, "Checking for nodes to update")
+
+	// There are no nodes currently in-progress, so we can start updating the next batch
+	if newHwProfile, batch := strategy.SelectRolloutBatch(nodepool, nodelist); len(batch) > 0 {
+		if remaining := strategy.GetBatchPauseRemaining(nodepool, time.Now()); remaining > 0 {
+			a.Logger.InfoContext(ctx, "Pausing before the next batch of profile updates", slog.Duration("remaining", remaining))
+			return utils.RequeueWithCustomInterval(remaining), nil
+		}
+
+		// Profile updates reboot the nodes, so are only started within a maintenance window
+		if wait, result, err := utils.WaitForMaintenanceWindow(ctx, a.Client, hwmgr, nodepool); wait || err != nil {
+			return result, err
+		}
+
+		for _, node := range batch {
+			if err := a.issueProfileUpdate(ctx, hwmgrClient, node, newHwProfile); err != nil {
+				return utils.RequeueWithShortInterval(), err
+			}
 		}
 
 		// Requeue to check update progress
 		return utils.RequeueWithMediumInterval(), nil
 	}
 
-	// All nodes have been updated
+	// All nodes have been updated, other than those that failed under the Continue policy
+	failed := utils.FindNodesUpdateFailed(nodelist)
+	var failures []string
+	for _, node := range failed {
+		failures = append(failures, fmt.Sprintf("node %s: %s", node.Name, node.GetAnnotations()[utils.ProfileUpdateFailedAnnotation]))
+	}
+
+	if err := a.clearRolloutAnnotations(ctx, nodepool, nodelist); err != nil {
+		return utils.RequeueWithShortInterval(), err
+	}
+
+	if len(failed) > 0 {
+		return a.failNodePoolConfiguring(ctx, nodepool, fmt.Sprintf("Profile update partially applied, %d of %d nodes failed: %s",
+			len(failed), len(nodelist.Items), strings.Join(failures, "; ")))
+	}
+
 	a.Logger.InfoContext(ctx, "All nodes have been updated to new profile")
 	if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
 		hwmgmtv1alpha1.Configured, hwmgmtv1alpha1.ConfigApplied, metav1.ConditionTrue, string(hwmgmtv1alpha1.ConfigSuccess)); err != nil {
@@ -421,12 +763,43 @@ func (a *Adaptor) handleNodePoolConfiguring(
 	return result, nil
 }
 
+// failNodePoolConfiguring marks the configuration of the NodePool as failed, and the spec change as handled so that
+// it is not reapplied until the NodePool is retried
+func (a *Adaptor) failNodePoolConfiguring(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	message string) (ctrl.Result, error) {
+
+	a.Logger.InfoContext(ctx, "NodePool configuration failed", slog.String("reason", message))
+
+	if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+		hwmgmtv1alpha1.Configured,
+		hwmgmtv1alpha1.Failed,
+		metav1.ConditionFalse,
+		message); err != nil {
+		return utils.RequeueWithMediumInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	if err := utils.UpdateNodePoolPluginStatus(ctx, a.Client, nodepool); err != nil {
+		return utils.RequeueWithShortInterval(),
+			fmt.Errorf("failed to update hwMgrPlugin observedGeneration Status: %w", err)
+	}
+
+	return utils.DoNotRequeue(), nil
+}
+
 func (a *Adaptor) HandleNodePoolSpecChanged(
 	ctx context.Context,
 	hwmgrClient *hwmgrclient.HardwareManagerClient,
 	hwmgr *pluginv1alpha1.HardwareManager,
 	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
 
+	message := string(hwmgmtv1alpha1.AwaitConfig)
+	if failReason, rollingBack := nodepool.GetAnnotations()[utils.RolloutRollbackAnnotation]; rollingBack {
+		message = fmt.Sprintf("Rolling back profile update: %s", failReason)
+	}
+
 	if err := utils.UpdateNodePoolStatusCondition(
 		ctx,
 		a.Client,
@@ -434,10 +807,273 @@ func (a *Adaptor) HandleNodePoolSpecChanged(
 		hwmgmtv1alpha1.Configured,
 		hwmgmtv1alpha1.ConfigUpdate,
 		metav1.ConditionFalse,
-		string(hwmgmtv1alpha1.AwaitConfig)); err != nil {
+		message); err != nil {
 		return utils.RequeueWithMediumInterval(),
 			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
 	}
 
-	return a.handleNodePoolConfiguring(ctx, hwmgrClient, nodepool)
+	if done, result, err := a.handleNodePoolScaling(ctx, hwmgrClient, hwmgr, nodepool); !done || err != nil {
+		return result, err
+	}
+
+	return a.handleNodePoolConfiguring(ctx, hwmgrClient, hwmgr, nodepool)
+}
+
+// handleNodePoolScaling adds or releases resources as needed to match the size of each nodegroup, returning true once
+// all nodegroups are at their requested size. The hardware manager API provides no means to resize a resource group,
+// so resources are added to or released from the resource group one at a time by updating their group membership,
+// with the job tracked via the jobId annotation of the NodePool.
+func (a *Adaptor) handleNodePoolScaling(
+	ctx context.Context,
+	hwmgrClient *hwmgrclient.HardwareManagerClient,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (bool, ctrl.Result, error) {
+
+	if jobId := utils.GetJobId(nodepool); jobId != "" {
+		// Query the hardware manager for the job status
+		status, failReason, err := hwmgrClient.CheckJobStatus(ctx, jobId)
+		if err != nil {
+			a.Logger.InfoContext(ctx, "Resource group update check failed", slog.String("error", err.Error()))
+			return false, utils.RequeueWithShortInterval(),
+				fmt.Errorf("failed to check resource group update job progress, jobId=%s: %w", jobId, err)
+		}
+
+		switch status {
+		case hwmgrclient.JobStatusInProgress:
+			return false, jobPollRequeue(hwmgr), nil
+		case hwmgrclient.JobStatusFailed:
+			utils.ClearJobId(nodepool)
+			if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+				return false, utils.RequeueWithShortInterval(),
+					fmt.Errorf("failed to clear annotation from nodepool %s: %w", nodepool.Name, err)
+			}
+			return a.failNodePoolScaling(ctx, nodepool,
+				fmt.Sprintf("Resource group update failed, jobId=%s: %s", jobId, failReason))
+		case hwmgrclient.JobStatusCompleted:
+			a.Logger.InfoContext(ctx, "Resource group update job has completed", slog.String("jobId", jobId))
+			utils.ClearJobId(nodepool)
+			if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+				return false, utils.RequeueWithShortInterval(),
+					fmt.Errorf("failed to clear annotation from nodepool %s: %w", nodepool.Name, err)
+			}
+		default:
+			a.Logger.InfoContext(ctx, "Resource group update check returned unknown status", slog.String("failReason", failReason))
+			return false, utils.RequeueWithShortInterval(),
+				fmt.Errorf("failed to check resource group update job progress, jobId=%s: %s", jobId, failReason)
+		}
+	}
+
+	nodelist, err := utils.GetChildNodes(ctx, a.Logger, a.Client, nodepool)
+	if err != nil {
+		return false, utils.RequeueWithShortInterval(),
+			fmt.Errorf("failed to get child nodes for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	// The resource group is only queried when the size of a nodegroup has changed, or while scaling is in progress
+	scaledCondition := meta.FindStatusCondition(nodepool.Status.Conditions, string(utils.NodePoolScaled))
+	scaling := scaledCondition != nil && scaledCondition.Reason == string(hwmgmtv1alpha1.InProgress)
+	if !scaling && isNodePoolAtSize(nodepool, nodelist) {
+		return true, utils.DoNotRequeue(), nil
+	}
+
+	members, err := a.reconcileResourceGroupMembers(ctx, hwmgrClient, nodepool, nodelist)
+	if err != nil {
+		return false, utils.RequeueWithShortInterval(), err
+	}
+
+	var toRelease []hwmgmtv1alpha1.Node
+	var toAdd []string
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		used := members[nodegroup.NodePoolData.Name]
+
+		if len(used) > nodegroup.Size {
+			var nodes []hwmgmtv1alpha1.Node
+			for _, node := range nodelist.Items {
+				if slices.Contains(used, node.Spec.HwMgrNodeId) {
+					nodes = append(nodes, node)
+				}
+			}
+
+			selected, err := utils.SelectNodesForRemoval(nodepool, nodes, len(used)-nodegroup.Size)
+			if err != nil {
+				return a.failNodePoolScaling(ctx, nodepool, err.Error())
+			}
+			toRelease = append(toRelease, selected...)
+		} else if len(used) < nodegroup.Size {
+			free, err := hwmgrClient.GetFreeResources(ctx, nodegroup.NodePoolData.ResourcePoolId, nodegroup.NodePoolData.Name)
+			if err != nil {
+				return false, utils.RequeueWithShortInterval(), fmt.Errorf("failed to get free resources: %w", err)
+			}
+			if nodegroup.Size-len(used) > len(free) {
+				return a.failNodePoolScaling(ctx, nodepool,
+					fmt.Sprintf("not enough free resources in resource pool %s to scale out nodegroup %s: freenodes=%d",
+						nodegroup.NodePoolData.ResourcePoolId, nodegroup.NodePoolData.Name, len(free)))
+			}
+			toAdd = append(toAdd, *free[0].Id)
+		}
+	}
+
+	if len(toRelease) == 0 && len(toAdd) == 0 {
+		if scaling {
+			a.Logger.InfoContext(ctx, "NodePool scaling complete")
+			if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+				utils.NodePoolScaled, hwmgmtv1alpha1.Completed, metav1.ConditionTrue, "Scaled"); err != nil {
+				return false, utils.RequeueWithShortInterval(),
+					fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+			}
+		}
+		return true, utils.DoNotRequeue(), nil
+	}
+
+	// Releasing nodes is disruptive, so is only started within a maintenance window
+	if len(toRelease) > 0 {
+		if wait, result, err := utils.WaitForMaintenanceWindow(ctx, a.Client, hwmgr, nodepool); wait || err != nil {
+			return false, result, err
+		}
+	}
+
+	a.Logger.InfoContext(ctx, "Scaling NodePool",
+		slog.Int("releasing", len(toRelease)),
+		slog.Int("adding", len(toAdd)))
+
+	if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+		utils.NodePoolScaled, hwmgmtv1alpha1.InProgress, metav1.ConditionFalse, "Scaling nodegroups"); err != nil {
+		return false, utils.RequeueWithShortInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	var jobId string
+	if len(toRelease) > 0 {
+		jobId, err = hwmgrClient.RemoveResourceFromGroup(ctx, nodepool, toRelease[0].Spec.HwMgrNodeId)
+		if err != nil {
+			return false, utils.RequeueWithShortInterval(), fmt.Errorf("failed to release node %s: %w", toRelease[0].Name, err)
+		}
+	} else {
+		jobId, err = hwmgrClient.AddResourceToGroup(ctx, nodepool, toAdd[0])
+		if err != nil {
+			return false, utils.RequeueWithShortInterval(), fmt.Errorf("failed to add resource %s: %w", toAdd[0], err)
+		}
+	}
+
+	utils.SetJobId(nodepool, jobId)
+	if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+		return false, utils.RequeueWithShortInterval(), fmt.Errorf("failed to annotate nodepool %s: %w", nodepool.Name, err)
+	}
+
+	return false, jobPollRequeue(hwmgr), nil
+}
+
+// isNodePoolAtSize checks whether each nodegroup of the NodePool has the requested number of nodes
+func isNodePoolAtSize(nodepool *hwmgmtv1alpha1.NodePool, nodelist *hwmgmtv1alpha1.NodeList) bool {
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		count := 0
+		for _, node := range nodelist.Items {
+			if node.Spec.GroupName == nodegroup.NodePoolData.Name {
+				count++
+			}
+		}
+		if count != nodegroup.Size {
+			return false
+		}
+	}
+	return true
+}
+
+// reconcileResourceGroupMembers reconciles the Node CRs of a NodePool being scaled with the members of its resource
+// group, deleting the Node CRs of released resources and creating Node CRs for added resources. Returns the resources
+// of the resource group for each nodegroup.
+func (a *Adaptor) reconcileResourceGroupMembers(
+	ctx context.Context,
+	hwmgrClient *hwmgrclient.HardwareManagerClient,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	nodelist *hwmgmtv1alpha1.NodeList) (map[string][]string, error) {
+
+	rg, err := hwmgrClient.GetResourceGroup(ctx, nodepool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource group: %w", err)
+	}
+
+	resources := make(map[string][]hwmgrapi.RhprotoResource)
+	members := make(map[string][]string)
+	if rg.ResourceSelectors != nil {
+		for nodegroupName, resourceSelector := range *rg.ResourceSelectors {
+			if resourceSelector.Resources == nil {
+				continue
+			}
+			resources[nodegroupName] = *resourceSelector.Resources
+			for _, resource := range *resourceSelector.Resources {
+				members[nodegroupName] = append(members[nodegroupName], *resource.Id)
+			}
+		}
+	}
+
+	isMember := func(resourceId string) bool {
+		for _, resources := range members {
+			if slices.Contains(resources, resourceId) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Delete the Node CRs of the released resources
+	for _, node := range nodelist.Items {
+		if isMember(node.Spec.HwMgrNodeId) {
+			continue
+		}
+		if err := a.DeleteNode(ctx, node.Name); err != nil {
+			return nil, err
+		}
+		nodepool.Status.Properties.NodeNames = slices.DeleteFunc(nodepool.Status.Properties.NodeNames,
+			func(nodename string) bool { return nodename == node.Name })
+	}
+
+	// Create the Node CRs corresponding to the added resources. A Node CR left over from a previous attempt is adopted.
+	for nodegroupName, added := range resources {
+		for _, resource := range added {
+			nodename := utils.FindNodeInList(*nodelist, nodepool.Spec.HwMgrId, *resource.Id)
+			if nodename != "" {
+				if slices.Contains(nodepool.Status.Properties.NodeNames, nodename) {
+					continue
+				}
+				if err := a.AdoptNode(ctx, hwmgrClient, nodepool, nodename, resource); err != nil {
+					return nil, fmt.Errorf("failed to adopt node %s: %w", nodename, err)
+				}
+			} else {
+				nodename, err = a.AllocateNode(ctx, hwmgrClient, nodepool, resource, nodegroupName)
+				if err != nil {
+					return nil, fmt.Errorf("failed to allocate node (%s): %w", *resource.Id, err)
+				}
+			}
+			nodepool.Status.Properties.NodeNames = append(nodepool.Status.Properties.NodeNames, nodename)
+		}
+	}
+
+	if err := utils.UpdateNodePoolProperties(ctx, a.Client, nodepool); err != nil {
+		return nil, fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	return members, nil
+}
+
+// failNodePoolScaling reports a scaling failure, marking the spec change as handled so that it is not retried
+func (a *Adaptor) failNodePoolScaling(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	message string) (bool, ctrl.Result, error) {
+
+	a.Logger.InfoContext(ctx, "NodePool scaling failed", slog.String("reason", message))
+
+	if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+		utils.NodePoolScaled, hwmgmtv1alpha1.Failed, metav1.ConditionFalse, message); err != nil {
+		return false, utils.RequeueWithShortInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	if err := utils.UpdateNodePoolPluginStatus(ctx, a.Client, nodepool); err != nil {
+		return false, utils.RequeueWithShortInterval(),
+			fmt.Errorf("failed to update hwMgrPlugin observedGeneration Status: %w", err)
+	}
+
+	return false, utils.DoNotRequeue(), nil
 }
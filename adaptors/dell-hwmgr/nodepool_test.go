@@ -0,0 +1,251 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dellhwmgr
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/hwmgrclient"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	dellserver "github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/dell-hwmgr/dell-server"
+	apiserver "github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/dell-hwmgr/dell-server/generated"
+)
+
+var _ = Describe("Dell NodePool scaling", func() {
+	const (
+		tenant = hwmgrclient.DefaultTenant
+		pool   = "pool-1"
+	)
+
+	var (
+		ctx        context.Context
+		ds         *dellserver.DellServer
+		hwmgr      *pluginv1alpha1.HardwareManager
+		fakeClient client.Client
+		adaptor    *Adaptor
+		rgId       string
+	)
+
+	// setup creates the NodePool with a single worker nodegroup of the given size, already provisioned with the given
+	// nodes, each a member of the resource group of the NodePool
+	setup := func(size int, nodes ...*hwmgmtv1alpha1.Node) *hwmgmtv1alpha1.NodePool {
+		nodepool := newNodePool("np1", "")
+		nodepool.Generation = 2
+		nodepool.Spec.CloudID = "cloud-1"
+		nodepool.Spec.NodeGroup = []hwmgmtv1alpha1.NodeGroup{{
+			NodePoolData: hwmgmtv1alpha1.NodePoolData{Name: "worker", ResourcePoolId: pool, HwProfile: "profile-1"},
+			Size:         size,
+		}}
+		nodepool.Status.HwMgrPlugin.ObservedGeneration = 1
+
+		rgId = hwmgrclient.ResourceGroupIdFromNodePool(nodepool)
+		ds.AddResourceGroup(tenant, rgId)
+
+		objects := []client.Object{hwmgr, nodepool, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "dell-auth", Namespace: testNamespace},
+			Data: map[string][]byte{
+				hwmgrclient.AuthSecretClientIdKey: []byte("client"),
+				corev1.BasicAuthUsernameKey:       []byte("admin"),
+				corev1.BasicAuthPasswordKey:       []byte("secret"),
+			},
+		}}
+		for _, node := range nodes {
+			node.Spec.GroupName = "worker"
+			node.Spec.HwProfile = "profile-1"
+			node.Status.HwProfile = "profile-1"
+			ds.AddResource(tenant, pool, node.Spec.HwMgrNodeId, "worker")
+			ds.SetResourceGroup(node.Spec.HwMgrNodeId, rgId)
+			nodepool.Status.Properties.NodeNames = append(nodepool.Status.Properties.NodeNames, node.Name)
+			objects = append(objects, node)
+		}
+
+		fakeClient = newTestClient(objects...)
+		adaptor = NewAdaptor(fakeClient, fakeClient.Scheme(), slog.New(slog.NewTextHandler(GinkgoWriter, nil)), testNamespace)
+		return nodepool
+	}
+
+	// reconcile handles the spec change of the NodePool, returning its current state
+	reconcile := func(nodepool *hwmgmtv1alpha1.NodePool) (*hwmgmtv1alpha1.NodePool, error) {
+		hwmgrClient, err := hwmgrclient.NewClientWithResponses(ctx, adaptor.Logger, fakeClient, hwmgr)
+		Expect(err).NotTo(HaveOccurred())
+
+		current := &hwmgmtv1alpha1.NodePool{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(nodepool), current)).To(Succeed())
+		_, err = adaptor.HandleNodePoolSpecChanged(ctx, hwmgrClient, hwmgr, current)
+
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(nodepool), current)).To(Succeed())
+		return current, err
+	}
+
+	scaledCondition := func(nodepool *hwmgmtv1alpha1.NodePool) *metav1.Condition {
+		return meta.FindStatusCondition(nodepool.Status.Conditions, string(utils.NodePoolScaled))
+	}
+
+	childNodes := func(nodepool *hwmgmtv1alpha1.NodePool) []string {
+		nodelist, err := utils.GetChildNodes(ctx, adaptor.Logger, fakeClient, nodepool)
+		Expect(err).NotTo(HaveOccurred())
+		var resources []string
+		for _, node := range nodelist.Items {
+			resources = append(resources, node.Spec.HwMgrNodeId)
+		}
+		return resources
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		ds = dellserver.NewDellServer()
+		dellserver.GetTokenFn = func(w http.ResponseWriter, r *http.Request) {
+			token, expiresIn := "token", int64(3600)
+			w.Header().Set("Content-Type", "application/json")
+			Expect(json.NewEncoder(w).Encode(apiserver.RhprotoGetTokenResponseBody{
+				AccessToken: &token,
+				ExpiresIn:   &expiresIn,
+			})).To(Succeed())
+		}
+		server := httptest.NewServer(apiserver.HandlerWithOptions(ds, apiserver.GorillaServerOptions{}))
+		DeferCleanup(server.Close)
+
+		hwmgr = &pluginv1alpha1.HardwareManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "dell-1", Namespace: testNamespace, UID: "uid-1"},
+			Spec: pluginv1alpha1.HardwareManagerSpec{
+				AdaptorID: pluginv1alpha1.SupportedAdaptors.Dell,
+				DellData: &pluginv1alpha1.DellData{
+					ApiUrl:                server.URL,
+					AuthSecret:            "dell-auth",
+					InsecureSkipTLSVerify: true,
+				},
+			},
+		}
+		DeferCleanup(hwmgrclient.EvictClient, client.ObjectKeyFromObject(hwmgr))
+	})
+
+	It("adds a free resource to the resource group on scale-out", func() {
+		nodepool := setup(2, newNode("node-1", "np1", "resource-1", ""))
+		ds.AddResource(tenant, pool, "resource-2", "worker")
+		ds.AddResource(tenant, pool, "resource-3", "master")
+
+		By("issuing the resource group update")
+
+		current, err := reconcile(nodepool)
+		Expect(err).NotTo(HaveOccurred())
+		jobId := utils.GetJobId(current)
+		Expect(jobId).NotTo(BeEmpty())
+		Expect(scaledCondition(current).Reason).To(Equal(string(hwmgmtv1alpha1.InProgress)))
+
+		By("waiting for the job without reissuing the update")
+
+		current, err = reconcile(nodepool)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(utils.GetJobId(current)).To(Equal(jobId))
+
+		By("creating the Node CR once the job has completed")
+
+		ds.SetJobStatus(jobId, "completed", "")
+		current, err = reconcile(nodepool)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(utils.GetJobId(current)).To(BeEmpty())
+		Expect(ds.GetResourceGroupMembers(rgId)).To(ConsistOf("resource-1", "resource-2"))
+		Expect(childNodes(current)).To(ConsistOf("resource-1", "resource-2"))
+		Expect(current.Status.Properties.NodeNames).To(HaveLen(2))
+
+		Expect(scaledCondition(current).Status).To(Equal(metav1.ConditionTrue))
+		Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(hwmgmtv1alpha1.Configured))).To(BeTrue())
+		Expect(current.Status.HwMgrPlugin.ObservedGeneration).To(Equal(current.Generation))
+	})
+
+	It("releases the selected node from the resource group on scale-in", func() {
+		removed := newNode("node-2", "np1", "resource-2", "")
+		removed.Annotations = map[string]string{utils.RemoveNodeAnnotation: ""}
+		nodepool := setup(1, newNode("node-1", "np1", "resource-1", ""), removed)
+
+		By("issuing the release of the annotated node")
+
+		current, err := reconcile(nodepool)
+		Expect(err).NotTo(HaveOccurred())
+		jobId := utils.GetJobId(current)
+		Expect(jobId).NotTo(BeEmpty())
+
+		By("deleting the Node CR once the job has completed")
+
+		ds.SetJobStatus(jobId, "completed", "")
+		current, err = reconcile(nodepool)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ds.GetResourceGroupMembers(rgId)).To(ConsistOf("resource-1"))
+		Expect(childNodes(current)).To(ConsistOf("resource-1"))
+		Expect(current.Status.Properties.NodeNames).To(ConsistOf("node-1"))
+		Expect(scaledCondition(current).Status).To(Equal(metav1.ConditionTrue))
+
+		secret := &corev1.Secret{}
+		err = fakeClient.Get(ctx, client.ObjectKey{Name: bmcSecretName("node-2"), Namespace: testNamespace}, secret)
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails the scale-out when the resource pool has too few free resources", func() {
+		nodepool := setup(3, newNode("node-1", "np1", "resource-1", ""))
+		ds.AddResource(tenant, pool, "resource-2", "worker")
+
+		current, err := reconcile(nodepool)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(utils.GetJobId(current)).To(BeEmpty())
+		Expect(scaledCondition(current).Reason).To(Equal(string(hwmgmtv1alpha1.Failed)))
+		Expect(scaledCondition(current).Message).To(ContainSubstring("not enough free resources"))
+		Expect(current.Status.HwMgrPlugin.ObservedGeneration).To(Equal(current.Generation))
+	})
+
+	It("fails the scaling when the resource group update fails", func() {
+		nodepool := setup(2, newNode("node-1", "np1", "resource-1", ""))
+		ds.AddResource(tenant, pool, "resource-2", "worker")
+
+		current, err := reconcile(nodepool)
+		Expect(err).NotTo(HaveOccurred())
+		jobId := utils.GetJobId(current)
+		Expect(jobId).NotTo(BeEmpty())
+
+		ds.SetJobStatus(jobId, "failed", "resource busy")
+		current, err = reconcile(nodepool)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(utils.GetJobId(current)).To(BeEmpty())
+		Expect(scaledCondition(current).Reason).To(Equal(string(hwmgmtv1alpha1.Failed)))
+		Expect(scaledCondition(current).Message).To(ContainSubstring("resource busy"))
+		Expect(ds.GetResourceGroupMembers(rgId)).To(ConsistOf("resource-1"))
+	})
+
+	It("does not query the resource group when the nodegroups are at their size", func() {
+		nodepool := setup(1, newNode("node-1", "np1", "resource-1", ""))
+		ds.FailResourceGroupRequests(http.StatusInternalServerError)
+
+		current, err := reconcile(nodepool)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scaledCondition(current)).To(BeNil())
+		Expect(meta.IsStatusConditionTrue(current.Status.Conditions, string(hwmgmtv1alpha1.Configured))).To(BeTrue())
+	})
+})
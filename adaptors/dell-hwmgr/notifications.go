@@ -0,0 +1,186 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dellhwmgr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/hwmgrclient"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/notifications"
+)
+
+const (
+	// maxNotificationSize bounds the body of a notification pushed by a hardware manager
+	maxNotificationSize = 1 << 20
+
+	// defaultFallbackPollInterval is the interval at which the status of jobs is polled while subscribed to
+	// notifications, if not configured in the HardwareManager
+	defaultFallbackPollInterval = 5 * time.Minute
+)
+
+// SetupNotifications registers the handler for the notifications pushed by the Dell hardware managers to the receiver
+func (a *Adaptor) SetupNotifications(receiver *notifications.Receiver) {
+	a.receiver = receiver
+	receiver.Handle("POST "+notifications.Path(pluginv1alpha1.SupportedAdaptors.Dell, "{hwmgr}"),
+		http.HandlerFunc(a.handleNotification))
+}
+
+// handleNotification enqueues the reconcile of the NodePools affected by the job and resource events of a notification.
+// The reconcile queries the hardware manager for the state of the jobs and resources, so the content of a notification
+// is only used to identify the NodePools, and a forged notification at most causes an extra reconcile.
+func (a *Adaptor) handleNotification(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := r.PathValue("hwmgr")
+
+	hwmgr := &pluginv1alpha1.HardwareManager{}
+	if err := a.Client.Get(ctx, types.NamespacedName{Namespace: a.Namespace, Name: name}, hwmgr); err != nil {
+		if errors.IsNotFound(err) {
+			http.Error(w, "unknown hardware manager", http.StatusNotFound)
+			return
+		}
+		a.Logger.ErrorContext(ctx, "Failed to get HardwareManager for notification",
+			slog.String("hwmgr", name), slog.String("error", err.Error()))
+		http.Error(w, "failed to get hardware manager", http.StatusInternalServerError)
+		return
+	}
+
+	if hwmgr.Spec.AdaptorID != pluginv1alpha1.SupportedAdaptors.Dell {
+		http.Error(w, "unknown hardware manager", http.StatusNotFound)
+		return
+	}
+
+	var notification hwmgrclient.Notification
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxNotificationSize)).Decode(&notification); err != nil {
+		http.Error(w, "malformed notification", http.StatusBadRequest)
+		return
+	}
+
+	// A subscription left behind by a deleted HardwareManager of the same name has a different ID
+	if notification.SubscriptionId != hwmgrclient.GetSubscriptionId(hwmgr) {
+		http.Error(w, "unknown subscription", http.StatusNotFound)
+		return
+	}
+
+	nodepools, err := a.findNotifiedNodePools(ctx, hwmgr, notification.Events)
+	if err != nil {
+		a.Logger.ErrorContext(ctx, "Failed to process notification",
+			slog.String("hwmgr", name), slog.String("error", err.Error()))
+		http.Error(w, "failed to process notification", http.StatusInternalServerError)
+		return
+	}
+
+	a.Logger.InfoContext(ctx, "Received notification", slog.String("hwmgr", name),
+		slog.Int("events", len(notification.Events)), slog.Int("nodepools", len(nodepools)))
+	for _, nodepool := range nodepools {
+		a.receiver.EnqueueNodePool(nodepool)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findNotifiedNodePools returns the NodePools of the HardwareManager affected by the events. A job event affects the
+// NodePool or Node with the job in progress, while a resource event affects the NodePool of the Node allocated the
+// resource.
+func (a *Adaptor) findNotifiedNodePools(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	events []hwmgrclient.NotificationEvent) ([]types.NamespacedName, error) {
+
+	jobs := sets.New[string]()
+	resources := sets.New[string]()
+	for _, event := range events {
+		switch {
+		case event.Type == hwmgrclient.NotificationEventTypeJob && event.JobId != "":
+			jobs.Insert(event.JobId)
+		case event.Type == hwmgrclient.NotificationEventTypeResource && event.ResourceId != "":
+			resources.Insert(event.ResourceId)
+		default:
+			a.Logger.InfoContext(ctx, "Ignoring unrecognized notification event", slog.String("type", event.Type))
+		}
+	}
+
+	affected := sets.New[types.NamespacedName]()
+
+	if jobs.Len() > 0 {
+		var nodepools hwmgmtv1alpha1.NodePoolList
+		if err := a.Client.List(ctx, &nodepools, client.InNamespace(a.Namespace),
+			client.MatchingFields{utils.NodePoolSpecHwMgrIdKey: hwmgr.Name}); err != nil {
+			return nil, fmt.Errorf("failed to list NodePools: %w", err)
+		}
+		for i := range nodepools.Items {
+			nodepool := &nodepools.Items[i]
+			if jobs.Has(utils.GetJobId(nodepool)) || jobs.Has(utils.GetDeleteJobId(nodepool)) {
+				affected.Insert(client.ObjectKeyFromObject(nodepool))
+			}
+		}
+	}
+
+	if jobs.Len() > 0 || resources.Len() > 0 {
+		var nodes hwmgmtv1alpha1.NodeList
+		if err := a.Client.List(ctx, &nodes, client.InNamespace(a.Namespace)); err != nil {
+			return nil, fmt.Errorf("failed to list Nodes: %w", err)
+		}
+		for i := range nodes.Items {
+			node := &nodes.Items[i]
+			if node.Spec.HwMgrId != hwmgr.Name {
+				continue
+			}
+			if resources.Has(node.Spec.HwMgrNodeId) || jobs.Has(utils.GetJobId(node)) {
+				affected.Insert(types.NamespacedName{Namespace: node.Namespace, Name: node.Spec.NodePool})
+			}
+		}
+	}
+
+	nodepools := affected.UnsortedList()
+	slices.SortFunc(nodepools, func(x, y types.NamespacedName) int {
+		return strings.Compare(x.String(), y.String())
+	})
+	return nodepools, nil
+}
+
+// jobPollRequeue returns the requeue for polling the status of a job in progress on the hardware manager. While the
+// HardwareManager is subscribed to notifications, a change to the job status triggers a reconcile, so the status is
+// only polled at the fallback interval in case a notification is lost.
+func jobPollRequeue(hwmgr *pluginv1alpha1.HardwareManager) ctrl.Result {
+	if hwmgr.Spec.DellData == nil || hwmgr.Spec.DellData.Notifications == nil ||
+		!meta.IsStatusConditionTrue(hwmgr.Status.Conditions, string(pluginv1alpha1.ConditionTypes.Subscribed)) {
+		return utils.RequeueWithShortInterval()
+	}
+
+	if interval := hwmgr.Spec.DellData.Notifications.FallbackPollInterval; interval != nil && interval.Duration > 0 {
+		return utils.RequeueWithCustomInterval(interval.Duration)
+	}
+	return utils.RequeueWithCustomInterval(defaultFallbackPollInterval)
+}
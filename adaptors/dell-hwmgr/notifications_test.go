@@ -0,0 +1,140 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dellhwmgr
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/hwmgrclient"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/notifications"
+)
+
+var _ = Describe("Dell hardware manager notifications", func() {
+	var (
+		receiver *notifications.Receiver
+		server   *httptest.Server
+		hwmgr    *pluginv1alpha1.HardwareManager
+	)
+
+	notify := func(name string, notification hwmgrclient.Notification) int {
+		body, err := json.Marshal(notification)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := http.Post(server.URL+notifications.Path(pluginv1alpha1.SupportedAdaptors.Dell, name),
+			"application/json", bytes.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	notified := func() []string {
+		var names []string
+		for {
+			select {
+			case evt := <-receiver.NodePoolEvents():
+				names = append(names, evt.Object.GetName())
+			default:
+				return names
+			}
+		}
+	}
+
+	BeforeEach(func() {
+		hwmgr = &pluginv1alpha1.HardwareManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "dell-1", Namespace: testNamespace, UID: "uid-1"},
+			Spec: pluginv1alpha1.HardwareManagerSpec{
+				AdaptorID: pluginv1alpha1.SupportedAdaptors.Dell,
+				DellData: &pluginv1alpha1.DellData{
+					ApiUrl:        "https://dell.example.com",
+					Notifications: &pluginv1alpha1.DellNotifications{CallbackUrl: "https://plugin.example.com"},
+				},
+			},
+		}
+
+		fakeClient := newTestClient(hwmgr,
+			newNodePool("np-creating", "job-1"),
+			newNodePool("np-idle", ""),
+			newNodePool("np-updating", ""),
+			newNodePool("np-failing", ""),
+			newNode("node-1", "np-updating", "resource-1", "job-2"),
+			newNode("node-2", "np-failing", "resource-2", ""),
+			newNode("node-3", "np-idle", "resource-3", ""))
+
+		logger := slog.New(slog.NewTextHandler(GinkgoWriter, nil))
+		receiver = notifications.NewReceiver(notifications.Options{}, logger)
+		adaptor := NewAdaptor(fakeClient, fakeClient.Scheme(), logger, testNamespace)
+		adaptor.SetupNotifications(receiver)
+
+		server = httptest.NewServer(receiver)
+		DeferCleanup(server.Close)
+	})
+
+	It("enqueues the NodePools affected by the job and resource events", func() {
+		Expect(notify("dell-1", hwmgrclient.Notification{
+			SubscriptionId: hwmgrclient.GetSubscriptionId(hwmgr),
+			Events: []hwmgrclient.NotificationEvent{
+				{Type: hwmgrclient.NotificationEventTypeJob, JobId: "job-1", Status: "Completed"},
+				{Type: hwmgrclient.NotificationEventTypeJob, JobId: "job-2", Status: "Completed"},
+				{Type: hwmgrclient.NotificationEventTypeResource, ResourceId: "resource-2", Status: "Failed"},
+				{Type: hwmgrclient.NotificationEventTypeJob, JobId: "unknown-job"},
+				{Type: hwmgrclient.NotificationEventTypeJob},
+				{Type: "Unknown", ResourceId: "resource-3"},
+			},
+		})).To(Equal(http.StatusNoContent))
+
+		Expect(notified()).To(Equal([]string{"np-creating", "np-failing", "np-updating"}))
+	})
+
+	It("rejects notifications for unknown hardware managers and subscriptions", func() {
+		event := hwmgrclient.NotificationEvent{Type: hwmgrclient.NotificationEventTypeJob, JobId: "job-1"}
+
+		Expect(notify("dell-2", hwmgrclient.Notification{
+			SubscriptionId: hwmgrclient.GetSubscriptionId(hwmgr),
+			Events:         []hwmgrclient.NotificationEvent{event},
+		})).To(Equal(http.StatusNotFound))
+
+		Expect(notify("dell-1", hwmgrclient.Notification{
+			SubscriptionId: "oran-hwmgr-plugin-uid-0",
+			Events:         []hwmgrclient.NotificationEvent{event},
+		})).To(Equal(http.StatusNotFound))
+
+		Expect(notified()).To(BeEmpty())
+	})
+
+	It("polls jobs at the fallback interval only while subscribed", func() {
+		Expect(jobPollRequeue(hwmgr)).To(Equal(utils.RequeueWithShortInterval()))
+
+		utils.SetStatusCondition(&hwmgr.Status.Conditions,
+			string(pluginv1alpha1.ConditionTypes.Subscribed),
+			string(pluginv1alpha1.ConditionReasons.Completed),
+			metav1.ConditionTrue, "Subscribed")
+		Expect(jobPollRequeue(hwmgr)).To(Equal(utils.RequeueWithCustomInterval(defaultFallbackPollInterval)))
+
+		hwmgr.Spec.DellData.Notifications.FallbackPollInterval = &metav1.Duration{Duration: 10 * time.Minute}
+		Expect(jobPollRequeue(hwmgr)).To(Equal(utils.RequeueWithCustomInterval(10 * time.Minute)))
+	})
+})
@@ -0,0 +1,82 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dellhwmgr
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+)
+
+func TestDellAdaptor(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Dell Adaptor Suite")
+}
+
+const testNamespace = "oran-hwmgr-plugin"
+
+func newNodePool(name, jobId string) *hwmgmtv1alpha1.NodePool {
+	nodepool := &hwmgmtv1alpha1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec:       hwmgmtv1alpha1.NodePoolSpec{HwMgrId: "dell-1"},
+	}
+	if jobId != "" {
+		utils.SetJobId(nodepool, jobId)
+	}
+	return nodepool
+}
+
+func newNode(name, nodepool, resourceId, jobId string) *hwmgmtv1alpha1.Node {
+	node := &hwmgmtv1alpha1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec:       hwmgmtv1alpha1.NodeSpec{NodePool: nodepool, HwMgrId: "dell-1", HwMgrNodeId: resourceId},
+	}
+	if jobId != "" {
+		utils.SetJobId(node, jobId)
+	}
+	return node
+}
+
+// newTestClient builds a fake client with the field indexes set up by the NodePool controller
+func newTestClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	Expect(pluginv1alpha1.AddToScheme(scheme)).To(Succeed())
+	Expect(hwmgmtv1alpha1.AddToScheme(scheme)).To(Succeed())
+	Expect(corev1.AddToScheme(scheme)).To(Succeed())
+
+	return fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&hwmgmtv1alpha1.NodePool{}, &hwmgmtv1alpha1.Node{}).
+		WithIndex(&hwmgmtv1alpha1.NodePool{}, utils.NodePoolSpecHwMgrIdKey, func(obj client.Object) []string {
+			return []string{obj.(*hwmgmtv1alpha1.NodePool).Spec.HwMgrId}
+		}).
+		WithIndex(&hwmgmtv1alpha1.Node{}, utils.NodeSpecNodePoolKey, func(obj client.Object) []string {
+			return []string{obj.(*hwmgmtv1alpha1.Node).Spec.NodePool}
+		}).
+		WithObjects(objects...).
+		Build()
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+// DefaultDriftCheckInterval is the time between drift checks of a provisioned NodePool when its HardwareManager does not
+// specify an interval
+const DefaultDriftCheckInterval = 10 * time.Minute
+
+// driftRemediationInterval is the time before a NodePool whose provisioning was restarted to remediate a drift is
+// processed
+const driftRemediationInterval = 15 * time.Second
+
+// driftTracker records the time of the last drift check of each NodePool, so that the hardware manager is queried at
+// the configured interval regardless of how often the NodePool is reconciled
+type driftTracker struct {
+	sync.Mutex
+	lastChecked map[types.UID]time.Time
+}
+
+// remaining returns the time until the next drift check of the NodePool is due
+func (t *driftTracker) remaining(uid types.UID, interval time.Duration) time.Duration {
+	t.Lock()
+	defer t.Unlock()
+
+	lastChecked, exists := t.lastChecked[uid]
+	if !exists {
+		return 0
+	}
+	return interval - time.Since(lastChecked)
+}
+
+// record sets the time of the last drift check of the NodePool
+func (t *driftTracker) record(uid types.UID, checked time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.lastChecked == nil {
+		t.lastChecked = make(map[types.UID]time.Time)
+	}
+	t.lastChecked[uid] = checked
+}
+
+// reset clears the time of the last drift check of the NodePool, so that it is checked once provisioned again
+func (t *driftTracker) reset(uid types.UID) {
+	t.Lock()
+	defer t.Unlock()
+
+	delete(t.lastChecked, uid)
+}
+
+// getDriftCheckInterval returns the time between drift checks of the NodePools of the HardwareManager
+func getDriftCheckInterval(hwmgr *pluginv1alpha1.HardwareManager) time.Duration {
+	if hwmgr.Spec.DriftDetection != nil && hwmgr.Spec.DriftDetection.Interval != nil &&
+		hwmgr.Spec.DriftDetection.Interval.Duration > 0 {
+		return hwmgr.Spec.DriftDetection.Interval.Duration
+	}
+	return DefaultDriftCheckInterval
+}
+
+// recordEvent emits an event for the NodePool, if an event recorder is configured
+func (c *HwMgrAdaptorController) recordEvent(nodepool *hwmgmtv1alpha1.NodePool, eventtype, reason, message string) {
+	if c.Recorder != nil {
+		c.Recorder.Event(nodepool, eventtype, reason, message)
+	}
+}
+
+// checkNodePoolDrift periodically compares a provisioned NodePool with its allocation in the hardware manager, for
+// adaptors that support it, and reports the result via the InSync condition. A detected drift is remediated if enabled
+// for the HardwareManager, by releasing the missing nodes and restarting the provisioning of the NodePool. Returns the
+// time until the next check.
+func (c *HwMgrAdaptorController) checkNodePoolDrift(
+	ctx context.Context,
+	adaptor adaptorinterface.HwMgrAdaptorIntf,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (time.Duration, error) {
+
+	handler, ok := adaptor.(adaptorinterface.NodePoolDriftHandler)
+	if !ok || !utils.IsNodePoolProvisionedCompleted(nodepool) || findPendingCondition(nodepool) != nil {
+		return 0, nil
+	}
+
+	interval := getDriftCheckInterval(hwmgr)
+	if remaining := c.drift.remaining(nodepool.UID, interval); remaining > 0 {
+		return remaining, nil
+	}
+
+	c.Logger.InfoContext(ctx, "Checking NodePool for drift")
+	drift, err := handler.CheckNodePoolDrift(ctx, hwmgr, nodepool)
+	c.drift.record(nodepool.UID, time.Now())
+	if err != nil {
+		return interval, fmt.Errorf("failed to check drift for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	previous := meta.FindStatusCondition(nodepool.Status.Conditions, string(utils.NodePoolInSync))
+
+	if drift.InSync() {
+		if previous != nil && previous.Status != metav1.ConditionTrue {
+			c.recordEvent(nodepool, corev1.EventTypeNormal, string(utils.NodePoolSynced),
+				"NodePool matches its allocation in the hardware manager")
+		}
+		if err := utils.UpdateNodePoolStatusCondition(ctx, c.Client, nodepool,
+			utils.NodePoolInSync, utils.NodePoolSynced, metav1.ConditionTrue,
+			"NodePool matches its allocation in the hardware manager"); err != nil {
+			return interval, fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+		}
+		return interval, nil
+	}
+
+	message := drift.String()
+	c.Logger.InfoContext(ctx, "NodePool drift detected", slog.String("drift", message))
+
+	if previous == nil || previous.Message != message {
+		c.recordEvent(nodepool, corev1.EventTypeWarning, string(utils.NodePoolDriftDetected), message)
+	}
+	if err := utils.UpdateNodePoolStatusCondition(ctx, c.Client, nodepool,
+		utils.NodePoolInSync, utils.NodePoolDriftDetected, metav1.ConditionFalse, message); err != nil {
+		return interval, fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	if hwmgr.Spec.DriftDetection == nil || !hwmgr.Spec.DriftDetection.AutoRemediate {
+		return interval, nil
+	}
+
+	c.Logger.InfoContext(ctx, "Remediating NodePool drift")
+	if err := handler.RemediateNodePoolDrift(ctx, hwmgr, nodepool, drift); err != nil {
+		return interval, fmt.Errorf("failed to remediate drift for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	c.recordEvent(nodepool, corev1.EventTypeNormal, "DriftRemediation", "Reprovisioning NodePool: "+message)
+	if err := utils.ResetNodePoolStatusCondition(ctx, c.Client, nodepool,
+		hwmgmtv1alpha1.Provisioned, hwmgmtv1alpha1.InProgress, metav1.ConditionFalse,
+		"Reprovisioning after drift: "+message); err != nil {
+		return interval, fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	// Check the NodePool again once it has been provisioned
+	c.drift.reset(nodepool.UID)
+
+	return driftRemediationInterval, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+var _ = Describe("NodePool drift detection", func() {
+	It("takes the check interval from the HardwareManager", func() {
+		hwmgr := &pluginv1alpha1.HardwareManager{}
+		Expect(getDriftCheckInterval(hwmgr)).To(Equal(DefaultDriftCheckInterval))
+
+		hwmgr.Spec.DriftDetection = &pluginv1alpha1.DriftDetection{AutoRemediate: true}
+		Expect(getDriftCheckInterval(hwmgr)).To(Equal(DefaultDriftCheckInterval))
+
+		hwmgr.Spec.DriftDetection.Interval = &metav1.Duration{Duration: time.Minute}
+		Expect(getDriftCheckInterval(hwmgr)).To(Equal(time.Minute))
+	})
+
+	It("schedules checks at the interval", func() {
+		tracker := driftTracker{}
+		uid := types.UID("a")
+		Expect(tracker.remaining(uid, time.Hour)).To(BeZero())
+
+		tracker.record(uid, time.Now().Add(-time.Minute))
+		Expect(tracker.remaining(uid, time.Hour)).To(BeNumerically("~", 59*time.Minute, time.Second))
+		Expect(tracker.remaining(uid, time.Second)).To(BeNumerically("<=", 0))
+
+		tracker.reset(uid)
+		Expect(tracker.remaining(uid, time.Hour)).To(BeZero())
+	})
+
+	It("describes the drift", func() {
+		drift := &adaptorinterface.NodePoolDrift{}
+		Expect(drift.InSync()).To(BeTrue())
+		Expect(drift.String()).To(BeEmpty())
+
+		drift.MissingNodes = []string{"node-a", "node-b"}
+		drift.UnexpectedNodes = []string{"id-c"}
+		Expect(drift.InSync()).To(BeFalse())
+		Expect(drift.String()).To(Equal("nodes no longer allocated: node-a, node-b; allocated nodes without Node CR: id-c"))
+	})
+})
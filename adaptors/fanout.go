@@ -0,0 +1,398 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+// A NodePool with nodegroups assigned to more than one hardware manager is fanned out to a child NodePool per hardware
+// manager, each holding the nodegroups of that hardware manager. The children are handled by their adaptors like any
+// other NodePool, and their results are aggregated into the status of the parent NodePool.
+
+// getNodeGroupsByHwMgr returns the nodegroups of the NodePool keyed by their assigned hardware manager
+func getNodeGroupsByHwMgr(nodepool *hwmgmtv1alpha1.NodePool) map[string][]hwmgmtv1alpha1.NodeGroup {
+	nodegroups := make(map[string][]hwmgmtv1alpha1.NodeGroup)
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		hwMgrId := utils.GetNodeGroupHwMgrId(nodepool, nodegroup.NodePoolData.Name)
+		nodegroups[hwMgrId] = append(nodegroups[hwMgrId], nodegroup)
+	}
+	return nodegroups
+}
+
+// hasNodeGroupHwMgrOverride checks whether any nodegroup is assigned to a hardware manager other than spec.hwMgrId
+func hasNodeGroupHwMgrOverride(nodepool *hwmgmtv1alpha1.NodePool) bool {
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		if utils.GetNodeGroupHwMgrId(nodepool, nodegroup.NodePoolData.Name) != nodepool.Spec.HwMgrId {
+			return true
+		}
+	}
+	return false
+}
+
+func childNodePoolName(nodepool *hwmgmtv1alpha1.NodePool, hwMgrId string) string {
+	return fmt.Sprintf("%s-%s", nodepool.Name, hwMgrId)
+}
+
+// childCloudID returns the cloud ID of the child NodePool for a hardware manager. Adaptors key their allocations by cloud
+// ID, so each child needs its own, even when the children share a backend such as the loopback nodelist or a Dell
+// hardware manager instance.
+func childCloudID(nodepool *hwmgmtv1alpha1.NodePool, hwMgrId string) string {
+	return fmt.Sprintf("%s-%s", nodepool.Spec.CloudID, hwMgrId)
+}
+
+// newChildNodePool builds the child NodePool for the nodegroups assigned to a hardware manager. The nodegroup
+// overrides are not copied, so the child is handled directly by the adaptor of its hardware manager.
+func newChildNodePool(nodepool *hwmgmtv1alpha1.NodePool, hwMgrId string, nodegroups []hwmgmtv1alpha1.NodeGroup) *hwmgmtv1alpha1.NodePool {
+	child := &hwmgmtv1alpha1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      childNodePoolName(nodepool, hwMgrId),
+			Namespace: nodepool.Namespace,
+			Labels: map[string]string{
+				utils.ParentNodePoolLabel: nodepool.Name,
+			},
+		},
+		Spec: *nodepool.Spec.DeepCopy(),
+	}
+
+	child.Spec.HwMgrId = hwMgrId
+	child.Spec.CloudID = childCloudID(nodepool, hwMgrId)
+	child.Spec.NodeGroup = slices.Clone(nodegroups)
+	child.Spec.Extensions = nil
+	for key, value := range nodepool.Spec.Extensions {
+		if strings.HasPrefix(key, utils.NodeGroupHwMgrIdKeyPrefix) {
+			continue
+		}
+		if child.Spec.Extensions == nil {
+			child.Spec.Extensions = make(map[string]string)
+		}
+		child.Spec.Extensions[key] = value
+	}
+
+	return child
+}
+
+// findMovedNodeGroups returns a description of each nodegroup that is assigned to a different hardware manager than
+// the child NodePool that holds it. Moving an allocated nodegroup between hardware managers is not supported.
+func findMovedNodeGroups(nodepool *hwmgmtv1alpha1.NodePool, children []hwmgmtv1alpha1.NodePool) []string {
+	var moved []string
+	for _, child := range children {
+		for _, nodegroup := range child.Spec.NodeGroup {
+			groupname := nodegroup.NodePoolData.Name
+			if !slices.ContainsFunc(nodepool.Spec.NodeGroup, func(ng hwmgmtv1alpha1.NodeGroup) bool {
+				return ng.NodePoolData.Name == groupname
+			}) {
+				// The nodegroup has been removed
+				continue
+			}
+
+			if hwMgrId := utils.GetNodeGroupHwMgrId(nodepool, groupname); hwMgrId != child.Spec.HwMgrId {
+				moved = append(moved,
+					fmt.Sprintf("move of nodegroup %s from %s to %s", groupname, child.Spec.HwMgrId, hwMgrId))
+			}
+		}
+	}
+	return moved
+}
+
+// conditionRank orders conditions for aggregation, with a failure taking precedence over work in progress, and work
+// in progress taking precedence over completion
+func conditionRank(condition *metav1.Condition) int {
+	switch {
+	case condition.Reason == string(hwmgmtv1alpha1.Failed), condition.Reason == string(hwmgmtv1alpha1.TimedOut):
+		return 2
+	case condition.Status != metav1.ConditionTrue:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// aggregateCondition combines a condition of the child NodePools into a single condition for the parent. The result
+// takes the status and reason of the least progressed child, with the messages of each child in that state. A child
+// without the condition is considered pending if the condition is required, and is otherwise ignored. Returns nil if
+// no condition applies.
+func aggregateCondition(
+	conditionType hwmgmtv1alpha1.ConditionType,
+	required bool,
+	children []hwmgmtv1alpha1.NodePool) *metav1.Condition {
+
+	var aggregate *metav1.Condition
+	var messages []string
+
+	for i := range children {
+		condition := meta.FindStatusCondition(children[i].Status.Conditions, string(conditionType))
+		if condition == nil {
+			if !required {
+				continue
+			}
+			condition = &metav1.Condition{
+				Type:    string(conditionType),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(hwmgmtv1alpha1.InProgress),
+				Message: "Pending",
+			}
+		}
+
+		message := fmt.Sprintf("%s: %s", children[i].Spec.HwMgrId, condition.Message)
+		switch {
+		case aggregate == nil || conditionRank(condition) > conditionRank(aggregate):
+			aggregate = condition.DeepCopy()
+			messages = []string{message}
+		case conditionRank(condition) == conditionRank(aggregate):
+			messages = append(messages, message)
+		}
+	}
+
+	if aggregate == nil {
+		return nil
+	}
+
+	aggregate.Message = strings.Join(messages, "; ")
+	return aggregate
+}
+
+// getChildNodePools gets the per-hardware manager child NodePools of the NodePool, sorted by hardware manager
+func (c *HwMgrAdaptorController) getChildNodePools(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) ([]hwmgmtv1alpha1.NodePool, error) {
+	list := &hwmgmtv1alpha1.NodePoolList{}
+	if err := c.Client.List(ctx, list,
+		client.InNamespace(nodepool.Namespace),
+		client.MatchingLabels{utils.ParentNodePoolLabel: nodepool.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list child NodePools of %s: %w", nodepool.Name, err)
+	}
+
+	slices.SortFunc(list.Items, func(a, b hwmgmtv1alpha1.NodePool) int {
+		return strings.Compare(a.Spec.HwMgrId, b.Spec.HwMgrId)
+	})
+
+	return list.Items, nil
+}
+
+// isFannedOut checks whether the NodePool is handled by child NodePools, either because it has nodegroups assigned
+// to other hardware managers or because its children have already been created
+func (c *HwMgrAdaptorController) isFannedOut(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) (bool, []hwmgmtv1alpha1.NodePool, error) {
+	children, err := c.getChildNodePools(ctx, nodepool)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return len(children) > 0 || hasNodeGroupHwMgrOverride(nodepool), children, nil
+}
+
+// syncChildNodePools creates, updates or deletes the child NodePools to match the nodegroup assignments of the
+// NodePool. A child whose nodegroups have all been removed is deleted, releasing its nodes.
+func (c *HwMgrAdaptorController) syncChildNodePools(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	children []hwmgmtv1alpha1.NodePool) error {
+
+	nodegroups := getNodeGroupsByHwMgr(nodepool)
+
+	for hwMgrId, groups := range nodegroups {
+		desired := newChildNodePool(nodepool, hwMgrId, groups)
+
+		current := &hwmgmtv1alpha1.NodePool{}
+		err := c.Client.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, current)
+		if errors.IsNotFound(err) {
+			c.Logger.InfoContext(ctx, "Creating child NodePool",
+				slog.String("child", desired.Name),
+				slog.String("hwmgr", hwMgrId))
+
+			if err := controllerutil.SetControllerReference(nodepool, desired, c.Scheme); err != nil {
+				return fmt.Errorf("failed to set owner of child NodePool %s: %w", desired.Name, err)
+			}
+			if err := c.Client.Create(ctx, desired); err != nil {
+				return fmt.Errorf("failed to create child NodePool %s: %w", desired.Name, err)
+			}
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to get child NodePool %s: %w", desired.Name, err)
+		}
+
+		if equality.Semantic.DeepEqual(current.Spec, desired.Spec) {
+			continue
+		}
+
+		c.Logger.InfoContext(ctx, "Updating child NodePool", slog.String("child", current.Name))
+		current.Spec = desired.Spec
+		if err := c.Client.Update(ctx, current); err != nil {
+			return fmt.Errorf("failed to update child NodePool %s: %w", current.Name, err)
+		}
+	}
+
+	for i := range children {
+		if _, exists := nodegroups[children[i].Spec.HwMgrId]; exists {
+			continue
+		}
+
+		c.Logger.InfoContext(ctx, "Deleting child NodePool with no remaining nodegroups", slog.String("child", children[i].Name))
+		if err := c.Client.Delete(ctx, &children[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete child NodePool %s: %w", children[i].Name, err)
+		}
+	}
+
+	return nil
+}
+
+// updateFannedOutNodePoolStatus aggregates the status of the child NodePools into the NodePool
+func (c *HwMgrAdaptorController) updateFannedOutNodePoolStatus(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	children []hwmgmtv1alpha1.NodePool) error {
+
+	var nodenames []string
+	for _, child := range children {
+		nodenames = append(nodenames, child.Status.Properties.NodeNames...)
+	}
+
+	if !slices.Equal(nodepool.Status.Properties.NodeNames, nodenames) {
+		nodepool.Status.Properties.NodeNames = nodenames
+		if err := utils.UpdateNodePoolProperties(ctx, c.Client, nodepool); err != nil {
+			return fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+		}
+	}
+
+	for _, aggregation := range []struct {
+		conditionType hwmgmtv1alpha1.ConditionType
+		required      bool
+	}{
+		{conditionType: hwmgmtv1alpha1.Provisioned, required: true},
+		{conditionType: hwmgmtv1alpha1.Configured, required: false},
+		{conditionType: utils.NodePoolScaled, required: false},
+		{conditionType: utils.NodePoolInSync, required: false},
+	} {
+		condition := aggregateCondition(aggregation.conditionType, aggregation.required, children)
+		if condition == nil {
+			continue
+		}
+
+		if err := utils.UpdateNodePoolStatusCondition(ctx, c.Client, nodepool,
+			aggregation.conditionType,
+			hwmgmtv1alpha1.ConditionReason(condition.Reason),
+			condition.Status,
+			condition.Message); err != nil {
+			return fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// handleFannedOutNodePool processes a NodePool with nodegroups assigned to more than one hardware manager. A new
+// spec is propagated to the child NodePools, unless it moves a nodegroup between hardware managers, and the status
+// of the children is then aggregated into the NodePool.
+func (c *HwMgrAdaptorController) handleFannedOutNodePool(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	children []hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	if nodepool.Generation != nodepool.Status.HwMgrPlugin.ObservedGeneration {
+		// Verify that each hardware manager exists before handing off the nodegroups
+		for hwMgrId := range getNodeGroupsByHwMgr(nodepool) {
+			hwmgr := &pluginv1alpha1.HardwareManager{}
+			if err := c.Client.Get(ctx, types.NamespacedName{Name: hwMgrId, Namespace: c.Namespace}, hwmgr); err != nil {
+				c.Logger.Error("failed to get adaptor instance", slog.String("hwmgr", hwMgrId), slog.String("error", err.Error()))
+
+				if err := utils.UpdateNodePoolStatusCondition(ctx, c.Client, nodepool,
+					hwmgmtv1alpha1.Provisioned, hwmgmtv1alpha1.Failed, metav1.ConditionFalse,
+					hwMgrNotFoundMessage+hwMgrId); err != nil {
+					return utils.RequeueWithMediumInterval(),
+						fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+				}
+
+				return utils.DoNotRequeue(), nil
+			}
+		}
+
+		if moved := findMovedNodeGroups(nodepool, children); len(moved) > 0 {
+			message := "Unsupported NodePool spec change: " + strings.Join(moved, ", ")
+			c.Logger.InfoContext(ctx, "Rejecting NodePool spec change", slog.String("reason", message))
+
+			if err := utils.UpdateNodePoolStatusCondition(ctx, c.Client, nodepool,
+				hwmgmtv1alpha1.Configured, hwmgmtv1alpha1.Failed, metav1.ConditionFalse, message); err != nil {
+				return utils.RequeueWithMediumInterval(),
+					fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+			}
+
+			if err := utils.UpdateNodePoolPluginStatus(ctx, c.Client, nodepool); err != nil {
+				return utils.RequeueWithShortInterval(),
+					fmt.Errorf("failed to update plugin status for NodePool %s: %w", nodepool.Name, err)
+			}
+
+			return utils.DoNotRequeue(), nil
+		}
+
+		if err := c.syncChildNodePools(ctx, nodepool, children); err != nil {
+			return utils.RequeueWithShortInterval(), err
+		}
+
+		if err := utils.UpdateNodePoolPluginStatus(ctx, c.Client, nodepool); err != nil {
+			return utils.RequeueWithShortInterval(),
+				fmt.Errorf("failed to update plugin status for NodePool %s: %w", nodepool.Name, err)
+		}
+
+		// Refresh the children after the sync
+		var err error
+		if children, err = c.getChildNodePools(ctx, nodepool); err != nil {
+			return utils.RequeueWithShortInterval(), err
+		}
+	}
+
+	if err := c.updateFannedOutNodePoolStatus(ctx, nodepool, children); err != nil {
+		return utils.RequeueWithMediumInterval(), err
+	}
+
+	if !controllerutil.ContainsFinalizer(nodepool, utils.NodepoolFinalizer) {
+		c.Logger.InfoContext(ctx, "Adding finalizer to NodePool")
+		if err := utils.NodepoolAddFinalizer(ctx, c.Client, nodepool); err != nil {
+			return utils.RequeueImmediately(), fmt.Errorf("failed to add finalizer to nodepool: %w", err)
+		}
+	}
+
+	// Changes to the child NodePools trigger a reconcile of the NodePool
+	return utils.DoNotRequeue(), nil
+}
+
+// handleFannedOutNodePoolDeletion deletes the child NodePools, each of which is released by its adaptor. The release
+// is complete once the children are gone, which triggers a reconcile of the parent as their owner.
+func (c *HwMgrAdaptorController) handleFannedOutNodePoolDeletion(ctx context.Context, children []hwmgmtv1alpha1.NodePool) error {
+	for i := range children {
+		c.Logger.InfoContext(ctx, "Deleting child NodePool", slog.String("child", children[i].Name))
+		if err := c.Client.Delete(ctx, &children[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete child NodePool %s: %w", children[i].Name, err)
+		}
+	}
+
+	return nil
+}
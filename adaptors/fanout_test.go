@@ -0,0 +1,207 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/loopback"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+func newTestChildNodePool(hwMgrId string, conditions ...metav1.Condition) hwmgmtv1alpha1.NodePool {
+	return hwmgmtv1alpha1.NodePool{
+		Spec:   hwmgmtv1alpha1.NodePoolSpec{HwMgrId: hwMgrId},
+		Status: hwmgmtv1alpha1.NodePoolStatus{Conditions: conditions},
+	}
+}
+
+var _ = Describe("NodePool fan-out", func() {
+	var nodepool *hwmgmtv1alpha1.NodePool
+
+	BeforeEach(func() {
+		nodepool = &hwmgmtv1alpha1.NodePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "np1", Namespace: "hwmgr"},
+			Spec: hwmgmtv1alpha1.NodePoolSpec{
+				CloudID: "cloud-1",
+				HwMgrId: "dell-1",
+				NodeGroup: []hwmgmtv1alpha1.NodeGroup{
+					{NodePoolData: hwmgmtv1alpha1.NodePoolData{Name: "controller"}, Size: 3},
+					{NodePoolData: hwmgmtv1alpha1.NodePoolData{Name: "worker"}, Size: 2},
+				},
+				Extensions: map[string]string{
+					utils.ResourceTypeIdKey: "rt-1",
+				},
+			},
+		}
+	})
+
+	It("assigns every nodegroup to spec.hwMgrId by default", func() {
+		Expect(hasNodeGroupHwMgrOverride(nodepool)).To(BeFalse())
+		Expect(getNodeGroupsByHwMgr(nodepool)).To(HaveLen(1))
+		Expect(getNodeGroupsByHwMgr(nodepool)["dell-1"]).To(HaveLen(2))
+	})
+
+	It("builds a child NodePool per hardware manager", func() {
+		nodepool.Spec.Extensions[utils.NodeGroupHwMgrIdKeyPrefix+"worker"] = "metal3-1"
+		Expect(hasNodeGroupHwMgrOverride(nodepool)).To(BeTrue())
+
+		nodegroups := getNodeGroupsByHwMgr(nodepool)
+		Expect(nodegroups).To(HaveLen(2))
+
+		child := newChildNodePool(nodepool, "metal3-1", nodegroups["metal3-1"])
+		Expect(child.Name).To(Equal("np1-metal3-1"))
+		Expect(child.Namespace).To(Equal("hwmgr"))
+		Expect(child.Labels).To(HaveKeyWithValue(utils.ParentNodePoolLabel, "np1"))
+		Expect(child.Spec.HwMgrId).To(Equal("metal3-1"))
+		Expect(child.Spec.CloudID).To(Equal("cloud-1-metal3-1"))
+		Expect(child.Spec.NodeGroup).To(HaveLen(1))
+		Expect(child.Spec.NodeGroup[0].NodePoolData.Name).To(Equal("worker"))
+		Expect(child.Spec.Extensions).To(Equal(map[string]string{utils.ResourceTypeIdKey: "rt-1"}))
+
+		// The parent is unchanged
+		Expect(nodepool.Spec.NodeGroup).To(HaveLen(2))
+		Expect(nodepool.Spec.Extensions).To(HaveLen(2))
+	})
+
+	It("keeps the loopback allocations of sibling children apart", func() {
+		ctx := context.Background()
+		nodepool.Spec.HwMgrId = "loopback-1"
+		nodepool.Spec.Extensions[utils.NodeGroupHwMgrIdKeyPrefix+"worker"] = "loopback-2"
+		nodegroups := getNodeGroupsByHwMgr(nodepool)
+		child1 := newChildNodePool(nodepool, "loopback-1", nodegroups["loopback-1"])
+		child2 := newChildNodePool(nodepool, "loopback-2", nodegroups["loopback-2"])
+
+		// Both children have allocations in the nodelist shared by the loopback hardware managers
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "loopback-adaptor-nodelist", Namespace: "hwmgr"},
+			Data: map[string]string{
+				"resources": "resourcepools: [master, worker]\nnodes: {}\n",
+				"allocations": fmt.Sprintf("clouds:\n"+
+					"- cloudID: %s\n  nodegroups:\n    controller: [node-1, node-2, node-3]\n"+
+					"- cloudID: %s\n  nodegroups:\n    worker: [node-4, node-5]\n",
+					child1.Spec.CloudID, child2.Spec.CloudID),
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		adaptor := loopback.NewAdaptor(fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build(),
+			scheme, slog.New(slog.NewTextHandler(GinkgoWriter, nil)), "hwmgr")
+
+		Expect(adaptor.GetAllocatedNodes(ctx, child1)).To(ConsistOf("node-1", "node-2", "node-3"))
+		Expect(adaptor.GetAllocatedNodes(ctx, child2)).To(ConsistOf("node-4", "node-5"))
+
+		// Deleting one child releases only its own allocation
+		Expect(adaptor.ReleaseNodePool(ctx, nil, child1)).To(Succeed())
+		Expect(adaptor.GetAllocatedNodes(ctx, child1)).To(BeEmpty())
+		Expect(adaptor.GetAllocatedNodes(ctx, child2)).To(ConsistOf("node-4", "node-5"))
+	})
+
+	It("rejects moving a nodegroup between hardware managers", func() {
+		children := []hwmgmtv1alpha1.NodePool{
+			*newChildNodePool(nodepool, "dell-1", nodepool.Spec.NodeGroup[:1]),
+			*newChildNodePool(nodepool, "metal3-1", nodepool.Spec.NodeGroup[1:]),
+		}
+		nodepool.Spec.Extensions[utils.NodeGroupHwMgrIdKeyPrefix+"worker"] = "metal3-1"
+		Expect(findMovedNodeGroups(nodepool, children)).To(BeEmpty())
+
+		nodepool.Spec.Extensions[utils.NodeGroupHwMgrIdKeyPrefix+"worker"] = "redfish-1"
+		Expect(findMovedNodeGroups(nodepool, children)).To(
+			ConsistOf("move of nodegroup worker from metal3-1 to redfish-1"))
+
+		// Removing a nodegroup is not a move
+		nodepool.Spec.NodeGroup = nodepool.Spec.NodeGroup[:1]
+		Expect(findMovedNodeGroups(nodepool, children)).To(BeEmpty())
+	})
+
+	It("aggregates the Provisioned condition of the children", func() {
+		provisioned := metav1.Condition{
+			Type:    string(hwmgmtv1alpha1.Provisioned),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(hwmgmtv1alpha1.Completed),
+			Message: "Created",
+		}
+		inProgress := metav1.Condition{
+			Type:    string(hwmgmtv1alpha1.Provisioned),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(hwmgmtv1alpha1.InProgress),
+			Message: "Handling creation",
+		}
+		failed := metav1.Condition{
+			Type:    string(hwmgmtv1alpha1.Provisioned),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(hwmgmtv1alpha1.Failed),
+			Message: "not enough free resources",
+		}
+
+		// A child that has not started is pending
+		condition := aggregateCondition(hwmgmtv1alpha1.Provisioned, true, []hwmgmtv1alpha1.NodePool{
+			newTestChildNodePool("dell-1"),
+		})
+		Expect(condition.Reason).To(Equal(string(hwmgmtv1alpha1.InProgress)))
+
+		condition = aggregateCondition(hwmgmtv1alpha1.Provisioned, true, []hwmgmtv1alpha1.NodePool{
+			newTestChildNodePool("dell-1", provisioned),
+			newTestChildNodePool("metal3-1"),
+		})
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(hwmgmtv1alpha1.InProgress)))
+		Expect(condition.Message).To(Equal("metal3-1: Pending"))
+
+		condition = aggregateCondition(hwmgmtv1alpha1.Provisioned, true, []hwmgmtv1alpha1.NodePool{
+			newTestChildNodePool("dell-1", inProgress),
+			newTestChildNodePool("metal3-1", failed),
+		})
+		Expect(condition.Reason).To(Equal(string(hwmgmtv1alpha1.Failed)))
+		Expect(condition.Message).To(Equal("metal3-1: not enough free resources"))
+
+		condition = aggregateCondition(hwmgmtv1alpha1.Provisioned, true, []hwmgmtv1alpha1.NodePool{
+			newTestChildNodePool("dell-1", provisioned),
+			newTestChildNodePool("metal3-1", provisioned),
+		})
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(hwmgmtv1alpha1.Completed)))
+		Expect(condition.Message).To(Equal("dell-1: Created; metal3-1: Created"))
+	})
+
+	It("ignores children without an optional condition", func() {
+		Expect(aggregateCondition(hwmgmtv1alpha1.Configured, false, []hwmgmtv1alpha1.NodePool{
+			newTestChildNodePool("dell-1"),
+		})).To(BeNil())
+
+		condition := aggregateCondition(hwmgmtv1alpha1.Configured, false, []hwmgmtv1alpha1.NodePool{
+			newTestChildNodePool("dell-1"),
+			newTestChildNodePool("metal3-1", metav1.Condition{
+				Type:    string(hwmgmtv1alpha1.Configured),
+				Status:  metav1.ConditionTrue,
+				Reason:  string(hwmgmtv1alpha1.ConfigApplied),
+				Message: "Configuration has been applied successfully",
+			}),
+		})
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	})
+})
@@ -0,0 +1,186 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcadaptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	adaptorv1 "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/grpc/api/v1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/grpc/controller"
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/grpc/grpcclient"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+// AdaptorID is the ID of the adaptor that forwards requests to an out-of-process adaptor
+const AdaptorID pluginv1alpha1.HardwareManagerAdaptorID = "grpc"
+
+type Adaptor struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Logger    *slog.Logger
+	Namespace string
+	AdaptorID pluginv1alpha1.HardwareManagerAdaptorID
+	clients   *grpcclient.ClientCache
+}
+
+func NewAdaptor(client client.Client, scheme *runtime.Scheme, logger *slog.Logger, namespace string) *Adaptor {
+	return &Adaptor{
+		Client:    client,
+		Scheme:    scheme,
+		Logger:    logger.With("adaptor", string(AdaptorID)),
+		Namespace: namespace,
+		AdaptorID: AdaptorID,
+		clients:   grpcclient.NewClientCache(),
+	}
+}
+
+// SetupAdaptor sets up the gRPC adaptor
+func (a *Adaptor) SetupAdaptor(mgr ctrl.Manager) error {
+	a.Logger.Info("SetupAdaptor called for gRPC")
+
+	if err := (&controller.HardwareManagerReconciler{
+		Client:    a.Client,
+		Scheme:    a.Scheme,
+		Logger:    a.Logger,
+		Namespace: a.Namespace,
+		AdaptorID: a.AdaptorID,
+		Clients:   a.clients,
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to setup grpc adaptor: %w", err)
+	}
+
+	return nil
+}
+
+// encodeRequest encodes the HardwareManager and NodePool CRs for transfer to the adaptor
+func (a *Adaptor) encodeRequest(hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) ([]byte, []byte, error) {
+	hwmgrData, err := grpcclient.EncodeObject(a.Scheme, hwmgr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode hwmgr: %w", err)
+	}
+
+	nodepoolData, err := grpcclient.EncodeObject(a.Scheme, nodepool)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode nodepool: %w", err)
+	}
+
+	return hwmgrData, nodepoolData, nil
+}
+
+// HandleNodePool forwards the NodePool CR to the out-of-process adaptor
+func (a *Adaptor) HandleNodePool(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+	adaptorClient, err := a.clients.Get(ctx, a.Logger, a.Client, hwmgr)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to setup adaptor client: %w", err)
+	}
+
+	hwmgrData, nodepoolData, err := a.encodeRequest(hwmgr, nodepool)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, grpcclient.RequestTimeout)
+	defer cancel()
+
+	response, err := adaptorClient.HandleNodePool(ctx, &adaptorv1.HandleNodePoolRequest{
+		HardwareManager: hwmgrData,
+		NodePool:        nodepoolData,
+	})
+	if err != nil {
+		if grpcclient.IsTransient(err) {
+			return ctrl.Result{}, utils.NewTransientError("HandleNodePool request to %s failed: %w", hwmgr.Spec.GrpcData.Endpoint, err)
+		}
+		return ctrl.Result{}, fmt.Errorf("HandleNodePool request to %s failed: %w", hwmgr.Spec.GrpcData.Endpoint, err)
+	}
+
+	result := adaptorv1.ResultFromProto(response.GetResult())
+	if response.GetError() != "" {
+		return result, errors.New(response.GetError())
+	}
+
+	return result, nil
+}
+
+// HandleNodePoolDeletion forwards the NodePool CR deletion to the out-of-process adaptor
+func (a *Adaptor) HandleNodePoolDeletion(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (bool, error) {
+	a.Logger.InfoContext(ctx, "Finalizing nodepool")
+
+	adaptorClient, err := a.clients.Get(ctx, a.Logger, a.Client, hwmgr)
+	if err != nil {
+		return false, fmt.Errorf("failed to setup adaptor client: %w", err)
+	}
+
+	hwmgrData, nodepoolData, err := a.encodeRequest(hwmgr, nodepool)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, grpcclient.RequestTimeout)
+	defer cancel()
+
+	response, err := adaptorClient.HandleNodePoolDeletion(ctx, &adaptorv1.HandleNodePoolDeletionRequest{
+		HardwareManager: hwmgrData,
+		NodePool:        nodepoolData,
+	})
+	if err != nil {
+		return false, fmt.Errorf("HandleNodePoolDeletion request to %s failed: %w", hwmgr.Spec.GrpcData.Endpoint, err)
+	}
+
+	if response.GetError() != "" {
+		return false, errors.New(response.GetError())
+	}
+
+	return response.GetCompleted(), nil
+}
+
+// GetCapabilities queries the NodePool operations supported by the out-of-process adaptor
+func (a *Adaptor) GetCapabilities(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) (pluginv1alpha1.AdaptorCapabilities, error) {
+	adaptorClient, err := a.clients.Get(ctx, a.Logger, a.Client, hwmgr)
+	if err != nil {
+		return pluginv1alpha1.AdaptorCapabilities{}, fmt.Errorf("failed to setup adaptor client: %w", err)
+	}
+
+	capabilities, err := adaptorClient.GetAdaptorCapabilities(ctx, a.Scheme, hwmgr)
+	if err != nil {
+		return capabilities, fmt.Errorf("GetCapabilities request to %s failed: %w", hwmgr.Spec.GrpcData.Endpoint, err)
+	}
+
+	return capabilities, nil
+}
+
+// CheckReadiness verifies that the out-of-process adaptor is reachable and implements a supported protocol version
+func (a *Adaptor) CheckReadiness(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) error {
+	adaptorClient, err := a.clients.Get(ctx, a.Logger, a.Client, hwmgr)
+	if err != nil {
+		return fmt.Errorf("failed to setup adaptor client: %w", err)
+	}
+
+	if _, err := adaptorClient.Handshake(ctx); err != nil {
+		return fmt.Errorf("handshake with %s failed: %w", hwmgr.Spec.GrpcData.Endpoint, err)
+	}
+
+	return nil
+}
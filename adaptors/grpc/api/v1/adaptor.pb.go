@@ -0,0 +1,754 @@
+// Copyright 2024.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.1
+// 	protoc        v5.28.3
+// source: adaptor.proto
+
+package adaptorv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetInfoRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The protocol version implemented by the plugin
+	ProtocolVersion string `protobuf:"bytes,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetInfoRequest) Reset() {
+	*x = GetInfoRequest{}
+	mi := &file_adaptor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoRequest) ProtoMessage() {}
+
+func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adaptor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+	return file_adaptor_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetInfoRequest) GetProtocolVersion() string {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return ""
+}
+
+type GetInfoResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The protocol version implemented by the adaptor
+	ProtocolVersion string `protobuf:"bytes,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	// A descriptive name for the adaptor implementation
+	Name          string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInfoResponse) Reset() {
+	*x = GetInfoResponse{}
+	mi := &file_adaptor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoResponse) ProtoMessage() {}
+
+func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adaptor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetInfoResponse) Descriptor() ([]byte, []int) {
+	return file_adaptor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetInfoResponse) GetProtocolVersion() string {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return ""
+}
+
+func (x *GetInfoResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type HandleNodePoolRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The JSON encoded HardwareManager CR
+	HardwareManager []byte `protobuf:"bytes,1,opt,name=hardware_manager,json=hardwareManager,proto3" json:"hardware_manager,omitempty"`
+	// The JSON encoded NodePool CR
+	NodePool      []byte `protobuf:"bytes,2,opt,name=node_pool,json=nodePool,proto3" json:"node_pool,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandleNodePoolRequest) Reset() {
+	*x = HandleNodePoolRequest{}
+	mi := &file_adaptor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandleNodePoolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandleNodePoolRequest) ProtoMessage() {}
+
+func (x *HandleNodePoolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adaptor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandleNodePoolRequest.ProtoReflect.Descriptor instead.
+func (*HandleNodePoolRequest) Descriptor() ([]byte, []int) {
+	return file_adaptor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *HandleNodePoolRequest) GetHardwareManager() []byte {
+	if x != nil {
+		return x.HardwareManager
+	}
+	return nil
+}
+
+func (x *HandleNodePoolRequest) GetNodePool() []byte {
+	if x != nil {
+		return x.NodePool
+	}
+	return nil
+}
+
+// Result mirrors the controller-runtime reconcile result
+type Result struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Requeue tells the plugin to requeue the NodePool immediately
+	Requeue bool `protobuf:"varint,1,opt,name=requeue,proto3" json:"requeue,omitempty"`
+	// RequeueAfterSeconds tells the plugin to requeue the NodePool after the given interval, if non-zero
+	RequeueAfterSeconds int64 `protobuf:"varint,2,opt,name=requeue_after_seconds,json=requeueAfterSeconds,proto3" json:"requeue_after_seconds,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_adaptor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_adaptor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_adaptor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Result) GetRequeue() bool {
+	if x != nil {
+		return x.Requeue
+	}
+	return false
+}
+
+func (x *Result) GetRequeueAfterSeconds() int64 {
+	if x != nil {
+		return x.RequeueAfterSeconds
+	}
+	return 0
+}
+
+type HandleNodePoolResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Result *Result                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// A non-empty error indicates that the request failed. The result is still honoured.
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandleNodePoolResponse) Reset() {
+	*x = HandleNodePoolResponse{}
+	mi := &file_adaptor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandleNodePoolResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandleNodePoolResponse) ProtoMessage() {}
+
+func (x *HandleNodePoolResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adaptor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandleNodePoolResponse.ProtoReflect.Descriptor instead.
+func (*HandleNodePoolResponse) Descriptor() ([]byte, []int) {
+	return file_adaptor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *HandleNodePoolResponse) GetResult() *Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *HandleNodePoolResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type HandleNodePoolDeletionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The JSON encoded HardwareManager CR
+	HardwareManager []byte `protobuf:"bytes,1,opt,name=hardware_manager,json=hardwareManager,proto3" json:"hardware_manager,omitempty"`
+	// The JSON encoded NodePool CR
+	NodePool      []byte `protobuf:"bytes,2,opt,name=node_pool,json=nodePool,proto3" json:"node_pool,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandleNodePoolDeletionRequest) Reset() {
+	*x = HandleNodePoolDeletionRequest{}
+	mi := &file_adaptor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandleNodePoolDeletionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandleNodePoolDeletionRequest) ProtoMessage() {}
+
+func (x *HandleNodePoolDeletionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adaptor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandleNodePoolDeletionRequest.ProtoReflect.Descriptor instead.
+func (*HandleNodePoolDeletionRequest) Descriptor() ([]byte, []int) {
+	return file_adaptor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HandleNodePoolDeletionRequest) GetHardwareManager() []byte {
+	if x != nil {
+		return x.HardwareManager
+	}
+	return nil
+}
+
+func (x *HandleNodePoolDeletionRequest) GetNodePool() []byte {
+	if x != nil {
+		return x.NodePool
+	}
+	return nil
+}
+
+type HandleNodePoolDeletionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// A non-empty error indicates that the request failed
+	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// Completed indicates that the resources of the NodePool have been released. Otherwise, the plugin requeues the
+	// deletion and calls HandleNodePoolDeletion again. Added in protocol version v2.
+	Completed     bool `protobuf:"varint,2,opt,name=completed,proto3" json:"completed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandleNodePoolDeletionResponse) Reset() {
+	*x = HandleNodePoolDeletionResponse{}
+	mi := &file_adaptor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandleNodePoolDeletionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandleNodePoolDeletionResponse) ProtoMessage() {}
+
+func (x *HandleNodePoolDeletionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adaptor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HandleNodePoolDeletionResponse.ProtoReflect.Descriptor instead.
+func (*HandleNodePoolDeletionResponse) Descriptor() ([]byte, []int) {
+	return file_adaptor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HandleNodePoolDeletionResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *HandleNodePoolDeletionResponse) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+type GetCapabilitiesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The JSON encoded HardwareManager CR
+	HardwareManager []byte `protobuf:"bytes,1,opt,name=hardware_manager,json=hardwareManager,proto3" json:"hardware_manager,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesRequest) Reset() {
+	*x = GetCapabilitiesRequest{}
+	mi := &file_adaptor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesRequest) ProtoMessage() {}
+
+func (x *GetCapabilitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_adaptor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesRequest.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesRequest) Descriptor() ([]byte, []int) {
+	return file_adaptor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetCapabilitiesRequest) GetHardwareManager() []byte {
+	if x != nil {
+		return x.HardwareManager
+	}
+	return nil
+}
+
+// Capabilities mirrors the AdaptorCapabilities published in the HardwareManager status
+type Capabilities struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ProfileUpdate    bool                   `protobuf:"varint,1,opt,name=profile_update,json=profileUpdate,proto3" json:"profile_update,omitempty"`
+	ScaleOut         bool                   `protobuf:"varint,2,opt,name=scale_out,json=scaleOut,proto3" json:"scale_out,omitempty"`
+	ScaleIn          bool                   `protobuf:"varint,3,opt,name=scale_in,json=scaleIn,proto3" json:"scale_in,omitempty"`
+	PowerControl     bool                   `protobuf:"varint,4,opt,name=power_control,json=powerControl,proto3" json:"power_control,omitempty"`
+	NodeReplacement  bool                   `protobuf:"varint,5,opt,name=node_replacement,json=nodeReplacement,proto3" json:"node_replacement,omitempty"`
+	InventoryListing bool                   `protobuf:"varint,6,opt,name=inventory_listing,json=inventoryListing,proto3" json:"inventory_listing,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Capabilities) Reset() {
+	*x = Capabilities{}
+	mi := &file_adaptor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Capabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Capabilities) ProtoMessage() {}
+
+func (x *Capabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_adaptor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Capabilities.ProtoReflect.Descriptor instead.
+func (*Capabilities) Descriptor() ([]byte, []int) {
+	return file_adaptor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Capabilities) GetProfileUpdate() bool {
+	if x != nil {
+		return x.ProfileUpdate
+	}
+	return false
+}
+
+func (x *Capabilities) GetScaleOut() bool {
+	if x != nil {
+		return x.ScaleOut
+	}
+	return false
+}
+
+func (x *Capabilities) GetScaleIn() bool {
+	if x != nil {
+		return x.ScaleIn
+	}
+	return false
+}
+
+func (x *Capabilities) GetPowerControl() bool {
+	if x != nil {
+		return x.PowerControl
+	}
+	return false
+}
+
+func (x *Capabilities) GetNodeReplacement() bool {
+	if x != nil {
+		return x.NodeReplacement
+	}
+	return false
+}
+
+func (x *Capabilities) GetInventoryListing() bool {
+	if x != nil {
+		return x.InventoryListing
+	}
+	return false
+}
+
+type GetCapabilitiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Capabilities  *Capabilities          `protobuf:"bytes,1,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapabilitiesResponse) Reset() {
+	*x = GetCapabilitiesResponse{}
+	mi := &file_adaptor_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapabilitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapabilitiesResponse) ProtoMessage() {}
+
+func (x *GetCapabilitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_adaptor_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapabilitiesResponse.ProtoReflect.Descriptor instead.
+func (*GetCapabilitiesResponse) Descriptor() ([]byte, []int) {
+	return file_adaptor_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCapabilitiesResponse) GetCapabilities() *Capabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+var File_adaptor_proto protoreflect.FileDescriptor
+
+var file_adaptor_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x16, 0x68, 0x77, 0x6d, 0x67, 0x72, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x61, 0x64, 0x61,
+	0x70, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x22, 0x3b, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x22, 0x50, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x5f, 0x0a, 0x15, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65,
+	0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x29, 0x0a, 0x10, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x6d, 0x61, 0x6e, 0x61,
+	0x67, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x68, 0x61, 0x72, 0x64, 0x77,
+	0x61, 0x72, 0x65, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f,
+	0x64, 0x65, 0x5f, 0x70, 0x6f, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6e,
+	0x6f, 0x64, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x22, 0x56, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x75, 0x65, 0x12, 0x32, 0x0a, 0x15, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x13, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x75, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22,
+	0x66, 0x0a, 0x16, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x6f,
+	0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x68, 0x77, 0x6d, 0x67,
+	0x72, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x67, 0x0a, 0x1d, 0x48, 0x61, 0x6e, 0x64, 0x6c,
+	0x65, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x68, 0x61, 0x72, 0x64,
+	0x77, 0x61, 0x72, 0x65, 0x5f, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x0f, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x4d, 0x61, 0x6e, 0x61,
+	0x67, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x70, 0x6f, 0x6f, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x6f, 0x6c,
+	0x22, 0x54, 0x0a, 0x1e, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f,
+	0x6f, 0x6c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70,
+	0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6d,
+	0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x22, 0x43, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x43, 0x61, 0x70,
+	0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x29, 0x0a, 0x10, 0x68, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x5f, 0x6d, 0x61, 0x6e,
+	0x61, 0x67, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0f, 0x68, 0x61, 0x72, 0x64,
+	0x77, 0x61, 0x72, 0x65, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x72, 0x22, 0xea, 0x01, 0x0a, 0x0c,
+	0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e,
+	0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x5f, 0x6f, 0x75, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x4f, 0x75, 0x74,
+	0x12, 0x19, 0x0a, 0x08, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x5f, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x49, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x70,
+	0x6f, 0x77, 0x65, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0c, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x12, 0x29, 0x0a, 0x10, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x6e, 0x6f, 0x64, 0x65,
+	0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x69,
+	0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x69, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72,
+	0x79, 0x4c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x22, 0x63, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x43,
+	0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x48, 0x0a, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x68, 0x77, 0x6d, 0x67,
+	0x72, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52,
+	0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x32, 0xd9, 0x03,
+	0x0a, 0x0c, 0x48, 0x77, 0x4d, 0x67, 0x72, 0x41, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x12, 0x5a,
+	0x0a, 0x07, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x26, 0x2e, 0x68, 0x77, 0x6d, 0x67,
+	0x72, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x27, 0x2e, 0x68, 0x77, 0x6d, 0x67, 0x72, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e,
+	0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e,
+	0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6f, 0x0a, 0x0e, 0x48, 0x61,
+	0x6e, 0x64, 0x6c, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x12, 0x2d, 0x2e, 0x68,
+	0x77, 0x6d, 0x67, 0x72, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x61, 0x64, 0x61, 0x70, 0x74,
+	0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x4e, 0x6f, 0x64, 0x65,
+	0x50, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2e, 0x2e, 0x68, 0x77,
+	0x6d, 0x67, 0x72, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x50,
+	0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x87, 0x01, 0x0a, 0x16,
+	0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x2e, 0x68, 0x77, 0x6d, 0x67, 0x72, 0x70, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x2e, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x36, 0x2e,
+	0x68, 0x77, 0x6d, 0x67, 0x72, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x61, 0x64, 0x61, 0x70,
+	0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x4e, 0x6f, 0x64,
+	0x65, 0x50, 0x6f, 0x6f, 0x6c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x72, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x43, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x2e, 0x2e, 0x68, 0x77, 0x6d, 0x67, 0x72,
+	0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x68, 0x77, 0x6d, 0x67, 0x72,
+	0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2e, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x4b, 0x5a, 0x49, 0x67, 0x69, 0x74,
+	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x73, 0x68, 0x69, 0x66,
+	0x74, 0x2d, 0x6b, 0x6e, 0x69, 0x2f, 0x6f, 0x72, 0x61, 0x6e, 0x2d, 0x68, 0x77, 0x6d, 0x67, 0x72,
+	0x2d, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x2f, 0x61, 0x64, 0x61, 0x70, 0x74, 0x6f, 0x72, 0x73,
+	0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x3b, 0x61, 0x64, 0x61,
+	0x70, 0x74, 0x6f, 0x72, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_adaptor_proto_rawDescOnce sync.Once
+	file_adaptor_proto_rawDescData = file_adaptor_proto_rawDesc
+)
+
+func file_adaptor_proto_rawDescGZIP() []byte {
+	file_adaptor_proto_rawDescOnce.Do(func() {
+		file_adaptor_proto_rawDescData = protoimpl.X.CompressGZIP(file_adaptor_proto_rawDescData)
+	})
+	return file_adaptor_proto_rawDescData
+}
+
+var file_adaptor_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_adaptor_proto_goTypes = []any{
+	(*GetInfoRequest)(nil),                 // 0: hwmgrplugin.adaptor.v1.GetInfoRequest
+	(*GetInfoResponse)(nil),                // 1: hwmgrplugin.adaptor.v1.GetInfoResponse
+	(*HandleNodePoolRequest)(nil),          // 2: hwmgrplugin.adaptor.v1.HandleNodePoolRequest
+	(*Result)(nil),                         // 3: hwmgrplugin.adaptor.v1.Result
+	(*HandleNodePoolResponse)(nil),         // 4: hwmgrplugin.adaptor.v1.HandleNodePoolResponse
+	(*HandleNodePoolDeletionRequest)(nil),  // 5: hwmgrplugin.adaptor.v1.HandleNodePoolDeletionRequest
+	(*HandleNodePoolDeletionResponse)(nil), // 6: hwmgrplugin.adaptor.v1.HandleNodePoolDeletionResponse
+	(*GetCapabilitiesRequest)(nil),         // 7: hwmgrplugin.adaptor.v1.GetCapabilitiesRequest
+	(*Capabilities)(nil),                   // 8: hwmgrplugin.adaptor.v1.Capabilities
+	(*GetCapabilitiesResponse)(nil),        // 9: hwmgrplugin.adaptor.v1.GetCapabilitiesResponse
+}
+var file_adaptor_proto_depIdxs = []int32{
+	3, // 0: hwmgrplugin.adaptor.v1.HandleNodePoolResponse.result:type_name -> hwmgrplugin.adaptor.v1.Result
+	8, // 1: hwmgrplugin.adaptor.v1.GetCapabilitiesResponse.capabilities:type_name -> hwmgrplugin.adaptor.v1.Capabilities
+	0, // 2: hwmgrplugin.adaptor.v1.HwMgrAdaptor.GetInfo:input_type -> hwmgrplugin.adaptor.v1.GetInfoRequest
+	2, // 3: hwmgrplugin.adaptor.v1.HwMgrAdaptor.HandleNodePool:input_type -> hwmgrplugin.adaptor.v1.HandleNodePoolRequest
+	5, // 4: hwmgrplugin.adaptor.v1.HwMgrAdaptor.HandleNodePoolDeletion:input_type -> hwmgrplugin.adaptor.v1.HandleNodePoolDeletionRequest
+	7, // 5: hwmgrplugin.adaptor.v1.HwMgrAdaptor.GetCapabilities:input_type -> hwmgrplugin.adaptor.v1.GetCapabilitiesRequest
+	1, // 6: hwmgrplugin.adaptor.v1.HwMgrAdaptor.GetInfo:output_type -> hwmgrplugin.adaptor.v1.GetInfoResponse
+	4, // 7: hwmgrplugin.adaptor.v1.HwMgrAdaptor.HandleNodePool:output_type -> hwmgrplugin.adaptor.v1.HandleNodePoolResponse
+	6, // 8: hwmgrplugin.adaptor.v1.HwMgrAdaptor.HandleNodePoolDeletion:output_type -> hwmgrplugin.adaptor.v1.HandleNodePoolDeletionResponse
+	9, // 9: hwmgrplugin.adaptor.v1.HwMgrAdaptor.GetCapabilities:output_type -> hwmgrplugin.adaptor.v1.GetCapabilitiesResponse
+	6, // [6:10] is the sub-list for method output_type
+	2, // [2:6] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_adaptor_proto_init() }
+func file_adaptor_proto_init() {
+	if File_adaptor_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_adaptor_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_adaptor_proto_goTypes,
+		DependencyIndexes: file_adaptor_proto_depIdxs,
+		MessageInfos:      file_adaptor_proto_msgTypes,
+	}.Build()
+	File_adaptor_proto = out.File
+	file_adaptor_proto_rawDesc = nil
+	file_adaptor_proto_goTypes = nil
+	file_adaptor_proto_depIdxs = nil
+}
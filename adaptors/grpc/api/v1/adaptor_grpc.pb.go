@@ -0,0 +1,271 @@
+// Copyright 2024.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: adaptor.proto
+
+package adaptorv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	HwMgrAdaptor_GetInfo_FullMethodName                = "/hwmgrplugin.adaptor.v1.HwMgrAdaptor/GetInfo"
+	HwMgrAdaptor_HandleNodePool_FullMethodName         = "/hwmgrplugin.adaptor.v1.HwMgrAdaptor/HandleNodePool"
+	HwMgrAdaptor_HandleNodePoolDeletion_FullMethodName = "/hwmgrplugin.adaptor.v1.HwMgrAdaptor/HandleNodePoolDeletion"
+	HwMgrAdaptor_GetCapabilities_FullMethodName        = "/hwmgrplugin.adaptor.v1.HwMgrAdaptor/GetCapabilities"
+)
+
+// HwMgrAdaptorClient is the client API for HwMgrAdaptor service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// HwMgrAdaptor is the contract between the hardware manager plugin and an out-of-process adaptor. It mirrors the
+// HwMgrAdaptorIntf interface implemented by the in-tree adaptors.
+//
+// Kubernetes objects are passed as their JSON encoding, including apiVersion and kind, so that the adaptor can
+// decode them with the same API types used by the plugin. The adaptor is responsible for updating the NodePool
+// status and creating Node CRs and BMC secrets, exactly as an in-tree adaptor would.
+type HwMgrAdaptorClient interface {
+	// GetInfo returns the adaptor identity and the protocol version it implements
+	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+	// HandleNodePool processes a NodePool CR
+	HandleNodePool(ctx context.Context, in *HandleNodePoolRequest, opts ...grpc.CallOption) (*HandleNodePoolResponse, error)
+	// HandleNodePoolDeletion processes the deletion of a NodePool CR
+	HandleNodePoolDeletion(ctx context.Context, in *HandleNodePoolDeletionRequest, opts ...grpc.CallOption) (*HandleNodePoolDeletionResponse, error)
+	// GetCapabilities returns the NodePool operations supported by the adaptor for a HardwareManager
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error)
+}
+
+type hwMgrAdaptorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHwMgrAdaptorClient(cc grpc.ClientConnInterface) HwMgrAdaptorClient {
+	return &hwMgrAdaptorClient{cc}
+}
+
+func (c *hwMgrAdaptorClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInfoResponse)
+	err := c.cc.Invoke(ctx, HwMgrAdaptor_GetInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hwMgrAdaptorClient) HandleNodePool(ctx context.Context, in *HandleNodePoolRequest, opts ...grpc.CallOption) (*HandleNodePoolResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HandleNodePoolResponse)
+	err := c.cc.Invoke(ctx, HwMgrAdaptor_HandleNodePool_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hwMgrAdaptorClient) HandleNodePoolDeletion(ctx context.Context, in *HandleNodePoolDeletionRequest, opts ...grpc.CallOption) (*HandleNodePoolDeletionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HandleNodePoolDeletionResponse)
+	err := c.cc.Invoke(ctx, HwMgrAdaptor_HandleNodePoolDeletion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hwMgrAdaptorClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*GetCapabilitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCapabilitiesResponse)
+	err := c.cc.Invoke(ctx, HwMgrAdaptor_GetCapabilities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HwMgrAdaptorServer is the server API for HwMgrAdaptor service.
+// All implementations must embed UnimplementedHwMgrAdaptorServer
+// for forward compatibility.
+//
+// HwMgrAdaptor is the contract between the hardware manager plugin and an out-of-process adaptor. It mirrors the
+// HwMgrAdaptorIntf interface implemented by the in-tree adaptors.
+//
+// Kubernetes objects are passed as their JSON encoding, including apiVersion and kind, so that the adaptor can
+// decode them with the same API types used by the plugin. The adaptor is responsible for updating the NodePool
+// status and creating Node CRs and BMC secrets, exactly as an in-tree adaptor would.
+type HwMgrAdaptorServer interface {
+	// GetInfo returns the adaptor identity and the protocol version it implements
+	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
+	// HandleNodePool processes a NodePool CR
+	HandleNodePool(context.Context, *HandleNodePoolRequest) (*HandleNodePoolResponse, error)
+	// HandleNodePoolDeletion processes the deletion of a NodePool CR
+	HandleNodePoolDeletion(context.Context, *HandleNodePoolDeletionRequest) (*HandleNodePoolDeletionResponse, error)
+	// GetCapabilities returns the NodePool operations supported by the adaptor for a HardwareManager
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error)
+	mustEmbedUnimplementedHwMgrAdaptorServer()
+}
+
+// UnimplementedHwMgrAdaptorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHwMgrAdaptorServer struct{}
+
+func (UnimplementedHwMgrAdaptorServer) GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
+}
+func (UnimplementedHwMgrAdaptorServer) HandleNodePool(context.Context, *HandleNodePoolRequest) (*HandleNodePoolResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleNodePool not implemented")
+}
+func (UnimplementedHwMgrAdaptorServer) HandleNodePoolDeletion(context.Context, *HandleNodePoolDeletionRequest) (*HandleNodePoolDeletionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HandleNodePoolDeletion not implemented")
+}
+func (UnimplementedHwMgrAdaptorServer) GetCapabilities(context.Context, *GetCapabilitiesRequest) (*GetCapabilitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedHwMgrAdaptorServer) mustEmbedUnimplementedHwMgrAdaptorServer() {}
+func (UnimplementedHwMgrAdaptorServer) testEmbeddedByValue()                      {}
+
+// UnsafeHwMgrAdaptorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HwMgrAdaptorServer will
+// result in compilation errors.
+type UnsafeHwMgrAdaptorServer interface {
+	mustEmbedUnimplementedHwMgrAdaptorServer()
+}
+
+func RegisterHwMgrAdaptorServer(s grpc.ServiceRegistrar, srv HwMgrAdaptorServer) {
+	// If the following call pancis, it indicates UnimplementedHwMgrAdaptorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&HwMgrAdaptor_ServiceDesc, srv)
+}
+
+func _HwMgrAdaptor_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HwMgrAdaptorServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HwMgrAdaptor_GetInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HwMgrAdaptorServer).GetInfo(ctx, req.(*GetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HwMgrAdaptor_HandleNodePool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandleNodePoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HwMgrAdaptorServer).HandleNodePool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HwMgrAdaptor_HandleNodePool_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HwMgrAdaptorServer).HandleNodePool(ctx, req.(*HandleNodePoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HwMgrAdaptor_HandleNodePoolDeletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HandleNodePoolDeletionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HwMgrAdaptorServer).HandleNodePoolDeletion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HwMgrAdaptor_HandleNodePoolDeletion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HwMgrAdaptorServer).HandleNodePoolDeletion(ctx, req.(*HandleNodePoolDeletionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HwMgrAdaptor_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HwMgrAdaptorServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HwMgrAdaptor_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HwMgrAdaptorServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HwMgrAdaptor_ServiceDesc is the grpc.ServiceDesc for HwMgrAdaptor service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HwMgrAdaptor_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hwmgrplugin.adaptor.v1.HwMgrAdaptor",
+	HandlerType: (*HwMgrAdaptorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetInfo",
+			Handler:    _HwMgrAdaptor_GetInfo_Handler,
+		},
+		{
+			MethodName: "HandleNodePool",
+			Handler:    _HwMgrAdaptor_HandleNodePool_Handler,
+		},
+		{
+			MethodName: "HandleNodePoolDeletion",
+			Handler:    _HwMgrAdaptor_HandleNodePoolDeletion_Handler,
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _HwMgrAdaptor_GetCapabilities_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "adaptor.proto",
+}
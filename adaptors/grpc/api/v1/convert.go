@@ -0,0 +1,76 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptorv1
+
+import (
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+// ProtocolVersion is the version of the adaptor protocol defined by this package. The version is incremented when a
+// change to the protocol would be misinterpreted by an adaptor implementing an earlier version, so that the adaptor is
+// rejected by the handshake. Version v2 added the completed field of HandleNodePoolDeletionResponse, which an earlier
+// adaptor leaves unset, so that the deletion of its NodePools would never complete.
+const ProtocolVersion = "v2"
+
+// ResultToProto converts a controller-runtime reconcile result to the protocol representation
+func ResultToProto(result ctrl.Result) *Result {
+	return &Result{
+		Requeue:             result.Requeue,
+		RequeueAfterSeconds: int64(result.RequeueAfter / time.Second),
+	}
+}
+
+// ResultFromProto converts the protocol representation of a reconcile result to a controller-runtime result
+func ResultFromProto(result *Result) ctrl.Result {
+	if result == nil {
+		return ctrl.Result{}
+	}
+
+	return ctrl.Result{
+		Requeue:      result.GetRequeue(),
+		RequeueAfter: time.Duration(result.GetRequeueAfterSeconds()) * time.Second,
+	}
+}
+
+// CapabilitiesToProto converts the adaptor capabilities to the protocol representation
+func CapabilitiesToProto(capabilities pluginv1alpha1.AdaptorCapabilities) *Capabilities {
+	return &Capabilities{
+		ProfileUpdate:    capabilities.ProfileUpdate,
+		ScaleOut:         capabilities.ScaleOut,
+		ScaleIn:          capabilities.ScaleIn,
+		PowerControl:     capabilities.PowerControl,
+		NodeReplacement:  capabilities.NodeReplacement,
+		InventoryListing: capabilities.InventoryListing,
+	}
+}
+
+// CapabilitiesFromProto converts the protocol representation of the adaptor capabilities. An adaptor that does not
+// report its capabilities is assumed to support none of the optional operations.
+func CapabilitiesFromProto(capabilities *Capabilities) pluginv1alpha1.AdaptorCapabilities {
+	return pluginv1alpha1.AdaptorCapabilities{
+		ProfileUpdate:    capabilities.GetProfileUpdate(),
+		ScaleOut:         capabilities.GetScaleOut(),
+		ScaleIn:          capabilities.GetScaleIn(),
+		PowerControl:     capabilities.GetPowerControl(),
+		NodeReplacement:  capabilities.GetNodeReplacement(),
+		InventoryListing: capabilities.GetInventoryListing(),
+	}
+}
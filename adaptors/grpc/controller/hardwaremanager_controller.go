@@ -0,0 +1,182 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/grpc/grpcclient"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/logging"
+)
+
+// HardwareManagerReconciler reconciles a HardwareManager object
+type HardwareManagerReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Logger    *slog.Logger
+	Namespace string
+	AdaptorID pluginv1alpha1.HardwareManagerAdaptorID
+	Clients   *grpcclient.ClientCache
+}
+
+//+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=hardwaremanagers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=hardwaremanagers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=hardwaremanagers/finalizers,verbs=update
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.16.3/pkg/reconcile
+func (r *HardwareManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	_ = log.FromContext(ctx)
+	result = utils.DoNotRequeue()
+
+	// Fetch the CR:
+	hwmgr := &pluginv1alpha1.HardwareManager{}
+	if err = r.Client.Get(ctx, req.NamespacedName, hwmgr); err != nil {
+		if errors.IsNotFound(err) {
+			// The HardwareManager has likely been deleted, so drop the connection to its adaptor
+			r.Clients.Evict(ctx, r.Logger, req.NamespacedName)
+			err = nil
+			return
+		}
+		r.Logger.ErrorContext(
+			ctx,
+			"Unable to fetch HardwareManager",
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	// Make sure this is an instance for this adaptor
+	if hwmgr.Spec.AdaptorID != r.AdaptorID {
+		// Skip this CR
+		return
+	}
+
+	ctx = logging.AppendCtx(ctx, slog.String("hwmgr", hwmgr.Name))
+
+	hwmgr.Status.ObservedGeneration = hwmgr.Generation
+
+	if hwmgr.Spec.GrpcData == nil {
+		// Invalid data
+		if updateErr := utils.UpdateHardwareManagerStatusCondition(ctx, r.Client, hwmgr,
+			pluginv1alpha1.ConditionTypes.Validation,
+			pluginv1alpha1.ConditionReasons.Failed,
+			metav1.ConditionFalse,
+			"Missing grpcData configuration field"); updateErr != nil {
+			err = fmt.Errorf("failed to update status for hardware manager (%s) with validation failure: %w", hwmgr.Name, updateErr)
+			return
+		}
+		r.Logger.Error("HardwareManager CR missing grpcData configuration field", slog.String("name", hwmgr.Name))
+		return
+	}
+
+	result = utils.RequeueWithLongInterval()
+
+	r.Logger.InfoContext(ctx, "Validating adaptor connection", slog.String("endpoint", hwmgr.Spec.GrpcData.Endpoint))
+
+	info, clientErr := r.checkConnection(ctx, hwmgr)
+	if clientErr != nil {
+		r.Logger.InfoContext(ctx, "Adaptor connection error", slog.String("error", clientErr.Error()))
+		if updateErr := utils.UpdateHardwareManagerStatusCondition(ctx, r.Client, hwmgr,
+			pluginv1alpha1.ConditionTypes.Validation,
+			pluginv1alpha1.ConditionReasons.Failed,
+			metav1.ConditionFalse,
+			"Adaptor connection failure - "+clientErr.Error()); updateErr != nil {
+			err = fmt.Errorf("failed to update status for hardware manager (%s) with connection failure: %w", hwmgr.Name, updateErr)
+			return
+		}
+		r.Logger.Error("Failed to establish connection to adaptor", slog.String("name", hwmgr.Name), slog.String("error", clientErr.Error()))
+		return
+	}
+
+	if updateErr := utils.UpdateHardwareManagerStatusCondition(ctx, r.Client, hwmgr,
+		pluginv1alpha1.ConditionTypes.Validation,
+		pluginv1alpha1.ConditionReasons.Completed,
+		metav1.ConditionTrue,
+		info); updateErr != nil {
+		err = fmt.Errorf("failed to update status for hardware manager (%s) with validation success: %w", hwmgr.Name, updateErr)
+		return
+	}
+
+	return
+}
+
+// checkConnection performs a handshake with the adaptor and queries its capabilities, returning a description of
+// the adaptor
+func (r *HardwareManagerReconciler) checkConnection(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) (string, error) {
+	adaptorClient, err := grpcclient.NewAdaptorClient(ctx, r.Logger, r.Client, hwmgr)
+	if err != nil {
+		return "", fmt.Errorf("failed to setup adaptor client: %w", err)
+	}
+
+	defer func() {
+		if err := adaptorClient.Close(); err != nil {
+			r.Logger.InfoContext(ctx, "Failed to close adaptor connection", slog.String("error", err.Error()))
+		}
+	}()
+
+	info, err := adaptorClient.Handshake(ctx)
+	if err != nil {
+		return "", err // nolint: wrapcheck
+	}
+
+	capabilities, err := adaptorClient.GetAdaptorCapabilities(ctx, r.Scheme, hwmgr)
+	if err != nil {
+		return "", err // nolint: wrapcheck
+	}
+	hwmgr.Status.Capabilities = &capabilities
+
+	return fmt.Sprintf("Connected to adaptor %s, protocol %s", info.GetName(), info.GetProtocolVersion()), nil
+}
+
+func filterEvents(adaptorID pluginv1alpha1.HardwareManagerAdaptorID) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(object client.Object) bool {
+		hwmgr := object.(*pluginv1alpha1.HardwareManager)
+		return hwmgr.Spec.AdaptorID == adaptorID
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HardwareManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Logger.Info("Setting up gRPC controller", slog.String("adaptorId", string(r.AdaptorID)))
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named(string(r.AdaptorID)).
+		For(&pluginv1alpha1.HardwareManager{}).
+		WithEventFilter(filterEvents(r.AdaptorID)).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to setup controller for %s: %w", r.AdaptorID, err)
+	}
+
+	return nil
+
+}
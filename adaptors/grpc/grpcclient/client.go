@@ -0,0 +1,290 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	adaptorv1 "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/grpc/api/v1"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+)
+
+// RequestTimeout bounds the duration of a single request to the adaptor
+const RequestTimeout = 2 * time.Minute
+
+// AdaptorClient provides functions for calling an out-of-process adaptor
+type AdaptorClient struct {
+	adaptorv1.HwMgrAdaptorClient
+	Logger     *slog.Logger
+	conn       *grpc.ClientConn
+	uid        types.UID
+	generation int64
+}
+
+// IsTransient checks whether a failed request to the adaptor may succeed if retried, as the adaptor was unreachable,
+// overloaded or did not respond in time
+func IsTransient(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// transportCredentials builds the transport credentials for the connection to the adaptor from the grpcData
+// configuration
+func transportCredentials(
+	ctx context.Context,
+	rtclient client.Client,
+	hwmgr *pluginv1alpha1.HardwareManager) (credentials.TransportCredentials, error) {
+
+	config := hwmgr.Spec.GrpcData
+	if config.Plaintext {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig, err := utils.GetDefaultTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}, config.InsecureSkipTLSVerify)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tls config: %w", err)
+	}
+
+	// If the HardwareManager CR includes certificates, add the bundle to the root CAs
+	if config.CaBundleName != nil {
+		cm, err := utils.GetConfigmap(ctx, rtclient, *config.CaBundleName, hwmgr.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configmap: %w", err)
+		}
+
+		caBundle, err := utils.GetConfigMapField(cm, "ca-bundle.pem")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get certificate bundle from configmap: %w", err)
+		}
+
+		if tlsConfig.RootCAs == nil {
+			tlsConfig.RootCAs = x509.NewCertPool()
+		}
+		if !tlsConfig.RootCAs.AppendCertsFromPEM([]byte(caBundle)) {
+			return nil, fmt.Errorf("failed to append certificate bundle from configmap %s", *config.CaBundleName)
+		}
+	}
+
+	// If a client certificate is provided, present it to the adaptor for mutual TLS
+	if config.ClientCertSecret != nil {
+		secret, err := utils.GetSecret(ctx, rtclient, *config.ClientCertSecret, hwmgr.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client certificate secret: %w", err)
+		}
+
+		certPEM, err := utils.GetSecretField(secret, corev1.TLSCertKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client certificate: %w", err)
+		}
+
+		keyPEM, err := utils.GetSecretField(secret, corev1.TLSPrivateKeyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client key: %w", err)
+		}
+
+		cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate from secret %s: %w", *config.ClientCertSecret, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// NewAdaptorClient creates a client connected to the out-of-process adaptor configured in the HardwareManager CR.
+// The connection is established lazily, on the first request.
+func NewAdaptorClient(
+	ctx context.Context,
+	logger *slog.Logger,
+	rtclient client.Client,
+	hwmgr *pluginv1alpha1.HardwareManager) (*AdaptorClient, error) {
+
+	if hwmgr.Spec.GrpcData == nil {
+		return nil, fmt.Errorf("required config data missing from HardwareManager: name=%s", hwmgr.Name)
+	}
+
+	creds, err := transportCredentials(ctx, rtclient, hwmgr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup transport credentials for %s: %w", hwmgr.Name, err)
+	}
+
+	conn, err := grpc.NewClient(hwmgr.Spec.GrpcData.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup client to %s: %w", hwmgr.Spec.GrpcData.Endpoint, err)
+	}
+
+	return &AdaptorClient{
+		HwMgrAdaptorClient: adaptorv1.NewHwMgrAdaptorClient(conn),
+		Logger:             logger,
+		conn:               conn,
+		uid:                hwmgr.UID,
+		generation:         hwmgr.Generation,
+	}, nil
+}
+
+// Close tears down the connection to the adaptor
+func (c *AdaptorClient) Close() error {
+	if err := c.conn.Close(); err != nil {
+		return fmt.Errorf("failed to close connection: %w", err)
+	}
+	return nil
+}
+
+// Handshake queries the adaptor identity, verifying that it implements the same protocol version as the plugin
+func (c *AdaptorClient) Handshake(ctx context.Context) (*adaptorv1.GetInfoResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, RequestTimeout)
+	defer cancel()
+
+	info, err := c.GetInfo(ctx, &adaptorv1.GetInfoRequest{ProtocolVersion: adaptorv1.ProtocolVersion})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get adaptor info: %w", err)
+	}
+
+	if info.GetProtocolVersion() != adaptorv1.ProtocolVersion {
+		return nil, fmt.Errorf("adaptor %s implements unsupported protocol version %s, expected %s",
+			info.GetName(), info.GetProtocolVersion(), adaptorv1.ProtocolVersion)
+	}
+
+	return info, nil
+}
+
+// GetAdaptorCapabilities queries the NodePool operations supported by the adaptor for the HardwareManager CR
+func (c *AdaptorClient) GetAdaptorCapabilities(
+	ctx context.Context,
+	scheme *runtime.Scheme,
+	hwmgr *pluginv1alpha1.HardwareManager) (pluginv1alpha1.AdaptorCapabilities, error) {
+	hwmgrData, err := EncodeObject(scheme, hwmgr)
+	if err != nil {
+		return pluginv1alpha1.AdaptorCapabilities{}, fmt.Errorf("failed to encode hwmgr: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, RequestTimeout)
+	defer cancel()
+
+	response, err := c.GetCapabilities(ctx, &adaptorv1.GetCapabilitiesRequest{HardwareManager: hwmgrData})
+	if err != nil {
+		return pluginv1alpha1.AdaptorCapabilities{}, fmt.Errorf("failed to get adaptor capabilities: %w", err)
+	}
+
+	return adaptorv1.CapabilitiesFromProto(response.GetCapabilities()), nil
+}
+
+// EncodeObject encodes a Kubernetes object as JSON for transfer to the adaptor, ensuring the apiVersion and kind
+// are populated so the adaptor can decode it
+func EncodeObject(scheme *runtime.Scheme, object client.Object) ([]byte, error) {
+	gvk, err := apiutil.GVKForObject(object, scheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GroupVersionKind for %s: %w", object.GetName(), err)
+	}
+
+	object = object.DeepCopyObject().(client.Object)
+	object.GetObjectKind().SetGroupVersionKind(gvk)
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s %s: %w", gvk.Kind, object.GetName(), err)
+	}
+
+	return data, nil
+}
+
+// ClientCache holds a client per HardwareManager CR, replacing it when the HardwareManager is recreated or its
+// generation changes. Entries must be evicted when the HardwareManager is deleted, to close the connection.
+type ClientCache struct {
+	lock    sync.Mutex
+	clients map[types.NamespacedName]*AdaptorClient
+}
+
+// NewClientCache creates an empty client cache
+func NewClientCache() *ClientCache {
+	return &ClientCache{
+		clients: make(map[types.NamespacedName]*AdaptorClient),
+	}
+}
+
+// Get returns the cached client for the HardwareManager CR, creating a new one as needed
+func (c *ClientCache) Get(
+	ctx context.Context,
+	logger *slog.Logger,
+	rtclient client.Client,
+	hwmgr *pluginv1alpha1.HardwareManager) (*AdaptorClient, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := client.ObjectKeyFromObject(hwmgr)
+	if cached, exists := c.clients[key]; exists {
+		if cached.uid == hwmgr.UID && cached.generation == hwmgr.Generation {
+			return cached, nil
+		}
+
+		// The CR has been recreated or its configuration has changed, so tear down the stale connection
+		c.evict(ctx, logger, key)
+	}
+
+	adaptorClient, err := NewAdaptorClient(ctx, logger, rtclient, hwmgr)
+	if err != nil {
+		return nil, err
+	}
+
+	c.clients[key] = adaptorClient
+	return adaptorClient, nil
+}
+
+// Evict removes the cached client for the HardwareManager CR, if any, closing its connection
+func (c *ClientCache) Evict(ctx context.Context, logger *slog.Logger, name types.NamespacedName) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.evict(ctx, logger, name)
+}
+
+// evict removes the cached client for the HardwareManager CR. The caller must hold the lock.
+func (c *ClientCache) evict(ctx context.Context, logger *slog.Logger, name types.NamespacedName) {
+	cached, exists := c.clients[name]
+	if !exists {
+		return
+	}
+
+	if err := cached.Close(); err != nil {
+		logger.InfoContext(ctx, "Failed to close stale adaptor connection", slog.String("error", err.Error()))
+	}
+	delete(c.clients, name)
+}
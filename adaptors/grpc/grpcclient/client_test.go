@@ -0,0 +1,152 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcclient
+
+import (
+	"context"
+	"log/slog"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	adaptorv1 "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/grpc/api/v1"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+// fakeAdaptorServer reports the configured protocol version in the handshake
+type fakeAdaptorServer struct {
+	adaptorv1.UnimplementedHwMgrAdaptorServer
+	protocolVersion string
+}
+
+func (s *fakeAdaptorServer) GetInfo(ctx context.Context, req *adaptorv1.GetInfoRequest) (*adaptorv1.GetInfoResponse, error) {
+	return &adaptorv1.GetInfoResponse{ProtocolVersion: s.protocolVersion, Name: "fake"}, nil
+}
+
+var _ = Describe("gRPC adaptor client", func() {
+	var (
+		ctx    context.Context
+		logger *slog.Logger
+		fake   *fakeAdaptorServer
+		hwmgr  *pluginv1alpha1.HardwareManager
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		logger = slog.New(slog.NewTextHandler(GinkgoWriter, nil))
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		fake = &fakeAdaptorServer{protocolVersion: adaptorv1.ProtocolVersion}
+		server := grpc.NewServer()
+		adaptorv1.RegisterHwMgrAdaptorServer(server, fake)
+		go func() {
+			_ = server.Serve(listener)
+		}()
+		DeferCleanup(server.Stop)
+
+		hwmgr = &pluginv1alpha1.HardwareManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "grpc-1", Namespace: "oran-hwmgr-plugin", UID: "uid-1", Generation: 1},
+			Spec: pluginv1alpha1.HardwareManagerSpec{
+				GrpcData: &pluginv1alpha1.GrpcData{Endpoint: listener.Addr().String(), Plaintext: true},
+			},
+		}
+	})
+
+	It("completes the handshake with an adaptor implementing the protocol version", func() {
+		adaptorClient, err := NewAdaptorClient(ctx, logger, nil, hwmgr)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(adaptorClient.Close)
+
+		info, err := adaptorClient.Handshake(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.GetName()).To(Equal("fake"))
+	})
+
+	It("rejects an adaptor implementing an earlier protocol version", func() {
+		// A v1 adaptor does not report the completion of a NodePool deletion
+		fake.protocolVersion = "v1"
+
+		adaptorClient, err := NewAdaptorClient(ctx, logger, nil, hwmgr)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(adaptorClient.Close)
+
+		_, err = adaptorClient.Handshake(ctx)
+		Expect(err).To(MatchError(ContainSubstring("unsupported protocol version v1, expected " + adaptorv1.ProtocolVersion)))
+	})
+
+	Describe("client cache", func() {
+		var cache *ClientCache
+
+		BeforeEach(func() {
+			cache = NewClientCache()
+		})
+
+		It("reuses the client while the HardwareManager is unchanged", func() {
+			first, err := cache.Get(ctx, logger, nil, hwmgr)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(cache.Evict, ctx, logger, client.ObjectKeyFromObject(hwmgr))
+
+			second, err := cache.Get(ctx, logger, nil, hwmgr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).To(BeIdenticalTo(first))
+		})
+
+		It("closes the stale client when the HardwareManager generation changes", func() {
+			first, err := cache.Get(ctx, logger, nil, hwmgr)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(cache.Evict, ctx, logger, client.ObjectKeyFromObject(hwmgr))
+
+			hwmgr.Generation = 2
+			second, err := cache.Get(ctx, logger, nil, hwmgr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).NotTo(BeIdenticalTo(first))
+			Expect(first.conn.GetState()).To(Equal(connectivity.Shutdown))
+		})
+
+		It("closes the stale client when the HardwareManager is recreated", func() {
+			first, err := cache.Get(ctx, logger, nil, hwmgr)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(cache.Evict, ctx, logger, client.ObjectKeyFromObject(hwmgr))
+
+			// A recreated CR has the same name and may have the same generation
+			hwmgr.UID = "uid-2"
+			second, err := cache.Get(ctx, logger, nil, hwmgr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).NotTo(BeIdenticalTo(first))
+			Expect(first.conn.GetState()).To(Equal(connectivity.Shutdown))
+		})
+
+		It("closes and drops the client when evicted", func() {
+			first, err := cache.Get(ctx, logger, nil, hwmgr)
+			Expect(err).NotTo(HaveOccurred())
+
+			cache.Evict(ctx, logger, client.ObjectKeyFromObject(hwmgr))
+			Expect(first.conn.GetState()).To(Equal(connectivity.Shutdown))
+			Expect(cache.clients).To(BeEmpty())
+
+			// Evicting an unknown HardwareManager is a no-op
+			cache.Evict(ctx, logger, client.ObjectKeyFromObject(hwmgr))
+		})
+	})
+})
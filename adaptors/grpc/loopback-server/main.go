@@ -0,0 +1,111 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The loopback-server command serves the loopback adaptor over the gRPC adaptor protocol. It is the reference
+// implementation of an out-of-process adaptor, and can be run as a sidecar of the plugin or as a standalone Service.
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	adaptorv1 "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/grpc/api/v1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/grpc/server"
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/loopback"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/logging"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(hwmgmtv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(pluginv1alpha1.AddToScheme(scheme))
+}
+
+func main() {
+	var bindAddr string
+	var namespace string
+	var certFile string
+	var keyFile string
+	flag.StringVar(&bindAddr, "bind-address", ":50051", "The address the adaptor service binds to.")
+	flag.StringVar(&namespace, "namespace", os.Getenv("MY_POD_NAMESPACE"), "The namespace of the loopback nodelist configmap.")
+	flag.StringVar(&certFile, "tls-cert-file", "", "The TLS certificate file. If not set, the service is served in plaintext.")
+	flag.StringVar(&keyFile, "tls-key-file", "", "The TLS key file.")
+	flag.Parse()
+
+	logger := slog.New(logging.NewLoggingContextHandler(slog.LevelInfo)).With("server", "loopback-grpc")
+
+	if err := run(logger, bindAddr, namespace, certFile, keyFile); err != nil {
+		logger.Error("loopback adaptor server failed", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
+
+func run(logger *slog.Logger, bindAddr, namespace, certFile, keyFile string) error {
+	if namespace == "" {
+		return fmt.Errorf("unable to determine namespace")
+	}
+
+	c, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	creds := insecure.NewCredentials()
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		creds = credentials.NewTLS(&tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		})
+	}
+
+	listener, err := net.Listen("tcp", bindAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", bindAddr, err)
+	}
+
+	adaptor := loopback.NewAdaptor(c, scheme, logger, namespace)
+
+	grpcServer := grpc.NewServer(grpc.Creds(creds))
+	adaptorv1.RegisterHwMgrAdaptorServer(grpcServer, server.NewServer("loopback", adaptor, logger))
+
+	logger.Info("Serving loopback adaptor", slog.String("address", bindAddr))
+	if err := grpcServer.Serve(listener); err != nil {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+
+	return nil
+}
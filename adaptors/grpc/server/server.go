@@ -0,0 +1,140 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
+	adaptorv1 "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/grpc/api/v1"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/logging"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+// Server implements the adaptor protocol on top of an in-process adaptor implementation. It serves as the
+// reference implementation of the protocol for out-of-process adaptors.
+type Server struct {
+	adaptorv1.UnimplementedHwMgrAdaptorServer
+	Name    string
+	Adaptor adaptorinterface.HwMgrAdaptorIntf
+	Logger  *slog.Logger
+}
+
+// NewServer creates a protocol server for the adaptor
+func NewServer(name string, adaptor adaptorinterface.HwMgrAdaptorIntf, logger *slog.Logger) *Server {
+	return &Server{
+		Name:    name,
+		Adaptor: adaptor,
+		Logger:  logger,
+	}
+}
+
+// decodeRequest decodes the HardwareManager and NodePool CRs from a request
+func decodeRequest(hwmgrData, nodepoolData []byte) (*pluginv1alpha1.HardwareManager, *hwmgmtv1alpha1.NodePool, error) {
+	hwmgr := &pluginv1alpha1.HardwareManager{}
+	if err := json.Unmarshal(hwmgrData, hwmgr); err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "failed to decode hardware_manager: %s", err.Error())
+	}
+
+	nodepool := &hwmgmtv1alpha1.NodePool{}
+	if err := json.Unmarshal(nodepoolData, nodepool); err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "failed to decode node_pool: %s", err.Error())
+	}
+
+	return hwmgr, nodepool, nil
+}
+
+// GetInfo returns the adaptor identity and protocol version
+func (s *Server) GetInfo(ctx context.Context, req *adaptorv1.GetInfoRequest) (*adaptorv1.GetInfoResponse, error) {
+	if req.GetProtocolVersion() != adaptorv1.ProtocolVersion {
+		s.Logger.InfoContext(ctx, "Unsupported protocol version requested", slog.String("protocolVersion", req.GetProtocolVersion()))
+	}
+
+	return &adaptorv1.GetInfoResponse{
+		ProtocolVersion: adaptorv1.ProtocolVersion,
+		Name:            s.Name,
+	}, nil
+}
+
+// HandleNodePool passes the NodePool CR to the adaptor
+func (s *Server) HandleNodePool(ctx context.Context, req *adaptorv1.HandleNodePoolRequest) (*adaptorv1.HandleNodePoolResponse, error) {
+	hwmgr, nodepool, err := decodeRequest(req.GetHardwareManager(), req.GetNodePool())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = logging.AppendCtx(ctx, slog.String("nodepool", nodepool.Name))
+	ctx = logging.AppendCtx(ctx, slog.String("hwmgr", hwmgr.Name))
+	s.Logger.InfoContext(ctx, "Handling NodePool request")
+
+	response := &adaptorv1.HandleNodePoolResponse{}
+
+	result, err := s.Adaptor.HandleNodePool(ctx, hwmgr, nodepool)
+	response.Result = adaptorv1.ResultToProto(result)
+	if err != nil {
+		s.Logger.InfoContext(ctx, "HandleNodePool failed", slog.String("error", err.Error()))
+		response.Error = err.Error()
+	}
+
+	return response, nil
+}
+
+// HandleNodePoolDeletion passes the NodePool CR deletion to the adaptor
+func (s *Server) HandleNodePoolDeletion(ctx context.Context, req *adaptorv1.HandleNodePoolDeletionRequest) (*adaptorv1.HandleNodePoolDeletionResponse, error) {
+	hwmgr, nodepool, err := decodeRequest(req.GetHardwareManager(), req.GetNodePool())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = logging.AppendCtx(ctx, slog.String("nodepool", nodepool.Name))
+	ctx = logging.AppendCtx(ctx, slog.String("hwmgr", hwmgr.Name))
+	s.Logger.InfoContext(ctx, "Handling NodePool deletion request")
+
+	response := &adaptorv1.HandleNodePoolDeletionResponse{}
+
+	completed, err := s.Adaptor.HandleNodePoolDeletion(ctx, hwmgr, nodepool)
+	if err != nil {
+		s.Logger.InfoContext(ctx, "HandleNodePoolDeletion failed", slog.String("error", err.Error()))
+		response.Error = err.Error()
+	}
+	response.Completed = completed
+
+	return response, nil
+}
+
+// GetCapabilities returns the NodePool operations supported by the adaptor
+func (s *Server) GetCapabilities(ctx context.Context, req *adaptorv1.GetCapabilitiesRequest) (*adaptorv1.GetCapabilitiesResponse, error) {
+	hwmgr := &pluginv1alpha1.HardwareManager{}
+	if err := json.Unmarshal(req.GetHardwareManager(), hwmgr); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to decode hardware_manager: %s", err.Error())
+	}
+
+	capabilities, err := s.Adaptor.GetCapabilities(ctx, hwmgr)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get capabilities: %s", err.Error())
+	}
+
+	return &adaptorv1.GetCapabilitiesResponse{
+		Capabilities: adaptorv1.CapabilitiesToProto(capabilities),
+	}, nil
+}
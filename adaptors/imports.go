@@ -0,0 +1,28 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+// Import the adaptors. Each adaptor package registers itself with the adaptor registry from its init function, which
+// only runs if the package is linked into the binary, so a new in-tree adaptor must be added to this list.
+import (
+	_ "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr"
+	_ "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/grpc"
+	_ "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/inventory"
+	_ "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/loopback"
+	_ "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/metal3"
+	_ "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/redfish"
+)
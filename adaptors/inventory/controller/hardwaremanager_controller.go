@@ -0,0 +1,161 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/logging"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+// HardwareManagerReconciler reconciles a HardwareManager object
+type HardwareManagerReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	Logger       *slog.Logger
+	Namespace    string
+	AdaptorID    pluginv1alpha1.HardwareManagerAdaptorID
+	Capabilities pluginv1alpha1.AdaptorCapabilities
+}
+
+//+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=hardwaremanagers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=hardwaremanagers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=hardwaremanagers/finalizers,verbs=update
+//+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=hardwarenodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=hardwarenodes/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.16.3/pkg/reconcile
+func (r *HardwareManagerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	_ = log.FromContext(ctx)
+	result = utils.DoNotRequeue()
+
+	// Fetch the CR:
+	hwmgr := &pluginv1alpha1.HardwareManager{}
+	if err = r.Client.Get(ctx, req.NamespacedName, hwmgr); err != nil {
+		if errors.IsNotFound(err) {
+			// The HardwareManager has likely been deleted
+			err = nil
+			return
+		}
+		r.Logger.ErrorContext(
+			ctx,
+			"Unable to fetch HardwareManager",
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	// Make sure this is an instance for this adaptor
+	if hwmgr.Spec.AdaptorID != r.AdaptorID {
+		// Skip this CR
+		return
+	}
+
+	ctx = logging.AppendCtx(ctx, slog.String("hwmgr", hwmgr.Name))
+
+	hwmgr.Status.ObservedGeneration = hwmgr.Generation
+	hwmgr.Status.Capabilities = r.Capabilities.DeepCopy()
+
+	// Configuration data is not mandatory for the inventory adaptor, so validation checks that the HardwareNode API
+	// is available
+	namespace := hwmgr.Namespace
+	if hwmgr.Spec.InventoryData != nil && hwmgr.Spec.InventoryData.NodeNamespace != "" {
+		namespace = hwmgr.Spec.InventoryData.NodeNamespace
+	}
+
+	if listErr := r.checkHardwareNodes(ctx, namespace); listErr != nil {
+		r.Logger.InfoContext(ctx, "HardwareNode query error", slog.String("error", listErr.Error()))
+		if updateErr := utils.UpdateHardwareManagerStatusCondition(ctx, r.Client, hwmgr,
+			pluginv1alpha1.ConditionTypes.Validation,
+			pluginv1alpha1.ConditionReasons.Failed,
+			metav1.ConditionFalse,
+			"HardwareNode query failure - "+listErr.Error()); updateErr != nil {
+			err = fmt.Errorf("failed to update status for hardware manager (%s) with validation failure: %w", hwmgr.Name, updateErr)
+			return
+		}
+		result = utils.RequeueWithLongInterval()
+		return
+	}
+
+	if updateErr := utils.UpdateHardwareManagerStatusCondition(ctx, r.Client, hwmgr,
+		pluginv1alpha1.ConditionTypes.Validation,
+		pluginv1alpha1.ConditionReasons.Completed,
+		metav1.ConditionTrue,
+		"Validated"); updateErr != nil {
+		err = fmt.Errorf("failed to update status for hardware manager (%s) with validation success: %w", hwmgr.Name, updateErr)
+		return
+	}
+
+	r.Logger.InfoContext(ctx, "[Inventory HardwareManager]", "inventoryData", hwmgr.Spec.InventoryData)
+
+	return
+}
+
+// checkHardwareNodes verifies that HardwareNodes can be queried in the namespace
+func (r *HardwareManagerReconciler) checkHardwareNodes(ctx context.Context, namespace string) error {
+	// The list is unstructured to bypass the manager cache, which is restricted to the plugin namespace
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(pluginv1alpha1.GroupVersion.WithKind("HardwareNodeList"))
+
+	if err := r.Client.List(ctx, list, client.InNamespace(namespace), client.Limit(1)); err != nil {
+		return fmt.Errorf("failed to list HardwareNodes in namespace %s: %w", namespace, err)
+	}
+
+	return nil
+}
+
+func filterEvents(adaptorID pluginv1alpha1.HardwareManagerAdaptorID) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(object client.Object) bool {
+		hwmgr := object.(*pluginv1alpha1.HardwareManager)
+		return hwmgr.Spec.AdaptorID == adaptorID
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HardwareManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.AdaptorID = pluginv1alpha1.SupportedAdaptors.Inventory
+	r.Logger.Info("Setting up Inventory controller", slog.String("adaptorId", string(r.AdaptorID)))
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named(string(r.AdaptorID)).
+		For(&pluginv1alpha1.HardwareManager{}).
+		WithEventFilter(filterEvents(r.AdaptorID)).
+		WithEventFilter(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{})).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to setup controller for %s: %w", r.AdaptorID, err)
+	}
+
+	return nil
+
+}
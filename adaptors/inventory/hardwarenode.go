@@ -0,0 +1,103 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// nodeId returns the hardware manager node ID of the HardwareNode, as <namespace>/<name>
+func nodeId(hwnode *pluginv1alpha1.HardwareNode) string {
+	return hwnode.Namespace + "/" + hwnode.Name
+}
+
+// isAllocatedTo checks whether the HardwareNode is allocated to the NodePool by the HardwareManager
+func isAllocatedTo(hwnode *pluginv1alpha1.HardwareNode, hwmgr *pluginv1alpha1.HardwareManager, nodepool string) bool {
+	allocation := hwnode.Status.Allocation
+	return allocation != nil && allocation.HwMgrId == hwmgr.Name && allocation.NodePool == nodepool
+}
+
+// listHardwareNodes gets the HardwareNodes in the namespace. The list is read as unstructured objects to bypass the
+// manager cache, which is restricted to the plugin namespace, and so that claims are made against the latest
+// resourceVersion of each node.
+func listHardwareNodes(ctx context.Context, c client.Client, namespace string) ([]*pluginv1alpha1.HardwareNode, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(pluginv1alpha1.GroupVersion.WithKind("HardwareNodeList"))
+
+	if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list HardwareNodes in namespace %s: %w", namespace, err)
+	}
+
+	hwnodes := make([]*pluginv1alpha1.HardwareNode, 0, len(list.Items))
+	for i := range list.Items {
+		hwnode := &pluginv1alpha1.HardwareNode{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(list.Items[i].Object, hwnode); err != nil {
+			return nil, fmt.Errorf("failed to parse HardwareNode %s/%s: %w",
+				list.Items[i].GetNamespace(), list.Items[i].GetName(), err)
+		}
+		hwnodes = append(hwnodes, hwnode)
+	}
+
+	return hwnodes, nil
+}
+
+// claimHardwareNode records the allocation in the status of the HardwareNode. The update is made against the
+// resourceVersion of the listed object, so a concurrent claim results in a conflict error.
+func claimHardwareNode(ctx context.Context, c client.Client, hwnode *pluginv1alpha1.HardwareNode, allocation *pluginv1alpha1.HardwareNodeAllocation) error {
+	object := hwnode.DeepCopy()
+	object.Status.Allocation = allocation
+
+	if err := c.Status().Update(ctx, object); err != nil {
+		return fmt.Errorf("failed to claim HardwareNode %s: %w", nodeId(hwnode), err)
+	}
+
+	*hwnode = *object
+	return nil
+}
+
+// releaseHardwareNode removes the allocation from the status of the HardwareNode
+func releaseHardwareNode(ctx context.Context, c client.Client, hwnode *pluginv1alpha1.HardwareNode) error {
+	object := hwnode.DeepCopy()
+	object.Status.Allocation = nil
+
+	if err := c.Status().Update(ctx, object); err != nil {
+		if errors.IsNotFound(err) {
+			// The node has been deleted, so there is no allocation to release
+			return nil
+		}
+		return fmt.Errorf("failed to release HardwareNode %s: %w", nodeId(hwnode), err)
+	}
+
+	return nil
+}
+
+// getCredentials reads the username and password from the BMC credentials secret of the HardwareNode
+func getCredentials(ctx context.Context, c client.Client, hwnode *pluginv1alpha1.HardwareNode) ([]byte, []byte, error) {
+	username, password, err := utils.GetBMCCredentials(ctx, c, hwnode.Spec.BMC.CredentialsName, hwnode.Namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("HardwareNode %s: %w", nodeId(hwnode), err)
+	}
+	return username, password, nil
+}
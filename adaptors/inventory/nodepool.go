@@ -0,0 +1,200 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// getFreeNodes gets the HardwareNodes in the resource pool that are not allocated
+func getFreeNodes(hwnodes []*pluginv1alpha1.HardwareNode, resourcePoolId string) []*pluginv1alpha1.HardwareNode {
+	var free []*pluginv1alpha1.HardwareNode
+	for _, hwnode := range hwnodes {
+		if hwnode.Spec.ResourcePoolId == resourcePoolId && hwnode.Status.Allocation == nil {
+			free = append(free, hwnode)
+		}
+	}
+	return free
+}
+
+// getClaimedNodes gets the HardwareNodes allocated to the NodePool
+func getClaimedNodes(
+	hwnodes []*pluginv1alpha1.HardwareNode,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) []*pluginv1alpha1.HardwareNode {
+
+	var claimed []*pluginv1alpha1.HardwareNode
+	for _, hwnode := range hwnodes {
+		if isAllocatedTo(hwnode, hwmgr, nodepool.Name) {
+			claimed = append(claimed, hwnode)
+		}
+	}
+	return claimed
+}
+
+// countClaimedNodes counts the HardwareNodes claimed for each nodegroup
+func countClaimedNodes(claimed []*pluginv1alpha1.HardwareNode) map[string]int {
+	counts := make(map[string]int)
+	for _, hwnode := range claimed {
+		counts[hwnode.Status.Allocation.NodeGroup]++
+	}
+	return counts
+}
+
+// ClaimNodes claims free HardwareNodes for each nodegroup of the NodePool, as needed, returning the updated list of
+// claimed nodes
+func (a *Adaptor) ClaimNodes(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	hwnodes []*pluginv1alpha1.HardwareNode,
+	claimed []*pluginv1alpha1.HardwareNode) ([]*pluginv1alpha1.HardwareNode, error) {
+
+	counts := countClaimedNodes(claimed)
+
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		remaining := nodegroup.Size - counts[nodegroup.NodePoolData.Name]
+		if remaining <= 0 {
+			// This group is allocated
+			a.Logger.InfoContext(ctx, "nodegroup is fully allocated", slog.String("nodegroup", nodegroup.NodePoolData.Name))
+			continue
+		}
+
+		freenodes := getFreeNodes(hwnodes, nodegroup.NodePoolData.ResourcePoolId)
+		if remaining > len(freenodes) {
+			return claimed, fmt.Errorf("not enough free resources remaining in resource pool %s", nodegroup.NodePoolData.ResourcePoolId)
+		}
+
+		for _, hwnode := range freenodes[:remaining] {
+			a.Logger.InfoContext(ctx, "Claiming HardwareNode",
+				slog.String("nodegroup name", nodegroup.NodePoolData.Name),
+				slog.String("hwnode", nodeId(hwnode)))
+
+			allocation := &pluginv1alpha1.HardwareNodeAllocation{
+				HwMgrId:   hwmgr.Name,
+				NodePool:  nodepool.Name,
+				NodeGroup: nodegroup.NodePoolData.Name,
+				NodeName:  utils.GenerateNodeName(),
+			}
+			if err := claimHardwareNode(ctx, a.Client, hwnode, allocation); err != nil {
+				return claimed, err
+			}
+			claimed = append(claimed, hwnode)
+		}
+	}
+
+	return claimed, nil
+}
+
+// CheckNodePoolProgress checks to see if a NodePool is fully allocated, claiming nodes and creating Node CRs as needed
+func (a *Adaptor) CheckNodePoolProgress(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (full bool, err error) {
+
+	hwnodes, err := listHardwareNodes(ctx, a.Client, nodeNamespace(hwmgr))
+	if err != nil {
+		err = fmt.Errorf("unable to get hardware nodes: %w", err)
+		return
+	}
+
+	claimed := getClaimedNodes(hwnodes, hwmgr, nodepool)
+
+	claimed, err = a.ClaimNodes(ctx, hwmgr, nodepool, hwnodes, claimed)
+	if err != nil {
+		err = fmt.Errorf("failed to claim nodes: %w", err)
+		return
+	}
+
+	if err = a.AllocateNodes(ctx, nodepool, claimed); err != nil {
+		err = fmt.Errorf("failed to allocate nodes: %w", err)
+		return
+	}
+
+	full = len(claimed) >= utils.GetNodePoolSize(nodepool)
+
+	return
+}
+
+// HandleNodePoolCreate processes a new NodePool CR
+func (a *Adaptor) HandleNodePoolCreate(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	return utils.HandleAllocationCreate(ctx, a.Client, a.Logger, a, hwmgr, nodepool)
+}
+
+// HandleNodePoolProcessing checks the progress of an in-progress NodePool
+func (a *Adaptor) HandleNodePoolProcessing(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	return utils.HandleAllocationProcessing(ctx, a.Client, a.Logger, a, hwmgr, nodepool)
+}
+
+// ProcessNewNodePool processes a new NodePool CR, verifying that there are enough free nodes to satisfy the request
+func (a *Adaptor) ProcessNewNodePool(ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) error {
+
+	a.Logger.InfoContext(ctx, "Processing ProcessNewNodePool request:",
+		slog.String("nodeNamespace", nodeNamespace(hwmgr)),
+		slog.String("cloudID", nodepool.Spec.CloudID),
+	)
+
+	hwnodes, err := listHardwareNodes(ctx, a.Client, nodeNamespace(hwmgr))
+	if err != nil {
+		return fmt.Errorf("unable to get hardware nodes: %w", err)
+	}
+
+	return utils.CheckFreeResources(nodepool, func(nodegroup hwmgmtv1alpha1.NodeGroup) (int, error) {
+		return len(getFreeNodes(hwnodes, nodegroup.NodePoolData.ResourcePoolId)), nil
+	})
+}
+
+// ReleaseNodePool frees the HardwareNodes allocated to a NodePool
+func (a *Adaptor) ReleaseNodePool(ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) error {
+
+	a.Logger.InfoContext(ctx, "Processing ReleaseNodePool request:",
+		slog.String("cloudID", nodepool.Spec.CloudID),
+	)
+
+	hwnodes, err := listHardwareNodes(ctx, a.Client, nodeNamespace(hwmgr))
+	if err != nil {
+		return fmt.Errorf("unable to get hardware nodes: %w", err)
+	}
+
+	for _, hwnode := range getClaimedNodes(hwnodes, hwmgr, nodepool) {
+		a.Logger.InfoContext(ctx, "Releasing HardwareNode", slog.String("hwnode", nodeId(hwnode)))
+		if err := releaseHardwareNode(ctx, a.Client, hwnode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
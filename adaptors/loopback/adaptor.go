@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 
+	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
 	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/loopback/controller"
 	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
@@ -49,15 +51,23 @@ func NewAdaptor(client client.Client, scheme *runtime.Scheme, logger *slog.Logge
 	}
 }
 
+// capabilities defines the NodePool operations supported by the Loopback adaptor
+var capabilities = pluginv1alpha1.AdaptorCapabilities{
+	ProfileUpdate: true,
+	ScaleOut:      true,
+	ScaleIn:       true,
+}
+
 // SetupAdaptor sets up the Loopback adaptor
 func (a *Adaptor) SetupAdaptor(mgr ctrl.Manager) error {
 	a.Logger.Info("SetupAdaptor called for Loopback")
 
 	if err := (&controller.HardwareManagerReconciler{
-		Client:    a.Client,
-		Scheme:    a.Scheme,
-		Logger:    a.Logger,
-		Namespace: a.Namespace,
+		Client:       a.Client,
+		Scheme:       a.Scheme,
+		Logger:       a.Logger,
+		Namespace:    a.Namespace,
+		Capabilities: capabilities,
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("unable to setup loopback adaptor: %w", err)
 	}
@@ -119,12 +129,83 @@ func (a *Adaptor) HandleNodePool(ctx context.Context, hwmgr *pluginv1alpha1.Hard
 	return result, nil
 }
 
-func (a *Adaptor) HandleNodePoolDeletion(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) error {
+func (a *Adaptor) HandleNodePoolDeletion(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (bool, error) {
 	a.Logger.InfoContext(ctx, "Finalizing nodepool")
 
 	if err := a.ReleaseNodePool(ctx, hwmgr, nodepool); err != nil {
-		return fmt.Errorf("failed to release nodepool %s: %w", nodepool.Name, err)
+		return false, fmt.Errorf("failed to release nodepool %s: %w", nodepool.Name, err)
+	}
+
+	return true, nil
+}
+
+// GetCapabilities returns the NodePool operations supported by the Loopback adaptor
+func (a *Adaptor) GetCapabilities(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) (pluginv1alpha1.AdaptorCapabilities, error) {
+	return capabilities, nil
+}
+
+// CheckReadiness verifies that the nodelist configmap can be parsed
+func (a *Adaptor) CheckReadiness(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) error {
+	if _, _, _, err := a.GetCurrentResources(ctx); err != nil {
+		return fmt.Errorf("failed to get current resources: %w", err)
+	}
+
+	return nil
+}
+
+// CheckNodePoolDrift compares the NodePool and its Node CRs with its allocations in the nodelist configmap
+func (a *Adaptor) CheckNodePoolDrift(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (*adaptorinterface.NodePoolDrift, error) {
+	allocatedNodes, err := a.GetAllocatedNodes(ctx, nodepool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get allocated nodes for %s: %w", nodepool.Name, err)
+	}
+
+	nodelist, err := utils.GetChildNodes(ctx, a.Logger, a.Client, nodepool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child nodes for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	// The configmap records the allocations by node name
+	missing, unexpected := utils.FindNodeDrift(nodepool.Status.Properties.NodeNames, nodelist, allocatedNodes,
+		func(node *hwmgmtv1alpha1.Node) string { return node.Name })
+
+	return &adaptorinterface.NodePoolDrift{MissingNodes: missing, UnexpectedNodes: unexpected}, nil
+}
+
+// RemediateNodePoolDrift releases the missing nodes of the NodePool, along with any allocation without a Node CR
+func (a *Adaptor) RemediateNodePoolDrift(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	drift *adaptorinterface.NodePoolDrift) error {
+
+	if err := a.ReleaseAllocations(ctx, nodepool, append(slices.Clone(drift.MissingNodes), drift.UnexpectedNodes...)); err != nil {
+		return fmt.Errorf("failed to release allocations for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	nodepool.Status.Properties.NodeNames = slices.DeleteFunc(slices.Clone(nodepool.Status.Properties.NodeNames),
+		func(nodename string) bool { return slices.Contains(drift.MissingNodes, nodename) })
+	if err := utils.UpdateNodePoolProperties(ctx, a.Client, nodepool); err != nil {
+		return fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
 	}
 
 	return nil
 }
+
+// GetAvailableCapacity counts the free nodes in the nodelist configmap for each resource pool requested by the NodePool
+func (a *Adaptor) GetAvailableCapacity(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (map[string]int, error) {
+	_, resources, allocations, err := a.GetCurrentResources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current resources: %w", err)
+	}
+
+	capacity := make(map[string]int)
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		poolID := nodegroup.NodePoolData.ResourcePoolId
+		if _, exists := capacity[poolID]; !exists {
+			capacity[poolID] = len(getFreeNodesInPool(resources, allocations, poolID))
+		}
+	}
+
+	return capacity, nil
+}
@@ -37,10 +37,11 @@ import (
 // HardwareManagerReconciler reconciles a HardwareManager object
 type HardwareManagerReconciler struct {
 	client.Client
-	Scheme    *runtime.Scheme
-	Logger    *slog.Logger
-	Namespace string
-	AdaptorID pluginv1alpha1.HardwareManagerAdaptorID
+	Scheme       *runtime.Scheme
+	Logger       *slog.Logger
+	Namespace    string
+	AdaptorID    pluginv1alpha1.HardwareManagerAdaptorID
+	Capabilities pluginv1alpha1.AdaptorCapabilities
 }
 
 //+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=hardwaremanagers,verbs=get;list;watch;create;update;patch;delete
@@ -74,7 +75,8 @@ func (r *HardwareManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 
 	// Make sure this is an instance for this adaptor and that this generation hasn't already been handled
 	if hwmgr.Spec.AdaptorID != r.AdaptorID ||
-		hwmgr.Status.ObservedGeneration == hwmgr.Generation {
+		(hwmgr.Status.ObservedGeneration == hwmgr.Generation &&
+			hwmgr.Status.Capabilities != nil && *hwmgr.Status.Capabilities == r.Capabilities) {
 		// Nothing to do
 		return
 	}
@@ -82,6 +84,7 @@ func (r *HardwareManagerReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	ctx = logging.AppendCtx(ctx, slog.String("hwmgr", hwmgr.Name))
 
 	hwmgr.Status.ObservedGeneration = hwmgr.Generation
+	hwmgr.Status.Capabilities = r.Capabilities.DeepCopy()
 
 	// Configuration data is not currently mandatory for the loopback adaptor
 	if updateErr := utils.UpdateHardwareManagerStatusCondition(ctx, r.Client, hwmgr,
@@ -21,6 +21,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
@@ -29,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 )
 
@@ -110,6 +112,121 @@ func (a *Adaptor) AllocateNode(ctx context.Context, nodepool *hwmgmtv1alpha1.Nod
 	return nil
 }
 
+// ReleaseNode frees a node allocated to a NodePool, deleting its Node CR and bmc-secret
+func (a *Adaptor) ReleaseNode(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, node *hwmgmtv1alpha1.Node) error {
+	cloudID := nodepool.Spec.CloudID
+
+	a.Logger.InfoContext(ctx, "Releasing node",
+		slog.String("nodegroup name", node.Spec.GroupName),
+		slog.String("nodename", node.Name),
+		slog.String("nodeId", node.Spec.HwMgrNodeId))
+
+	cm, _, allocations, err := a.GetCurrentResources(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get current resources: %w", err)
+	}
+
+	for i, cloud := range allocations.Clouds {
+		if cloud.CloudID != cloudID {
+			continue
+		}
+
+		used := cloud.Nodegroups[node.Spec.GroupName]
+		if index := slices.Index(used, node.Name); index != -1 {
+			allocations.Clouds[i].Nodegroups[node.Spec.GroupName] = slices.Delete(used, index, index+1)
+
+			// Update the configmap
+			yamlString, err := yaml.Marshal(&allocations)
+			if err != nil {
+				return fmt.Errorf("unable to marshal allocated data: %w", err)
+			}
+			cm.Data[allocationsKey] = string(yamlString)
+			if err := a.Client.Update(ctx, cm); err != nil {
+				return fmt.Errorf("failed to update configmap: %w", err)
+			}
+		}
+		break
+	}
+
+	return a.DeleteNode(ctx, node.Name)
+}
+
+// ReleaseAllocations frees the named nodes from the allocations of a NodePool in the nodelist configmap, deleting their
+// Node CRs and bmc-secrets if present
+func (a *Adaptor) ReleaseAllocations(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, nodenames []string) error {
+	cloudID := nodepool.Spec.CloudID
+
+	a.Logger.InfoContext(ctx, "Releasing allocations", slog.Any("nodenames", nodenames))
+
+	cm, _, allocations, err := a.GetCurrentResources(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get current resources: %w", err)
+	}
+
+	updated := false
+	for _, cloud := range allocations.Clouds {
+		if cloud.CloudID != cloudID {
+			continue
+		}
+
+		for groupname, used := range cloud.Nodegroups {
+			remaining := slices.DeleteFunc(slices.Clone(used), func(nodename string) bool {
+				return slices.Contains(nodenames, nodename)
+			})
+			if len(remaining) != len(used) {
+				cloud.Nodegroups[groupname] = remaining
+				updated = true
+			}
+		}
+		break
+	}
+
+	if updated {
+		// Update the configmap
+		yamlString, err := yaml.Marshal(&allocations)
+		if err != nil {
+			return fmt.Errorf("unable to marshal allocated data: %w", err)
+		}
+		cm.Data[allocationsKey] = string(yamlString)
+		if err := a.Client.Update(ctx, cm); err != nil {
+			return fmt.Errorf("failed to update configmap: %w", err)
+		}
+	}
+
+	for _, nodename := range nodenames {
+		if err := a.DeleteNode(ctx, nodename); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteNode deletes the Node CR and bmc-secret of a node, if present
+func (a *Adaptor) DeleteNode(ctx context.Context, nodename string) error {
+	node := &hwmgmtv1alpha1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodename,
+			Namespace: a.Namespace,
+		},
+	}
+	if err := a.Client.Delete(ctx, node); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete node %s: %w", nodename, err)
+	}
+
+	bmcSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bmcSecretName(nodename),
+			Namespace: a.Namespace,
+		},
+	}
+	if err := a.Client.Delete(ctx, bmcSecret); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete bmc-secret for node %s: %w", nodename, err)
+	}
+
+	return nil
+}
+
 func bmcSecretName(nodename string) string {
 	return fmt.Sprintf("%s-bmc-secret", nodename)
 }
@@ -26,6 +26,7 @@ import (
 	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
 	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -138,95 +139,114 @@ func (a *Adaptor) HandleNodePoolProcessing(
 	return result, nil
 }
 
-func (a *Adaptor) checkNodeUpgradeProcess(
-	ctx context.Context,
-	allocatedNodes []string) ([]*hwmgmtv1alpha1.Node, []*hwmgmtv1alpha1.Node, error) {
-
-	var upgradedNodes []*hwmgmtv1alpha1.Node
-	var nodesStillUpgrading []*hwmgmtv1alpha1.Node
-
-	for _, name := range allocatedNodes {
-		// Fetch the latest version of each node to ensure up-to-date status
-		updatedNode, err := utils.GetNode(ctx, a.Logger, a.Client, a.Namespace, name)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get node %s: %w", name, err)
+// completeNodeUpdates completes the simulated profile update jobs of the nodes in flight. The loopback adaptor has no
+// hardware to update, so each job completes when it is first checked.
+func (a *Adaptor) completeNodeUpdates(ctx context.Context, inProgress []*hwmgmtv1alpha1.Node) error {
+	for _, node := range inProgress {
+		a.Logger.InfoContext(ctx, "Node update complete",
+			slog.String("nodename", node.Name),
+			slog.String("jobId", utils.GetJobId(node)))
+
+		node.Status.HwProfile = node.Spec.HwProfile
+		if err := utils.UpdateK8sCRStatus(ctx, a.Client, node); err != nil {
+			return fmt.Errorf("failed to update status for node %s: %w", node.Name, err)
 		}
 
-		if updatedNode.Status.HwProfile == updatedNode.Spec.HwProfile {
-			// Node has completed the upgrade
-			upgradedNodes = append(upgradedNodes, updatedNode)
-		} else {
-			updatedNode.Status.HwProfile = updatedNode.Spec.HwProfile
-			if err := utils.UpdateK8sCRStatus(ctx, a.Client, updatedNode); err != nil {
-				return nil, nil, fmt.Errorf("failed to update status for node %s: %w", updatedNode.Name, err)
-			}
-			nodesStillUpgrading = append(nodesStillUpgrading, updatedNode)
+		utils.ClearJobId(node)
+		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, node, nil, utils.PATCH); err != nil {
+			return fmt.Errorf("failed to clear annotation from node %s: %w", node.Name, err)
 		}
 	}
 
-	return upgradedNodes, nodesStillUpgrading, nil
+	return nil
 }
 
 func (a *Adaptor) handleNodePoolConfiguring(
 	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
 	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
 
-	var nodesToCheck []*hwmgmtv1alpha1.Node // To track nodes that we actually attempted to upgrade
 	var result ctrl.Result
 
 	a.Logger.InfoContext(ctx, "Handling Node Pool Configuring")
 
+	strategy, err := utils.GetRolloutStrategy(nodepool)
+	if err != nil {
+		return a.failNodePoolConfiguring(ctx, nodepool, err.Error())
+	}
+
 	allocatedNodes, err := a.GetAllocatedNodes(ctx, nodepool)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to get allocated nodes for %s: %w", nodepool.Name, err)
 	}
 
-	// Stage 1: Initiate upgrades by updating node.Spec.HwProfile as necessary
+	nodelist := &hwmgmtv1alpha1.NodeList{}
 	for _, name := range allocatedNodes {
 		node, err := utils.GetNode(ctx, a.Logger, a.Client, a.Namespace, name)
 		if err != nil {
 			return utils.RequeueWithShortInterval(), err
 		}
-		// Check each node against each nodegroup in the node pool spec
-		for _, nodegroup := range nodepool.Spec.NodeGroup {
-			if node.Spec.GroupName != nodegroup.NodePoolData.Name || node.Spec.HwProfile == nodegroup.NodePoolData.HwProfile {
-				continue
-			}
-			// Node needs an upgrade, so update Spec.HwProfile
+		nodelist.Items = append(nodelist.Items, *node)
+	}
+
+	// Stage 1: Track the completion of the batch of updates in flight
+	if inProgress := utils.FindNodesUpdateInProgress(nodelist); len(inProgress) > 0 {
+		if err := a.completeNodeUpdates(ctx, inProgress); err != nil {
+			return utils.RequeueWithShortInterval(), fmt.Errorf("failed to check upgrade status for nodes: %w", err)
+		}
+
+		// Record the completion time, from which the pause before the next batch is measured
+		utils.SetRolloutBatchCompleted(nodepool, time.Now())
+		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+			return utils.RequeueWithShortInterval(), fmt.Errorf("failed to annotate nodepool %s: %w", nodepool.Name, err)
+		}
+		return utils.RequeueImmediately(), nil
+	}
+
+	// Stage 2: Initiate the next batch of upgrades by updating node.Spec.HwProfile, with a simulated jobId
+	if newHwProfile, batch := strategy.SelectRolloutBatch(nodepool, nodelist); len(batch) > 0 {
+		if remaining := strategy.GetBatchPauseRemaining(nodepool, time.Now()); remaining > 0 {
+			a.Logger.InfoContext(ctx, "Pausing before the next batch of profile updates", slog.Duration("remaining", remaining))
+			return utils.RequeueWithCustomInterval(remaining), nil
+		}
+
+		if wait, result, err := utils.WaitForMaintenanceWindow(ctx, a.Client, hwmgr, nodepool); wait || err != nil {
+			return result, err
+		}
+
+		for _, node := range batch {
+			a.Logger.InfoContext(ctx, "Issuing profile update to node",
+				slog.String("nodename", node.Name),
+				slog.String("curHwProfile", node.Spec.HwProfile),
+				slog.String("newHwProfile", newHwProfile))
+
 			patch := client.MergeFrom(node.DeepCopy())
-			node.Spec.HwProfile = nodegroup.NodePoolData.HwProfile
+			node.Spec.HwProfile = newHwProfile
+			utils.SetJobId(node, fmt.Sprintf("loopback-%s-%d", node.Name, nodepool.Generation))
 			if err = a.Client.Patch(ctx, node, patch); err != nil {
 				return utils.RequeueWithShortInterval(), fmt.Errorf("failed to patch Node %s in namespace %s: %w", node.Name, node.Namespace, err)
 			}
-			nodesToCheck = append(nodesToCheck, node) // Track nodes we attempted to upgrade
-			break
 		}
-	}
 
-	// Requeue if there are nodes to check
-	if len(nodesToCheck) > 0 {
-		return utils.RequeueWithCustomInterval(30 * time.Second), nil
+		// The simulated updates complete on the next pass, so there is nothing to wait for
+		return utils.RequeueImmediately(), nil
 	}
 
-	// Stage 2: Verify and track completion of upgrades
-	_, nodesStillUpgrading, err := a.checkNodeUpgradeProcess(ctx, allocatedNodes)
-	if err != nil {
-		return utils.RequeueWithShortInterval(), fmt.Errorf("failed to check upgrade status for nodes: %w", err)
+	// All nodes are upgraded
+	if _, exists := nodepool.GetAnnotations()[utils.RolloutBatchCompletedAnnotation]; exists {
+		utils.ClearRolloutBatchCompleted(nodepool)
+		if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, nodepool, nil, utils.PATCH); err != nil {
+			return utils.RequeueWithShortInterval(), fmt.Errorf("failed to clear annotation from nodepool %s: %w", nodepool.Name, err)
+		}
 	}
 
-	// Update NodePool status if all nodes are upgraded
-	if len(nodesStillUpgrading) == 0 {
-		if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
-			hwmgmtv1alpha1.Configured, hwmgmtv1alpha1.ConfigApplied, metav1.ConditionTrue, string(hwmgmtv1alpha1.ConfigSuccess)); err != nil {
-			return utils.RequeueWithShortInterval(), fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
-		}
-		// Update the Node Pool hwMgrPlugin status
-		if err = utils.UpdateNodePoolPluginStatus(ctx, a.Client, nodepool); err != nil {
-			return utils.RequeueWithShortInterval(), fmt.Errorf("failed to update hwMgrPlugin observedGeneration Status: %w", err)
-		}
-	} else {
-		// Requeue if there are still nodes upgrading
-		return utils.RequeueWithMediumInterval(), nil
+	if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+		hwmgmtv1alpha1.Configured, hwmgmtv1alpha1.ConfigApplied, metav1.ConditionTrue, string(hwmgmtv1alpha1.ConfigSuccess)); err != nil {
+		return utils.RequeueWithShortInterval(), fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+	// Update the Node Pool hwMgrPlugin status
+	if err = utils.UpdateNodePoolPluginStatus(ctx, a.Client, nodepool); err != nil {
+		return utils.RequeueWithShortInterval(), fmt.Errorf("failed to update hwMgrPlugin observedGeneration Status: %w", err)
 	}
 
 	return result, nil
@@ -249,7 +269,167 @@ func (a *Adaptor) HandleNodePoolSpecChanged(
 			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
 	}
 
-	return a.handleNodePoolConfiguring(ctx, nodepool)
+	if done, result, err := a.handleNodePoolScaling(ctx, hwmgr, nodepool); !done || err != nil {
+		return result, err
+	}
+
+	return a.handleNodePoolConfiguring(ctx, hwmgr, nodepool)
+}
+
+// handleNodePoolScaling allocates or releases nodes as needed to match the size of each nodegroup, returning true
+// once all nodegroups are at their requested size
+func (a *Adaptor) handleNodePoolScaling(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (bool, ctrl.Result, error) {
+
+	cloudID := nodepool.Spec.CloudID
+
+	_, resources, allocations, err := a.GetCurrentResources(ctx)
+	if err != nil {
+		return false, utils.RequeueWithShortInterval(), fmt.Errorf("unable to get current resources: %w", err)
+	}
+
+	var cloud *cmAllocatedCloud
+	for i, iter := range allocations.Clouds {
+		if iter.CloudID == cloudID {
+			cloud = &allocations.Clouds[i]
+			break
+		}
+	}
+
+	var toRelease []hwmgmtv1alpha1.Node
+	scaleOut := false
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		var used []string
+		if cloud != nil {
+			used = cloud.Nodegroups[nodegroup.NodePoolData.Name]
+		}
+
+		if len(used) > nodegroup.Size {
+			var nodes []hwmgmtv1alpha1.Node
+			for _, name := range used {
+				node, err := utils.GetNode(ctx, a.Logger, a.Client, a.Namespace, name)
+				if err != nil {
+					return false, utils.RequeueWithShortInterval(), fmt.Errorf("failed to get node %s: %w", name, err)
+				}
+				nodes = append(nodes, *node)
+			}
+
+			selected, err := utils.SelectNodesForRemoval(nodepool, nodes, len(used)-nodegroup.Size)
+			if err != nil {
+				return a.failNodePoolScaling(ctx, nodepool, err.Error())
+			}
+			toRelease = append(toRelease, selected...)
+		} else if len(used) < nodegroup.Size {
+			freenodes := getFreeNodesInPool(resources, allocations, nodegroup.NodePoolData.ResourcePoolId)
+			if nodegroup.Size-len(used) > len(freenodes) {
+				return a.failNodePoolScaling(ctx, nodepool,
+					fmt.Sprintf("not enough free resources in resource pool %s to scale out nodegroup %s: freenodes=%d",
+						nodegroup.NodePoolData.ResourcePoolId, nodegroup.NodePoolData.Name, len(freenodes)))
+			}
+			scaleOut = true
+		}
+	}
+
+	if len(toRelease) == 0 && !scaleOut {
+		scaledCondition := meta.FindStatusCondition(nodepool.Status.Conditions, string(utils.NodePoolScaled))
+		if scaledCondition != nil && scaledCondition.Reason == string(hwmgmtv1alpha1.InProgress) {
+			if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+				utils.NodePoolScaled, hwmgmtv1alpha1.Completed, metav1.ConditionTrue, "Scaled"); err != nil {
+				return false, utils.RequeueWithShortInterval(),
+					fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+			}
+		}
+		return true, utils.DoNotRequeue(), nil
+	}
+
+	// Releasing nodes is disruptive, so is only started within a maintenance window
+	if len(toRelease) > 0 {
+		if wait, result, err := utils.WaitForMaintenanceWindow(ctx, a.Client, hwmgr, nodepool); wait || err != nil {
+			return false, result, err
+		}
+	}
+
+	a.Logger.InfoContext(ctx, "Scaling NodePool",
+		slog.String("cloudID", cloudID),
+		slog.Int("releasing", len(toRelease)),
+		slog.Bool("scaleOut", scaleOut))
+
+	if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+		utils.NodePoolScaled, hwmgmtv1alpha1.InProgress, metav1.ConditionFalse, "Scaling nodegroups"); err != nil {
+		return false, utils.RequeueWithShortInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	for i := range toRelease {
+		if err := a.ReleaseNode(ctx, nodepool, &toRelease[i]); err != nil {
+			return false, utils.RequeueWithShortInterval(), fmt.Errorf("failed to release node %s: %w", toRelease[i].Name, err)
+		}
+	}
+
+	if scaleOut {
+		if err := a.AllocateNode(ctx, nodepool); err != nil {
+			return false, utils.RequeueWithShortInterval(), fmt.Errorf("failed to allocate node: %w", err)
+		}
+	}
+
+	allocatedNodes, err := a.GetAllocatedNodes(ctx, nodepool)
+	if err != nil {
+		return false, utils.RequeueWithShortInterval(), fmt.Errorf("failed to get allocated nodes for %s: %w", nodepool.Name, err)
+	}
+	nodepool.Status.Properties.NodeNames = allocatedNodes
+
+	if err := utils.UpdateNodePoolProperties(ctx, a.Client, nodepool); err != nil {
+		return false, utils.RequeueWithMediumInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	return false, utils.RequeueWithShortInterval(), nil
+}
+
+// failNodePoolScaling reports a scaling failure, marking the spec change as handled so that it is not retried
+func (a *Adaptor) failNodePoolScaling(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	message string) (bool, ctrl.Result, error) {
+
+	a.Logger.InfoContext(ctx, "NodePool scaling failed", slog.String("reason", message))
+
+	if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+		utils.NodePoolScaled, hwmgmtv1alpha1.Failed, metav1.ConditionFalse, message); err != nil {
+		return false, utils.RequeueWithShortInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	if err := utils.UpdateNodePoolPluginStatus(ctx, a.Client, nodepool); err != nil {
+		return false, utils.RequeueWithShortInterval(),
+			fmt.Errorf("failed to update hwMgrPlugin observedGeneration Status: %w", err)
+	}
+
+	return false, utils.DoNotRequeue(), nil
+}
+
+// failNodePoolConfiguring reports a configuration failure, marking the spec change as handled so that it is not retried
+func (a *Adaptor) failNodePoolConfiguring(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	message string) (ctrl.Result, error) {
+
+	a.Logger.InfoContext(ctx, "NodePool configuration failed", slog.String("reason", message))
+
+	if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+		hwmgmtv1alpha1.Configured, hwmgmtv1alpha1.Failed, metav1.ConditionFalse, message); err != nil {
+		return utils.RequeueWithShortInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	if err := utils.UpdateNodePoolPluginStatus(ctx, a.Client, nodepool); err != nil {
+		return utils.RequeueWithShortInterval(),
+			fmt.Errorf("failed to update hwMgrPlugin observedGeneration Status: %w", err)
+	}
+
+	return utils.DoNotRequeue(), nil
 }
 
 // ProcessNewNodePool processes a new NodePool CR, verifying that there are enough free resources to satisfy the request
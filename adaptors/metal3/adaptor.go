@@ -0,0 +1,149 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal3
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/metal3/controller"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type Adaptor struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Logger    *slog.Logger
+	Namespace string
+	AdaptorID pluginv1alpha1.HardwareManagerAdaptorID
+}
+
+func NewAdaptor(client client.Client, scheme *runtime.Scheme, logger *slog.Logger, namespace string) *Adaptor {
+	return &Adaptor{
+		Client:    client,
+		Scheme:    scheme,
+		Logger:    logger.With("adaptor", "metal3"),
+		Namespace: namespace,
+		AdaptorID: pluginv1alpha1.SupportedAdaptors.Metal3,
+	}
+}
+
+// capabilities defines the NodePool operations supported by the Metal3 adaptor
+var capabilities = pluginv1alpha1.AdaptorCapabilities{}
+
+// SetupAdaptor sets up the Metal3 adaptor
+func (a *Adaptor) SetupAdaptor(mgr ctrl.Manager) error {
+	a.Logger.Info("SetupAdaptor called for Metal3")
+
+	if err := (&controller.HardwareManagerReconciler{
+		Client:       a.Client,
+		Scheme:       a.Scheme,
+		Logger:       a.Logger,
+		Namespace:    a.Namespace,
+		Capabilities: capabilities,
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to setup metal3 adaptor: %w", err)
+	}
+
+	return nil
+}
+
+// Metal3 Adaptor FSM
+type fsmAction int
+
+const (
+	NodePoolFSMCreate = iota
+	NodePoolFSMProcessing
+	NodePoolFSMNoop
+)
+
+func (a *Adaptor) determineAction(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) fsmAction {
+	if len(nodepool.Status.Conditions) == 0 {
+		a.Logger.InfoContext(ctx, "Handling Create NodePool request")
+		return NodePoolFSMCreate
+	}
+
+	provisionedCondition := meta.FindStatusCondition(
+		nodepool.Status.Conditions,
+		string(hwmgmtv1alpha1.Provisioned))
+	if provisionedCondition != nil {
+		if provisionedCondition.Status == metav1.ConditionTrue {
+			a.Logger.InfoContext(ctx, "NodePool request in Provisioned state")
+			return NodePoolFSMNoop
+		}
+
+		return NodePoolFSMProcessing
+	}
+
+	return NodePoolFSMNoop
+}
+
+func (a *Adaptor) HandleNodePool(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+	result := utils.DoNotRequeue()
+
+	switch a.determineAction(ctx, nodepool) {
+	case NodePoolFSMCreate:
+		return a.HandleNodePoolCreate(ctx, hwmgr, nodepool)
+	case NodePoolFSMProcessing:
+		return a.HandleNodePoolProcessing(ctx, hwmgr, nodepool)
+	case NodePoolFSMNoop:
+		// Nothing to do
+		return result, nil
+	}
+
+	return result, nil
+}
+
+func (a *Adaptor) HandleNodePoolDeletion(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (bool, error) {
+	a.Logger.InfoContext(ctx, "Finalizing nodepool")
+
+	if err := a.ReleaseNodePool(ctx, hwmgr, nodepool); err != nil {
+		return false, fmt.Errorf("failed to release nodepool %s: %w", nodepool.Name, err)
+	}
+
+	return true, nil
+}
+
+// GetCapabilities returns the NodePool operations supported by the Metal3 adaptor
+func (a *Adaptor) GetCapabilities(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) (pluginv1alpha1.AdaptorCapabilities, error) {
+	return capabilities, nil
+}
+
+// CheckReadiness verifies that the BareMetalHosts can be listed
+func (a *Adaptor) CheckReadiness(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) error {
+	if _, err := listBareMetalHosts(ctx, a.Client, bmhNamespace(hwmgr), nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// bmhNamespace returns the namespace from which BareMetalHosts are allocated for the HardwareManager
+func bmhNamespace(hwmgr *pluginv1alpha1.HardwareManager) string {
+	if hwmgr.Spec.Metal3Data != nil && hwmgr.Spec.Metal3Data.BmhNamespace != "" {
+		return hwmgr.Spec.Metal3Data.BmhNamespace
+	}
+	return hwmgr.Namespace
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal3
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The BareMetalHost CRs are accessed as unstructured objects, converted to the minimal set of fields used by the
+// adaptor, rather than importing the Metal3 API module. As a side effect, reads are not served from the manager
+// cache, which is restricted to the plugin namespace, so BareMetalHosts can be allocated from any namespace.
+var bmhGVK = schema.GroupVersionKind{
+	Group:   "metal3.io",
+	Version: "v1alpha1",
+	Kind:    "BareMetalHost",
+}
+
+// Labels and annotations used to select and claim BareMetalHosts
+const (
+	ResourcePoolIdLabel = "hwmgr-plugin.oran.openshift.io/resourcePoolId"
+	HwProfileLabel      = "hwmgr-plugin.oran.openshift.io/hwProfile"
+	ClaimLabel          = "hwmgr-plugin.oran.openshift.io/claimed-by"
+	ClaimNodePoolAnnot  = "hwmgr-plugin.oran.openshift.io/claimed-by-nodepool"
+	ClaimNodeGroupAnnot = "hwmgr-plugin.oran.openshift.io/claimed-by-nodegroup"
+)
+
+// BareMetalHost provisioning state in which a host is available for allocation
+const bmhStateAvailable = "available"
+
+// Label of the interface used to boot the node, as expected by the O-Cloud Manager
+const bootableInterfaceLabel = "bootable-interface"
+
+type bmhBMC struct {
+	Address         string `json:"address,omitempty"`
+	CredentialsName string `json:"credentialsName,omitempty"`
+}
+
+type bmhSpec struct {
+	BMC            bmhBMC                  `json:"bmc,omitempty"`
+	BootMACAddress string                  `json:"bootMACAddress,omitempty"`
+	ConsumerRef    *corev1.ObjectReference `json:"consumerRef,omitempty"`
+}
+
+type bmhNIC struct {
+	Name string `json:"name,omitempty"`
+	MAC  string `json:"mac,omitempty"`
+}
+
+type bmhHardware struct {
+	NICs []bmhNIC `json:"nics,omitempty"`
+}
+
+type bmhProvisioning struct {
+	State string `json:"state,omitempty"`
+}
+
+type bmhStatus struct {
+	Provisioning bmhProvisioning `json:"provisioning,omitempty"`
+	Hardware     *bmhHardware    `json:"hardware,omitempty"`
+}
+
+// bareMetalHost is the adaptor view of a BareMetalHost CR
+type bareMetalHost struct {
+	Name      string
+	Namespace string
+	Labels    map[string]string
+	Spec      bmhSpec
+	Status    bmhStatus
+
+	// The full object, used to update the claim
+	object *unstructured.Unstructured
+}
+
+// nodeId returns the identifier of the BareMetalHost, used as the hardware manager node ID
+func (h *bareMetalHost) nodeId() string {
+	return types.NamespacedName{Namespace: h.Namespace, Name: h.Name}.String()
+}
+
+// isClaimed checks whether the BareMetalHost is claimed by any NodePool
+func (h *bareMetalHost) isClaimed() bool {
+	_, claimed := h.Labels[ClaimLabel]
+	return claimed
+}
+
+// isAvailable checks whether the BareMetalHost is free to be allocated
+func (h *bareMetalHost) isAvailable() bool {
+	return !h.isClaimed() && h.Spec.ConsumerRef == nil && h.Status.Provisioning.State == bmhStateAvailable
+}
+
+func newBareMetalHost(object *unstructured.Unstructured) (*bareMetalHost, error) {
+	host := &bareMetalHost{
+		Name:      object.GetName(),
+		Namespace: object.GetNamespace(),
+		Labels:    object.GetLabels(),
+		object:    object,
+	}
+
+	if spec, found := object.Object["spec"].(map[string]interface{}); found {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(spec, &host.Spec); err != nil {
+			return nil, fmt.Errorf("failed to parse spec of BareMetalHost %s: %w", host.nodeId(), err)
+		}
+	}
+
+	if status, found := object.Object["status"].(map[string]interface{}); found {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(status, &host.Status); err != nil {
+			return nil, fmt.Errorf("failed to parse status of BareMetalHost %s: %w", host.nodeId(), err)
+		}
+	}
+
+	return host, nil
+}
+
+// listBareMetalHosts gets the BareMetalHosts in the namespace that match the labels
+func listBareMetalHosts(ctx context.Context, c client.Client, namespace string, labels client.MatchingLabels) ([]*bareMetalHost, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(bmhGVK.GroupVersion().WithKind(bmhGVK.Kind + "List"))
+
+	if err := c.List(ctx, list, client.InNamespace(namespace), labels); err != nil {
+		return nil, fmt.Errorf("failed to list BareMetalHosts in namespace %s: %w", namespace, err)
+	}
+
+	hosts := make([]*bareMetalHost, 0, len(list.Items))
+	for i := range list.Items {
+		host, err := newBareMetalHost(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host)
+	}
+
+	return hosts, nil
+}
+
+// claimBareMetalHost labels the BareMetalHost as claimed by the NodePool, and sets the NodePool as its consumerRef so
+// that other Metal3 consumers do not provision the host. The update is made against the resourceVersion of the
+// listed object, so a concurrent claim results in a conflict error.
+func claimBareMetalHost(ctx context.Context, c client.Client, host *bareMetalHost, nodepool *hwmgmtv1alpha1.NodePool, groupname string) error {
+	object := host.object.DeepCopy()
+
+	labels := object.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[ClaimLabel] = string(nodepool.UID)
+	object.SetLabels(labels)
+
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[ClaimNodePoolAnnot] = nodepool.Name
+	annotations[ClaimNodeGroupAnnot] = groupname
+	object.SetAnnotations(annotations)
+
+	consumerRef := &corev1.ObjectReference{
+		APIVersion: hwmgmtv1alpha1.GroupVersion.String(),
+		Kind:       "NodePool",
+		Namespace:  nodepool.Namespace,
+		Name:       nodepool.Name,
+		UID:        nodepool.UID,
+	}
+	ref, err := runtime.DefaultUnstructuredConverter.ToUnstructured(consumerRef)
+	if err != nil {
+		return fmt.Errorf("failed to build consumerRef for BareMetalHost %s: %w", host.nodeId(), err)
+	}
+	if err := unstructured.SetNestedMap(object.Object, ref, "spec", "consumerRef"); err != nil {
+		return fmt.Errorf("failed to set consumerRef for BareMetalHost %s: %w", host.nodeId(), err)
+	}
+
+	if err := c.Update(ctx, object); err != nil {
+		return fmt.Errorf("failed to claim BareMetalHost %s: %w", host.nodeId(), err)
+	}
+
+	host.object = object
+	host.Labels = labels
+	host.Spec.ConsumerRef = consumerRef
+	return nil
+}
+
+// releaseBareMetalHost removes the NodePool claim from the BareMetalHost, along with the consumerRef if it still
+// references the claiming NodePool
+func releaseBareMetalHost(ctx context.Context, c client.Client, host *bareMetalHost) error {
+	object := host.object.DeepCopy()
+
+	labels := object.GetLabels()
+	claimedBy := labels[ClaimLabel]
+	delete(labels, ClaimLabel)
+	object.SetLabels(labels)
+
+	annotations := object.GetAnnotations()
+	delete(annotations, ClaimNodePoolAnnot)
+	delete(annotations, ClaimNodeGroupAnnot)
+	object.SetAnnotations(annotations)
+
+	if ref := host.Spec.ConsumerRef; ref != nil && ref.Kind == "NodePool" && string(ref.UID) == claimedBy {
+		unstructured.RemoveNestedField(object.Object, "spec", "consumerRef")
+	}
+
+	if err := c.Update(ctx, object); err != nil {
+		if errors.IsNotFound(err) {
+			// The host has been deleted, so there is no claim to release
+			return nil
+		}
+		return fmt.Errorf("failed to release BareMetalHost %s: %w", host.nodeId(), err)
+	}
+
+	return nil
+}
+
+// getNodeGroup returns the nodegroup for which the BareMetalHost was claimed
+func (h *bareMetalHost) getNodeGroup() string {
+	return h.object.GetAnnotations()[ClaimNodeGroupAnnot]
+}
+
+// getCredentials reads the username and password from the BMC credentials secret of the BareMetalHost
+func getCredentials(ctx context.Context, c client.Client, host *bareMetalHost) ([]byte, []byte, error) {
+	username, password, err := utils.GetBMCCredentials(ctx, c, host.Spec.BMC.CredentialsName, host.Namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("BareMetalHost %s: %w", host.nodeId(), err)
+	}
+	return username, password, nil
+}
+
+// macAddressEqual compares MAC addresses, ignoring case
+func macAddressEqual(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
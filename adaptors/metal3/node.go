@@ -0,0 +1,226 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal3
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// AllocateNodes creates a Node CR for each claimed BareMetalHost that does not already have one
+func (a *Adaptor) AllocateNodes(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, claimed []*bareMetalHost) error {
+	nodelist, err := utils.GetChildNodes(ctx, a.Logger, a.Client, nodepool)
+	if err != nil {
+		return fmt.Errorf("failed to get child nodes for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	for _, host := range claimed {
+		if utils.FindNodeInList(*nodelist, nodepool.Spec.HwMgrId, host.nodeId()) != "" {
+			// Node already created
+			continue
+		}
+
+		if err := a.AllocateNode(ctx, nodepool, host); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AllocateNode creates the Node CR and BMC secret for a claimed BareMetalHost
+func (a *Adaptor) AllocateNode(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, host *bareMetalHost) error {
+	nodename := utils.GenerateNodeName()
+	nodeId := host.nodeId()
+
+	var hwprofile string
+	groupname := host.getNodeGroup()
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		if nodegroup.NodePoolData.Name == groupname {
+			hwprofile = nodegroup.NodePoolData.HwProfile
+			break
+		}
+	}
+
+	username, password, err := getCredentials(ctx, a.Client, host)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials when allocating node %s, nodeId %s: %w", nodename, nodeId, err)
+	}
+
+	if err := a.CreateBMCSecret(ctx, nodepool, nodename, username, password); err != nil {
+		return fmt.Errorf("failed to create bmc-secret when allocating node %s, nodeId %s: %w", nodename, nodeId, err)
+	}
+
+	if err := a.CreateNode(ctx, nodepool, nodename, nodeId, groupname, hwprofile); err != nil {
+		return fmt.Errorf("failed to create allocated node (%s): %w", nodename, err)
+	}
+
+	if err := a.UpdateNodeStatus(ctx, nodename, host, hwprofile); err != nil {
+		return fmt.Errorf("failed to update node status (%s): %w", nodename, err)
+	}
+
+	return nil
+}
+
+func bmcSecretName(nodename string) string {
+	return fmt.Sprintf("%s-bmc-secret", nodename)
+}
+
+// CreateBMCSecret creates the bmc-secret for a node
+func (a *Adaptor) CreateBMCSecret(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, nodename string, username, password []byte) error {
+	a.Logger.InfoContext(ctx, "Creating bmc-secret:", slog.String("nodename", nodename))
+
+	blockDeletion := true
+	bmcSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bmcSecretName(nodename),
+			Namespace: a.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         nodepool.APIVersion,
+				Kind:               nodepool.Kind,
+				Name:               nodepool.Name,
+				UID:                nodepool.UID,
+				BlockOwnerDeletion: &blockDeletion,
+			}},
+		},
+		Data: map[string][]byte{
+			"username": username,
+			"password": password,
+		},
+	}
+
+	if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, bmcSecret, nil, utils.UPDATE); err != nil {
+		return fmt.Errorf("failed to create bmc-secret for node %s: %w", nodename, err)
+	}
+
+	return nil
+}
+
+// CreateNode creates a Node CR with specified attributes
+func (a *Adaptor) CreateNode(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, nodename, nodeId, groupname, hwprofile string) error {
+	a.Logger.InfoContext(ctx, "Creating node",
+		slog.String("nodegroup name", groupname),
+		slog.String("nodename", nodename),
+		slog.String("nodeId", nodeId))
+
+	blockDeletion := true
+	node := &hwmgmtv1alpha1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodename,
+			Namespace: a.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         nodepool.APIVersion,
+				Kind:               nodepool.Kind,
+				Name:               nodepool.Name,
+				UID:                nodepool.UID,
+				BlockOwnerDeletion: &blockDeletion,
+			}},
+		},
+		Spec: hwmgmtv1alpha1.NodeSpec{
+			NodePool:    nodepool.Name,
+			GroupName:   groupname,
+			HwProfile:   hwprofile,
+			HwMgrId:     nodepool.Spec.HwMgrId,
+			HwMgrNodeId: nodeId,
+		},
+	}
+
+	if err := a.Client.Create(ctx, node); err != nil {
+		return fmt.Errorf("failed to create Node: %w", err)
+	}
+
+	return nil
+}
+
+// getInterfaces builds the node interface list from the NIC inventory of the BareMetalHost, labelling the
+// interface used to boot the host
+func getInterfaces(host *bareMetalHost) []*hwmgmtv1alpha1.Interface {
+	var interfaces []*hwmgmtv1alpha1.Interface
+	if host.Status.Hardware == nil {
+		return interfaces
+	}
+
+	for _, nic := range host.Status.Hardware.NICs {
+		iface := &hwmgmtv1alpha1.Interface{
+			Name:       nic.Name,
+			MACAddress: nic.MAC,
+		}
+		if macAddressEqual(nic.MAC, host.Spec.BootMACAddress) {
+			iface.Label = bootableInterfaceLabel
+		}
+		interfaces = append(interfaces, iface)
+	}
+
+	return interfaces
+}
+
+// UpdateNodeStatus updates a Node CR status field with additional node information from the BareMetalHost
+func (a *Adaptor) UpdateNodeStatus(ctx context.Context, nodename string, host *bareMetalHost, hwprofile string) error {
+	a.Logger.InfoContext(ctx, "Updating node", slog.String("nodename", nodename))
+
+	node := &hwmgmtv1alpha1.Node{}
+
+	if err := utils.RetryOnConflictOrRetriableOrNotFound(retry.DefaultRetry, func() error {
+		return a.Get(ctx, types.NamespacedName{Name: nodename, Namespace: a.Namespace}, node)
+	}); err != nil {
+		return fmt.Errorf("failed to get Node for update: %w", err)
+	}
+
+	a.Logger.InfoContext(ctx, "Adding info to node",
+		slog.String("nodename", nodename),
+		slog.String("bmh", host.nodeId()))
+	node.Status.BMC = &hwmgmtv1alpha1.BMC{
+		Address:         host.Spec.BMC.Address,
+		CredentialsName: bmcSecretName(nodename),
+	}
+	node.Status.Interfaces = getInterfaces(host)
+
+	utils.SetStatusCondition(&node.Status.Conditions,
+		string(hwmgmtv1alpha1.Provisioned),
+		string(hwmgmtv1alpha1.Completed),
+		metav1.ConditionTrue,
+		"Provisioned")
+	node.Status.HwProfile = hwprofile
+	if err := utils.UpdateK8sCRStatus(ctx, a.Client, node); err != nil {
+		return fmt.Errorf("failed to update status for node %s: %w", nodename, err)
+	}
+
+	return nil
+}
+
+// GetAllocatedNodes gets the names of the Node CRs allocated to the NodePool
+func (a *Adaptor) GetAllocatedNodes(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) ([]string, error) {
+	nodelist, err := utils.GetChildNodes(ctx, a.Logger, a.Client, nodepool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child nodes for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	var nodenames []string
+	for _, node := range nodelist.Items {
+		nodenames = append(nodenames, node.Name)
+	}
+
+	return nodenames, nil
+}
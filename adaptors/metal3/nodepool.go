@@ -0,0 +1,206 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal3
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// nodegroupSelector returns the labels used to select BareMetalHosts for a nodegroup
+func nodegroupSelector(nodegroup hwmgmtv1alpha1.NodeGroup) client.MatchingLabels {
+	return client.MatchingLabels{
+		ResourcePoolIdLabel: nodegroup.NodePoolData.ResourcePoolId,
+		HwProfileLabel:      nodegroup.NodePoolData.HwProfile,
+	}
+}
+
+// getFreeHosts gets the BareMetalHosts available for allocation to a nodegroup
+func (a *Adaptor) getFreeHosts(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodegroup hwmgmtv1alpha1.NodeGroup) ([]*bareMetalHost, error) {
+
+	hosts, err := listBareMetalHosts(ctx, a.Client, bmhNamespace(hwmgr), nodegroupSelector(nodegroup))
+	if err != nil {
+		return nil, err
+	}
+
+	var free []*bareMetalHost
+	for _, host := range hosts {
+		if host.isAvailable() {
+			free = append(free, host)
+		}
+	}
+
+	return free, nil
+}
+
+// getClaimedHosts gets the BareMetalHosts claimed by the NodePool
+func (a *Adaptor) getClaimedHosts(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) ([]*bareMetalHost, error) {
+
+	return listBareMetalHosts(ctx, a.Client, bmhNamespace(hwmgr), client.MatchingLabels{ClaimLabel: string(nodepool.UID)})
+}
+
+// countClaimedHosts counts the BareMetalHosts claimed for each nodegroup
+func countClaimedHosts(claimed []*bareMetalHost) map[string]int {
+	counts := make(map[string]int)
+	for _, host := range claimed {
+		counts[host.getNodeGroup()]++
+	}
+	return counts
+}
+
+// ClaimHosts claims available BareMetalHosts for each nodegroup of the NodePool, as needed, returning the updated
+// list of claimed hosts
+func (a *Adaptor) ClaimHosts(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	claimed []*bareMetalHost) ([]*bareMetalHost, error) {
+
+	counts := countClaimedHosts(claimed)
+
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		remaining := nodegroup.Size - counts[nodegroup.NodePoolData.Name]
+		if remaining <= 0 {
+			// This group is allocated
+			a.Logger.InfoContext(ctx, "nodegroup is fully allocated", slog.String("nodegroup", nodegroup.NodePoolData.Name))
+			continue
+		}
+
+		freehosts, err := a.getFreeHosts(ctx, hwmgr, nodegroup)
+		if err != nil {
+			return claimed, fmt.Errorf("unable to get free hosts: %w", err)
+		}
+		if remaining > len(freehosts) {
+			return claimed, fmt.Errorf("not enough free resources remaining in resource pool %s", nodegroup.NodePoolData.ResourcePoolId)
+		}
+
+		for _, host := range freehosts[:remaining] {
+			a.Logger.InfoContext(ctx, "Claiming BareMetalHost",
+				slog.String("nodegroup name", nodegroup.NodePoolData.Name),
+				slog.String("bmh", host.nodeId()))
+
+			if err := claimBareMetalHost(ctx, a.Client, host, nodepool, nodegroup.NodePoolData.Name); err != nil {
+				return claimed, err
+			}
+			claimed = append(claimed, host)
+		}
+	}
+
+	return claimed, nil
+}
+
+// CheckNodePoolProgress checks to see if a NodePool is fully allocated, claiming hosts and creating nodes as needed
+func (a *Adaptor) CheckNodePoolProgress(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (full bool, err error) {
+
+	claimed, err := a.getClaimedHosts(ctx, hwmgr, nodepool)
+	if err != nil {
+		err = fmt.Errorf("unable to get claimed hosts: %w", err)
+		return
+	}
+
+	claimed, err = a.ClaimHosts(ctx, hwmgr, nodepool, claimed)
+	if err != nil {
+		err = fmt.Errorf("failed to claim hosts: %w", err)
+		return
+	}
+
+	if err = a.AllocateNodes(ctx, nodepool, claimed); err != nil {
+		err = fmt.Errorf("failed to allocate nodes: %w", err)
+		return
+	}
+
+	full = len(claimed) >= utils.GetNodePoolSize(nodepool)
+
+	return
+}
+
+// HandleNodePoolCreate processes a new NodePool CR
+func (a *Adaptor) HandleNodePoolCreate(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	return utils.HandleAllocationCreate(ctx, a.Client, a.Logger, a, hwmgr, nodepool)
+}
+
+// HandleNodePoolProcessing checks the progress of an in-progress NodePool
+func (a *Adaptor) HandleNodePoolProcessing(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	return utils.HandleAllocationProcessing(ctx, a.Client, a.Logger, a, hwmgr, nodepool)
+}
+
+// ProcessNewNodePool processes a new NodePool CR, verifying that there are enough free hosts to satisfy the request
+func (a *Adaptor) ProcessNewNodePool(ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) error {
+
+	a.Logger.InfoContext(ctx, "Processing ProcessNewNodePool request:",
+		slog.String("bmhNamespace", bmhNamespace(hwmgr)),
+		slog.String("cloudID", nodepool.Spec.CloudID),
+	)
+
+	return utils.CheckFreeResources(nodepool, func(nodegroup hwmgmtv1alpha1.NodeGroup) (int, error) {
+		freehosts, err := a.getFreeHosts(ctx, hwmgr, nodegroup)
+		if err != nil {
+			return 0, fmt.Errorf("unable to get free hosts: %w", err)
+		}
+		return len(freehosts), nil
+	})
+}
+
+// ReleaseNodePool frees the BareMetalHosts claimed by a NodePool
+func (a *Adaptor) ReleaseNodePool(ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) error {
+
+	a.Logger.InfoContext(ctx, "Processing ReleaseNodePool request:",
+		slog.String("cloudID", nodepool.Spec.CloudID),
+	)
+
+	claimed, err := a.getClaimedHosts(ctx, hwmgr, nodepool)
+	if err != nil {
+		return fmt.Errorf("unable to get claimed hosts: %w", err)
+	}
+
+	for _, host := range claimed {
+		a.Logger.InfoContext(ctx, "Releasing BareMetalHost", slog.String("bmh", host.nodeId()))
+		if err := releaseBareMetalHost(ctx, a.Client, host); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
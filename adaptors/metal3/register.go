@@ -0,0 +1,41 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metal3
+
+import (
+	"log/slog"
+
+	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/registry"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func init() {
+	registry.Register(registry.Registration{
+		ID: pluginv1alpha1.SupportedAdaptors.Metal3,
+		NewAdaptor: func(client client.Client, scheme *runtime.Scheme, logger *slog.Logger, namespace string) adaptorinterface.HwMgrAdaptorIntf {
+			return NewAdaptor(client, scheme, logger, namespace)
+		},
+		// Configuration data is optional for the metal3 adaptor, defaulting to BareMetalHosts in the
+		// HardwareManager namespace
+		ValidateConfig: func(hwmgr *pluginv1alpha1.HardwareManager) error {
+			return nil
+		},
+	})
+}
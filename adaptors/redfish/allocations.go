@@ -0,0 +1,136 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+const allocationsKey = "allocations"
+
+// allocatedSystem records the allocation of a system to a NodePool nodegroup
+type allocatedSystem struct {
+	NodeId    string `json:"nodeId"`
+	NodePool  string `json:"nodePool"`
+	NodeGroup string `json:"nodeGroup"`
+}
+
+// allocations tracks the systems allocated to NodePools
+type allocations struct {
+	Systems []allocatedSystem `json:"systems"`
+}
+
+// allocationsConfigMapName returns the name of the configmap tracking allocations for a HardwareManager
+func allocationsConfigMapName(hwmgr *pluginv1alpha1.HardwareManager) string {
+	return fmt.Sprintf("%s-redfish-allocations", hwmgr.Name)
+}
+
+func (allocs *allocations) isAllocated(nodeId string) bool {
+	return slices.ContainsFunc(allocs.Systems, func(s allocatedSystem) bool { return s.NodeId == nodeId })
+}
+
+// forNodePool returns the systems allocated to a NodePool
+func (allocs *allocations) forNodePool(nodepool string) []allocatedSystem {
+	var systems []allocatedSystem
+	for _, s := range allocs.Systems {
+		if s.NodePool == nodepool {
+			systems = append(systems, s)
+		}
+	}
+	return systems
+}
+
+// count returns the number of systems allocated to a NodePool nodegroup
+func (allocs *allocations) count(nodepool, nodegroup string) int {
+	count := 0
+	for _, s := range allocs.Systems {
+		if s.NodePool == nodepool && s.NodeGroup == nodegroup {
+			count++
+		}
+	}
+	return count
+}
+
+// release frees the systems allocated to a NodePool, returning true if any were allocated
+func (allocs *allocations) release(nodepool string) bool {
+	count := len(allocs.Systems)
+	allocs.Systems = slices.DeleteFunc(allocs.Systems, func(s allocatedSystem) bool { return s.NodePool == nodepool })
+	return len(allocs.Systems) != count
+}
+
+// getAllocations reads the allocations configmap for the HardwareManager, creating it if needed
+func (a *Adaptor) getAllocations(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) (*corev1.ConfigMap, *allocations, error) {
+	cm := &corev1.ConfigMap{}
+	name := types.NamespacedName{Name: allocationsConfigMapName(hwmgr), Namespace: hwmgr.Namespace}
+
+	if err := a.Client.Get(ctx, name, cm); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("failed to get configmap %s: %w", name.Name, err)
+		}
+
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name.Name,
+				Namespace: name.Namespace,
+			},
+			Data: map[string]string{allocationsKey: ""},
+		}
+		if err := controllerutil.SetOwnerReference(hwmgr, cm, a.Scheme); err != nil {
+			return nil, nil, fmt.Errorf("failed to set owner of configmap %s: %w", name.Name, err)
+		}
+		if err := a.Client.Create(ctx, cm); err != nil {
+			return nil, nil, fmt.Errorf("failed to create configmap %s: %w", name.Name, err)
+		}
+	}
+
+	allocs := &allocations{}
+	if err := yaml.Unmarshal([]byte(cm.Data[allocationsKey]), allocs); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse allocations from configmap %s: %w", name.Name, err)
+	}
+
+	return cm, allocs, nil
+}
+
+// updateAllocations writes the allocations to the configmap. The update is made against the resourceVersion of the
+// configmap that was read, so a concurrent update results in a conflict error.
+func (a *Adaptor) updateAllocations(ctx context.Context, cm *corev1.ConfigMap, allocs *allocations) error {
+	yamlString, err := yaml.Marshal(allocs)
+	if err != nil {
+		return fmt.Errorf("unable to marshal allocated data: %w", err)
+	}
+
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	cm.Data[allocationsKey] = string(yamlString)
+	if err := a.Client.Update(ctx, cm); err != nil {
+		return fmt.Errorf("failed to update configmap: %w", err)
+	}
+
+	return nil
+}
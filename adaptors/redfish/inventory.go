@@ -0,0 +1,124 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/redfish/redfishclient"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+)
+
+// system is a Redfish system discovered via a BMC
+type system struct {
+	BMC    redfishclient.BMC
+	Client *redfishclient.Client
+	Info   redfishclient.System
+}
+
+// nodeId returns the identifier of the system, used as the hardware manager node ID
+func (s *system) nodeId() string {
+	return s.BMC.Name + "/" + s.Info.Id
+}
+
+// bmcAddress returns the address of the system in the form expected by the O-Cloud Manager for BMC access, such as
+// redfish+https://192.168.1.10/redfish/v1/Systems/1
+func (s *system) bmcAddress() (string, error) {
+	address, err := url.Parse(s.BMC.Address)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse address of BMC %s: %w", s.BMC.Name, err)
+	}
+
+	return fmt.Sprintf("redfish+%s://%s%s", address.Scheme, address.Host, s.Info.ODataID), nil
+}
+
+// inventory holds the systems discovered via the BMCs of a HardwareManager
+type inventory struct {
+	Systems []*system
+}
+
+// discoverSystems queries each BMC configured in the HardwareManager CR for the systems it manages
+func (a *Adaptor) discoverSystems(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) (*inventory, error) {
+	bmcs, clients, err := redfishclient.GetClients(ctx, a.Client, hwmgr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup BMC clients: %w", err)
+	}
+
+	inv := &inventory{}
+	for _, bmc := range bmcs {
+		systems, err := clients[bmc.Name].GetSystems(ctx)
+		if err != nil {
+			// The BMC may be temporarily unreachable
+			return nil, utils.NewTransientError("failed to discover systems of BMC %s: %w", bmc.Name, err)
+		}
+
+		for _, info := range systems {
+			inv.Systems = append(inv.Systems, &system{
+				BMC:    bmc,
+				Client: clients[bmc.Name],
+				Info:   info,
+			})
+		}
+	}
+
+	return inv, nil
+}
+
+// find returns the system with the specified node ID
+func (inv *inventory) find(nodeId string) *system {
+	for _, s := range inv.Systems {
+		if s.nodeId() == nodeId {
+			return s
+		}
+	}
+	return nil
+}
+
+// getFreeSystemsInPool returns the systems in the resource pool that are not allocated
+func (inv *inventory) getFreeSystemsInPool(allocs *allocations, resourcePoolId string) []*system {
+	var free []*system
+	for _, s := range inv.Systems {
+		if s.BMC.ResourcePoolId == resourcePoolId && !allocs.isAllocated(s.nodeId()) {
+			free = append(free, s)
+		}
+	}
+	return free
+}
+
+// getFirmwareVersions queries the firmware inventory of the BMC managing the system, formatted as a list of
+// name=version pairs
+func getFirmwareVersions(ctx context.Context, s *system) (string, error) {
+	components, err := s.Client.GetFirmwareInventory(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get firmware inventory of BMC %s: %w", s.BMC.Name, err)
+	}
+
+	versions := make([]string, 0, len(components))
+	for _, component := range components {
+		name := component.Name
+		if name == "" {
+			name = component.Id
+		}
+		versions = append(versions, fmt.Sprintf("%s=%s", name, component.Version))
+	}
+
+	return strings.Join(versions, ","), nil
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/redfish/redfishclient"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+)
+
+// Annotations recording the firmware discovered for a node
+const (
+	BiosVersionAnnotation = "redfish.hwmgr-plugin.oran.openshift.io/bios-version"
+	FirmwareAnnotation    = "redfish.hwmgr-plugin.oran.openshift.io/firmware"
+)
+
+// Label of the interface used to boot the node, as expected by the O-Cloud Manager
+const bootableInterfaceLabel = "bootable-interface"
+
+// AllocateNode creates the Node CR and BMC secret for an allocated system, applying the BIOS attributes of the
+// hardware profile. The settings take effect when the system is next booted.
+func (a *Adaptor) AllocateNode(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	s *system,
+	groupname, hwprofile string,
+	profile *hwProfile) error {
+
+	nodename := utils.GenerateNodeName()
+	nodeId := s.nodeId()
+
+	if len(profile.BiosAttributes) > 0 {
+		a.Logger.InfoContext(ctx, "Applying BIOS settings", slog.String("nodeId", nodeId), slog.String("hwProfile", hwprofile))
+		if err := s.Client.UpdateBiosSettings(ctx, &s.Info, profile.BiosAttributes); err != nil {
+			return fmt.Errorf("failed to apply BIOS settings when allocating node %s, nodeId %s: %w", nodename, nodeId, err)
+		}
+	}
+
+	interfaces, err := a.getInterfaces(ctx, s, profile)
+	if err != nil {
+		return fmt.Errorf("failed to get interfaces when allocating node %s, nodeId %s: %w", nodename, nodeId, err)
+	}
+
+	firmware, err := getFirmwareVersions(ctx, s)
+	if err != nil {
+		// The firmware inventory is informational, so don't fail the allocation
+		a.Logger.InfoContext(ctx, "Unable to get firmware inventory", slog.String("nodeId", nodeId), slog.String("error", err.Error()))
+	}
+
+	bmcAddress, err := s.bmcAddress()
+	if err != nil {
+		return err
+	}
+
+	if err := a.CreateBMCSecret(ctx, nodepool, nodename, s.BMC.Username, s.BMC.Password); err != nil {
+		return fmt.Errorf("failed to create bmc-secret when allocating node %s, nodeId %s: %w", nodename, nodeId, err)
+	}
+
+	annotations := map[string]string{
+		BiosVersionAnnotation: s.Info.BiosVersion,
+		FirmwareAnnotation:    firmware,
+	}
+	if err := a.CreateNode(ctx, nodepool, nodename, nodeId, groupname, hwprofile, annotations); err != nil {
+		return fmt.Errorf("failed to create allocated node (%s): %w", nodename, err)
+	}
+
+	if err := a.UpdateNodeStatus(ctx, nodename, bmcAddress, interfaces, hwprofile); err != nil {
+		return fmt.Errorf("failed to update node status (%s): %w", nodename, err)
+	}
+
+	return nil
+}
+
+// getInterfaces builds the node interface list from the EthernetInterfaces of the system, labelling the interface
+// used to boot the system
+func (a *Adaptor) getInterfaces(ctx context.Context, s *system, profile *hwProfile) ([]*hwmgmtv1alpha1.Interface, error) {
+	nics, err := s.Client.GetEthernetInterfaces(ctx, &s.Info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ethernet interfaces of system %s: %w", s.nodeId(), err)
+	}
+
+	var interfaces []*hwmgmtv1alpha1.Interface
+	for i, nic := range nics {
+		iface := &hwmgmtv1alpha1.Interface{
+			Name:       nic.Id,
+			MACAddress: nic.MACAddress,
+		}
+		if (profile.BootInterface == "" && i == 0) || profile.BootInterface == nic.Id {
+			iface.Label = bootableInterfaceLabel
+		}
+		interfaces = append(interfaces, iface)
+	}
+
+	return interfaces, nil
+}
+
+func bmcSecretName(nodename string) string {
+	return fmt.Sprintf("%s-bmc-secret", nodename)
+}
+
+// CreateBMCSecret creates the bmc-secret for a node
+func (a *Adaptor) CreateBMCSecret(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, nodename, username, password string) error {
+	a.Logger.InfoContext(ctx, "Creating bmc-secret:", slog.String("nodename", nodename))
+
+	blockDeletion := true
+	bmcSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bmcSecretName(nodename),
+			Namespace: a.Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         nodepool.APIVersion,
+				Kind:               nodepool.Kind,
+				Name:               nodepool.Name,
+				UID:                nodepool.UID,
+				BlockOwnerDeletion: &blockDeletion,
+			}},
+		},
+		Data: map[string][]byte{
+			"username": []byte(username),
+			"password": []byte(password),
+		},
+	}
+
+	if err := utils.CreateOrUpdateK8sCR(ctx, a.Client, bmcSecret, nil, utils.UPDATE); err != nil {
+		return fmt.Errorf("failed to create bmc-secret for node %s: %w", nodename, err)
+	}
+
+	return nil
+}
+
+// CreateNode creates a Node CR with specified attributes
+func (a *Adaptor) CreateNode(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	nodename, nodeId, groupname, hwprofile string,
+	annotations map[string]string) error {
+
+	a.Logger.InfoContext(ctx, "Creating node",
+		slog.String("nodegroup name", groupname),
+		slog.String("nodename", nodename),
+		slog.String("nodeId", nodeId))
+
+	blockDeletion := true
+	node := &hwmgmtv1alpha1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        nodename,
+			Namespace:   a.Namespace,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         nodepool.APIVersion,
+				Kind:               nodepool.Kind,
+				Name:               nodepool.Name,
+				UID:                nodepool.UID,
+				BlockOwnerDeletion: &blockDeletion,
+			}},
+		},
+		Spec: hwmgmtv1alpha1.NodeSpec{
+			NodePool:    nodepool.Name,
+			GroupName:   groupname,
+			HwProfile:   hwprofile,
+			HwMgrId:     nodepool.Spec.HwMgrId,
+			HwMgrNodeId: nodeId,
+		},
+	}
+
+	if err := a.Client.Create(ctx, node); err != nil {
+		return fmt.Errorf("failed to create Node: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateNodeStatus updates a Node CR status field with the BMC and interface information discovered via Redfish
+func (a *Adaptor) UpdateNodeStatus(
+	ctx context.Context,
+	nodename, bmcAddress string,
+	interfaces []*hwmgmtv1alpha1.Interface,
+	hwprofile string) error {
+
+	a.Logger.InfoContext(ctx, "Updating node", slog.String("nodename", nodename))
+
+	node := &hwmgmtv1alpha1.Node{}
+
+	if err := utils.RetryOnConflictOrRetriableOrNotFound(retry.DefaultRetry, func() error {
+		return a.Get(ctx, types.NamespacedName{Name: nodename, Namespace: a.Namespace}, node)
+	}); err != nil {
+		return fmt.Errorf("failed to get Node for update: %w", err)
+	}
+
+	node.Status.BMC = &hwmgmtv1alpha1.BMC{
+		Address:         bmcAddress,
+		CredentialsName: bmcSecretName(nodename),
+	}
+	node.Status.Interfaces = interfaces
+
+	utils.SetStatusCondition(&node.Status.Conditions,
+		string(hwmgmtv1alpha1.Provisioned),
+		string(hwmgmtv1alpha1.Completed),
+		metav1.ConditionTrue,
+		"Provisioned")
+	node.Status.HwProfile = hwprofile
+	if err := utils.UpdateK8sCRStatus(ctx, a.Client, node); err != nil {
+		return fmt.Errorf("failed to update status for node %s: %w", nodename, err)
+	}
+
+	return nil
+}
+
+// GetAllocatedNodes gets the names of the Node CRs allocated to the NodePool
+func (a *Adaptor) GetAllocatedNodes(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) ([]string, error) {
+	nodelist, err := utils.GetChildNodes(ctx, a.Logger, a.Client, nodepool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get child nodes for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	var nodenames []string
+	for _, node := range nodelist.Items {
+		nodenames = append(nodenames, node.Name)
+	}
+
+	return nodenames, nil
+}
+
+// StartProfileUpdate applies the BIOS attributes of the new hardware profile to the system of a node, restarting the
+// system if it is powered on so that the settings take effect
+func (a *Adaptor) StartProfileUpdate(ctx context.Context, s *system, profile *hwProfile) error {
+	if len(profile.BiosAttributes) == 0 {
+		return nil
+	}
+
+	if err := s.Client.UpdateBiosSettings(ctx, &s.Info, profile.BiosAttributes); err != nil {
+		return fmt.Errorf("failed to apply BIOS settings to system %s: %w", s.nodeId(), err)
+	}
+
+	if s.Info.PowerState == redfishclient.PowerStateOn {
+		if err := s.Client.ResetSystem(ctx, &s.Info, redfishclient.ResetTypeGracefulRestart); err != nil {
+			return fmt.Errorf("failed to restart system %s: %w", s.nodeId(), err)
+		}
+	}
+
+	return nil
+}
+
+// IsProfileApplied checks whether the current BIOS attributes of the system match the hardware profile
+func (a *Adaptor) IsProfileApplied(ctx context.Context, s *system, profile *hwProfile) (bool, error) {
+	if len(profile.BiosAttributes) == 0 {
+		return true, nil
+	}
+
+	bios, err := s.Client.GetBios(ctx, &s.Info)
+	if err != nil {
+		return false, fmt.Errorf("failed to get BIOS attributes of system %s: %w", s.nodeId(), err)
+	}
+
+	return biosAttributesMatch(bios.Attributes, profile.BiosAttributes), nil
+}
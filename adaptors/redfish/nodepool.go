@@ -0,0 +1,382 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getNodeGroup finds the nodegroup with the given name in the NodePool
+func getNodeGroup(nodepool *hwmgmtv1alpha1.NodePool, groupname string) *hwmgmtv1alpha1.NodeGroup {
+	for i := range nodepool.Spec.NodeGroup {
+		if nodepool.Spec.NodeGroup[i].NodePoolData.Name == groupname {
+			return &nodepool.Spec.NodeGroup[i]
+		}
+	}
+	return nil
+}
+
+// ClaimSystems records allocations of free systems for each nodegroup of the NodePool, as needed. The allocations
+// configmap is updated with its resourceVersion, so a concurrent claim by another NodePool results in a conflict.
+func (a *Adaptor) ClaimSystems(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	inv *inventory) error {
+
+	cm, allocs, err := a.getAllocations(ctx, hwmgr)
+	if err != nil {
+		return fmt.Errorf("unable to get allocations: %w", err)
+	}
+
+	updated := false
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		remaining := nodegroup.Size - allocs.count(nodepool.Name, nodegroup.NodePoolData.Name)
+		if remaining <= 0 {
+			// This group is allocated
+			a.Logger.InfoContext(ctx, "nodegroup is fully allocated", slog.String("nodegroup", nodegroup.NodePoolData.Name))
+			continue
+		}
+
+		freesystems := inv.getFreeSystemsInPool(allocs, nodegroup.NodePoolData.ResourcePoolId)
+		if remaining > len(freesystems) {
+			return fmt.Errorf("not enough free resources remaining in resource pool %s", nodegroup.NodePoolData.ResourcePoolId)
+		}
+
+		for _, s := range freesystems[:remaining] {
+			a.Logger.InfoContext(ctx, "Claiming system",
+				slog.String("nodegroup name", nodegroup.NodePoolData.Name),
+				slog.String("nodeId", s.nodeId()))
+
+			allocs.Systems = append(allocs.Systems, allocatedSystem{
+				NodeId:    s.nodeId(),
+				NodePool:  nodepool.Name,
+				NodeGroup: nodegroup.NodePoolData.Name,
+			})
+			updated = true
+		}
+	}
+
+	if updated {
+		if err := a.updateAllocations(ctx, cm, allocs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AllocateNodes creates the Node CRs for systems allocated to the NodePool that do not yet have one
+func (a *Adaptor) AllocateNodes(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	inv *inventory) (int, error) {
+
+	_, allocs, err := a.getAllocations(ctx, hwmgr)
+	if err != nil {
+		return 0, fmt.Errorf("unable to get allocations: %w", err)
+	}
+
+	nodelist, err := utils.GetChildNodes(ctx, a.Logger, a.Client, nodepool)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get child nodes for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	allocated := allocs.forNodePool(nodepool.Name)
+	for _, alloc := range allocated {
+		if utils.FindNodeInList(*nodelist, nodepool.Spec.HwMgrId, alloc.NodeId) != "" {
+			continue
+		}
+
+		nodegroup := getNodeGroup(nodepool, alloc.NodeGroup)
+		if nodegroup == nil {
+			return 0, fmt.Errorf("nodegroup %s not found in NodePool %s", alloc.NodeGroup, nodepool.Name)
+		}
+
+		s := inv.find(alloc.NodeId)
+		if s == nil {
+			return 0, fmt.Errorf("allocated system %s not found in inventory", alloc.NodeId)
+		}
+
+		profile, err := a.getHwProfile(ctx, hwmgr, nodegroup.NodePoolData.HwProfile)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get hardware profile: %w", err)
+		}
+
+		if err := a.AllocateNode(ctx, nodepool, s, alloc.NodeGroup, nodegroup.NodePoolData.HwProfile, profile); err != nil {
+			return 0, fmt.Errorf("failed to allocate node for system %s: %w", alloc.NodeId, err)
+		}
+	}
+
+	return len(allocated), nil
+}
+
+// CheckNodePoolProgress checks to see if a NodePool is fully allocated, claiming systems and creating nodes as needed
+func (a *Adaptor) CheckNodePoolProgress(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (full bool, err error) {
+
+	inv, err := a.discoverSystems(ctx, hwmgr)
+	if err != nil {
+		err = fmt.Errorf("failed to discover systems: %w", err)
+		return
+	}
+
+	if err = a.ClaimSystems(ctx, hwmgr, nodepool, inv); err != nil {
+		err = fmt.Errorf("failed to claim systems: %w", err)
+		return
+	}
+
+	allocated, err := a.AllocateNodes(ctx, hwmgr, nodepool, inv)
+	if err != nil {
+		err = fmt.Errorf("failed to allocate nodes: %w", err)
+		return
+	}
+
+	full = allocated >= utils.GetNodePoolSize(nodepool)
+
+	return
+}
+
+// HandleNodePoolCreate processes a new NodePool CR
+func (a *Adaptor) HandleNodePoolCreate(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	return utils.HandleAllocationCreate(ctx, a.Client, a.Logger, a, hwmgr, nodepool)
+}
+
+// HandleNodePoolProcessing checks the progress of an in-progress NodePool
+func (a *Adaptor) HandleNodePoolProcessing(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	return utils.HandleAllocationProcessing(ctx, a.Client, a.Logger, a, hwmgr, nodepool)
+}
+
+// ProcessNewNodePool processes a new NodePool CR, verifying that there are enough free systems to satisfy the
+// request and that the requested hardware profiles are defined
+func (a *Adaptor) ProcessNewNodePool(ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) error {
+
+	a.Logger.InfoContext(ctx, "Processing ProcessNewNodePool request:",
+		slog.String("cloudID", nodepool.Spec.CloudID),
+	)
+
+	inv, err := a.discoverSystems(ctx, hwmgr)
+	if err != nil {
+		return fmt.Errorf("failed to discover systems: %w", err)
+	}
+
+	_, allocs, err := a.getAllocations(ctx, hwmgr)
+	if err != nil {
+		return fmt.Errorf("unable to get allocations: %w", err)
+	}
+
+	return utils.CheckFreeResources(nodepool, func(nodegroup hwmgmtv1alpha1.NodeGroup) (int, error) {
+		if _, err := a.getHwProfile(ctx, hwmgr, nodegroup.NodePoolData.HwProfile); err != nil {
+			return 0, fmt.Errorf("invalid hardware profile for nodegroup %s: %w", nodegroup.NodePoolData.Name, err)
+		}
+		return len(inv.getFreeSystemsInPool(allocs, nodegroup.NodePoolData.ResourcePoolId)), nil
+	})
+}
+
+// ReleaseNodePool frees the systems allocated to a NodePool
+func (a *Adaptor) ReleaseNodePool(ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) error {
+
+	a.Logger.InfoContext(ctx, "Processing ReleaseNodePool request:",
+		slog.String("cloudID", nodepool.Spec.CloudID),
+	)
+
+	cm, allocs, err := a.getAllocations(ctx, hwmgr)
+	if err != nil {
+		return fmt.Errorf("unable to get allocations: %w", err)
+	}
+
+	if !allocs.release(nodepool.Name) {
+		return nil
+	}
+
+	if err := a.updateAllocations(ctx, cm, allocs); err != nil {
+		return fmt.Errorf("failed to release systems: %w", err)
+	}
+
+	return nil
+}
+
+func (a *Adaptor) handleNodePoolConfiguring(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	var result ctrl.Result
+
+	a.Logger.InfoContext(ctx, "Handling Node Pool Configuring")
+
+	nodelist, err := utils.GetChildNodes(ctx, a.Logger, a.Client, nodepool)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get child nodes for Node Pool %s: %w", nodepool.Name, err)
+	}
+
+	inv, err := a.discoverSystems(ctx, hwmgr)
+	if err != nil {
+		return utils.RequeueWithShortInterval(), fmt.Errorf("failed to discover systems: %w", err)
+	}
+
+	a.Logger.InfoContext(ctx, "Checking for node with profile update in-progress")
+
+	// A node with a spec profile that differs from its status profile has an update in progress
+	for i := range nodelist.Items {
+		node := &nodelist.Items[i]
+		if node.Spec.HwProfile == node.Status.HwProfile {
+			continue
+		}
+
+		s := inv.find(node.Spec.HwMgrNodeId)
+		if s == nil {
+			return result, fmt.Errorf("system %s for node %s not found in inventory", node.Spec.HwMgrNodeId, node.Name)
+		}
+
+		profile, err := a.getHwProfile(ctx, hwmgr, node.Spec.HwProfile)
+		if err != nil {
+			return result, fmt.Errorf("failed to get hardware profile: %w", err)
+		}
+
+		applied, err := a.IsProfileApplied(ctx, s, profile)
+		if err != nil {
+			return utils.RequeueWithShortInterval(), fmt.Errorf("failed to check profile update of node %s: %w", node.Name, err)
+		}
+		if !applied {
+			return utils.RequeueWithShortInterval(), nil
+		}
+
+		// Node update is complete
+		a.Logger.InfoContext(ctx, "Node update complete", slog.String("nodename", node.Name))
+		node.Status.HwProfile = node.Spec.HwProfile
+		if err := utils.UpdateK8sCRStatus(ctx, a.Client, node); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update status for node %s: %w", node.Name, err)
+		}
+
+		return utils.RequeueImmediately(), nil
+	}
+
+	a.Logger.InfoContext(ctx, "Checking for nodes to update")
+
+	// There are no nodes currently in-progress, so we can look for the next one to start updating
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		newHwProfile := nodegroup.NodePoolData.HwProfile
+		node := utils.FindNextNodeToUpdate(nodelist, nodegroup.NodePoolData.Name, newHwProfile)
+		if node == nil {
+			// No more nodes to update in this nodegroup
+			continue
+		}
+
+		a.Logger.InfoContext(ctx, "Issuing profile update to node",
+			slog.String("hwMgrNodeId", node.Spec.HwMgrNodeId),
+			slog.String("curHwProfile", node.Spec.HwProfile),
+			slog.String("newHwProfile", newHwProfile))
+
+		s := inv.find(node.Spec.HwMgrNodeId)
+		if s == nil {
+			return result, fmt.Errorf("system %s for node %s not found in inventory", node.Spec.HwMgrNodeId, node.Name)
+		}
+
+		profile, err := a.getHwProfile(ctx, hwmgr, newHwProfile)
+		if err == nil {
+			err = a.StartProfileUpdate(ctx, s, profile)
+		}
+		if err != nil {
+			a.Logger.InfoContext(ctx, "Profile update failed", slog.String("error", err.Error()))
+			if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+				hwmgmtv1alpha1.Configured,
+				hwmgmtv1alpha1.Failed,
+				metav1.ConditionFalse,
+				fmt.Sprintf("Profile update failed: %s", err.Error())); err != nil {
+				return utils.RequeueWithMediumInterval(),
+					fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+			}
+			return result, fmt.Errorf("failed to update profile for node %s: %w", node.Name, err)
+		}
+
+		a.Logger.InfoContext(ctx, "Updating Node CR with new profile",
+			slog.String("nodename", node.Name),
+			slog.String("newHwProfile", newHwProfile),
+		)
+
+		// Copy the current node object for patching
+		patch := client.MergeFrom(node.DeepCopy())
+
+		// Set the new profile in the spec
+		node.Spec.HwProfile = newHwProfile
+
+		if err = a.Client.Patch(ctx, node, patch); err != nil {
+			return utils.RequeueWithShortInterval(), fmt.Errorf("failed to patch Node %s in namespace %s: %w", node.Name, node.Namespace, err)
+		}
+
+		// Requeue to check update progress
+		return utils.RequeueWithMediumInterval(), nil
+	}
+
+	// All nodes have been updated
+	a.Logger.InfoContext(ctx, "All nodes have been updated to new profile")
+	if err := utils.UpdateNodePoolStatusCondition(ctx, a.Client, nodepool,
+		hwmgmtv1alpha1.Configured, hwmgmtv1alpha1.ConfigApplied, metav1.ConditionTrue, string(hwmgmtv1alpha1.ConfigSuccess)); err != nil {
+		return utils.RequeueWithShortInterval(), fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+	// Update the Node Pool hwMgrPlugin status
+	if err = utils.UpdateNodePoolPluginStatus(ctx, a.Client, nodepool); err != nil {
+		return utils.RequeueWithShortInterval(), fmt.Errorf("failed to update hwMgrPlugin observedGeneration Status: %w", err)
+	}
+
+	return result, nil
+}
+
+func (a *Adaptor) HandleNodePoolSpecChanged(
+	ctx context.Context,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	if err := utils.UpdateNodePoolStatusCondition(
+		ctx,
+		a.Client,
+		nodepool,
+		hwmgmtv1alpha1.Configured,
+		hwmgmtv1alpha1.ConfigUpdate,
+		metav1.ConditionFalse,
+		string(hwmgmtv1alpha1.AwaitConfig)); err != nil {
+		return utils.RequeueWithMediumInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	return a.handleNodePoolConfiguring(ctx, hwmgr, nodepool)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+)
+
+// hwProfile defines the settings applied to a system for a hardware profile
+type hwProfile struct {
+	// BiosAttributes are written to the BIOS settings of the system
+	BiosAttributes map[string]interface{} `json:"biosAttributes,omitempty"`
+
+	// BootInterface is the ID of the Redfish EthernetInterface used to boot the system. If not set, the first
+	// interface is used.
+	BootInterface string `json:"bootInterface,omitempty"`
+}
+
+// getHwProfile reads the definition of a hardware profile from the hwProfilesConfigMap. If no configmap is
+// configured, hardware profiles apply no settings.
+func (a *Adaptor) getHwProfile(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, name string) (*hwProfile, error) {
+	profile := &hwProfile{}
+
+	if hwmgr.Spec.RedfishData.HwProfilesConfigMap == nil {
+		return profile, nil
+	}
+
+	cmName := *hwmgr.Spec.RedfishData.HwProfilesConfigMap
+	cm, err := utils.GetConfigmap(ctx, a.Client, cmName, hwmgr.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configmap: %w", err)
+	}
+
+	data, exists := cm.Data[name]
+	if !exists {
+		return nil, fmt.Errorf("hardware profile %s not defined in configmap %s", name, cmName)
+	}
+
+	if err := yaml.Unmarshal([]byte(data), profile); err != nil {
+		return nil, fmt.Errorf("failed to parse hardware profile %s from configmap %s: %w", name, cmName, err)
+	}
+
+	return profile, nil
+}
+
+// biosAttributesMatch checks whether the current BIOS attributes of a system include the profile attributes. Values
+// are compared in their string form, as numbers may be decoded as different types.
+func biosAttributesMatch(current, wanted map[string]interface{}) bool {
+	for key, value := range wanted {
+		currentValue, exists := current[key]
+		if !exists || fmt.Sprint(currentValue) != fmt.Sprint(value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfishclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+)
+
+// InventoryKey is the key of the BMC list in the inventory secret
+const InventoryKey = "inventory"
+
+// BMC is a BMC endpoint managed by the redfish adaptor, with its resolved credentials
+type BMC struct {
+	Name           string `json:"name"`
+	Address        string `json:"address"`
+	ResourcePoolId string `json:"resourcePoolId"`
+	Username       string `json:"username"`
+	Password       string `json:"password"`
+}
+
+// ValidateBMCName checks that a BMC name can be used to form a hardware manager node ID
+func ValidateBMCName(name string) error {
+	if name == "" {
+		return fmt.Errorf("BMC name must not be empty")
+	}
+	if strings.Contains(name, "/") {
+		return fmt.Errorf("BMC name %s must not contain '/'", name)
+	}
+	return nil
+}
+
+// getCredentials reads the username and password from a basic-auth secret
+func getCredentials(ctx context.Context, rtclient client.Client, name, namespace string) (string, string, error) {
+	secret, err := utils.GetSecret(ctx, rtclient, name, namespace)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	username, err := utils.GetSecretField(secret, corev1.BasicAuthUsernameKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get %s from secret: %s, %w", corev1.BasicAuthUsernameKey, name, err)
+	}
+
+	password, err := utils.GetSecretField(secret, corev1.BasicAuthPasswordKey)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get %s from secret: %s, %w", corev1.BasicAuthPasswordKey, name, err)
+	}
+
+	return username, password, nil
+}
+
+// GetBMCs resolves the BMC endpoints configured in the HardwareManager CR, from both the bmcs list and the
+// inventory secret
+func GetBMCs(ctx context.Context, rtclient client.Client, hwmgr *pluginv1alpha1.HardwareManager) ([]BMC, error) {
+	config := hwmgr.Spec.RedfishData
+	if config == nil {
+		return nil, fmt.Errorf("required config data missing from HardwareManager: name=%s", hwmgr.Name)
+	}
+
+	var bmcs []BMC
+
+	for _, entry := range config.Bmcs {
+		authSecret := config.AuthSecret
+		if entry.AuthSecret != nil {
+			authSecret = entry.AuthSecret
+		}
+		if authSecret == nil {
+			return nil, fmt.Errorf("no authSecret configured for BMC %s", entry.Name)
+		}
+
+		username, password, err := getCredentials(ctx, rtclient, *authSecret, hwmgr.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credentials for BMC %s: %w", entry.Name, err)
+		}
+
+		bmcs = append(bmcs, BMC{
+			Name:           entry.Name,
+			Address:        entry.Address,
+			ResourcePoolId: entry.ResourcePoolId,
+			Username:       username,
+			Password:       password,
+		})
+	}
+
+	if config.InventorySecret != nil {
+		secret, err := utils.GetSecret(ctx, rtclient, *config.InventorySecret, hwmgr.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get inventory secret: %w", err)
+		}
+
+		inventory, err := utils.GetSecretField(secret, InventoryKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s from secret: %s, %w", InventoryKey, *config.InventorySecret, err)
+		}
+
+		var entries []BMC
+		if err := yaml.Unmarshal([]byte(inventory), &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse %s from secret: %s, %w", InventoryKey, *config.InventorySecret, err)
+		}
+		bmcs = append(bmcs, entries...)
+	}
+
+	names := make(map[string]bool)
+	for _, bmc := range bmcs {
+		if err := ValidateBMCName(bmc.Name); err != nil {
+			return nil, err
+		}
+		if names[bmc.Name] {
+			return nil, fmt.Errorf("duplicate BMC name %s", bmc.Name)
+		}
+		names[bmc.Name] = true
+	}
+
+	return bmcs, nil
+}
+
+// GetTransport builds the HTTP transport for communicating with the BMCs from the redfishData configuration
+func GetTransport(ctx context.Context, rtclient client.Client, hwmgr *pluginv1alpha1.HardwareManager) (http.RoundTripper, error) {
+	config := hwmgr.Spec.RedfishData
+
+	// If the HardwareManager CR includes certificates, get the bundle to add to the client
+	var caBundle string
+	if config.CaBundleName != nil {
+		cm, err := utils.GetConfigmap(ctx, rtclient, *config.CaBundleName, hwmgr.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configmap: %w", err)
+		}
+
+		caBundle, err = utils.GetConfigMapField(cm, "ca-bundle.pem")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get certificate bundle from configmap: %w", err)
+		}
+	}
+
+	tr, err := utils.GetTransportWithCaBundle(utils.OAuthClientConfig{CaBundle: []byte(caBundle)},
+		config.InsecureSkipTLSVerify, utils.IsHardwareManagerLogMessagesEnabled(hwmgr))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get http transport: %w", err)
+	}
+
+	return tr, nil
+}
+
+// GetClients creates a client for each BMC configured in the HardwareManager CR, keyed by BMC name
+func GetClients(ctx context.Context, rtclient client.Client, hwmgr *pluginv1alpha1.HardwareManager) ([]BMC, map[string]*Client, error) {
+	bmcs, err := GetBMCs(ctx, rtclient, hwmgr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tr, err := GetTransport(ctx, rtclient, hwmgr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clients := make(map[string]*Client, len(bmcs))
+	for _, bmc := range bmcs {
+		clients[bmc.Name] = NewClient(bmc.Address, bmc.Username, bmc.Password, tr)
+	}
+
+	return bmcs, clients, nil
+}
+
+// CheckBMCs verifies that the Redfish service of each BMC configured in the HardwareManager CR is reachable
+func CheckBMCs(ctx context.Context, rtclient client.Client, hwmgr *pluginv1alpha1.HardwareManager) error {
+	bmcs, clients, err := GetClients(ctx, rtclient, hwmgr)
+	if err != nil {
+		return err
+	}
+
+	if len(bmcs) == 0 {
+		return fmt.Errorf("no BMCs configured")
+	}
+
+	var failed []string
+	for _, bmc := range bmcs {
+		if _, err := clients[bmc.Name].GetServiceRoot(ctx); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", bmc.Name, err.Error()))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to reach BMCs: %s", strings.Join(failed, "; "))
+	}
+
+	return nil
+}
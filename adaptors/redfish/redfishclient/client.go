@@ -0,0 +1,252 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfishclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// ServiceRootPath is the path of the Redfish service root
+	ServiceRootPath = "/redfish/v1"
+
+	// FirmwareInventoryPath is the path of the firmware inventory collection
+	FirmwareInventoryPath = "/redfish/v1/UpdateService/FirmwareInventory"
+
+	// RequestTimeout bounds the duration of a single request to a BMC
+	RequestTimeout = 30 * time.Second
+)
+
+// Client provides functions for calling the Redfish API of a BMC, authenticating with HTTP basic auth
+type Client struct {
+	address    string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the Redfish service at the address, such as https://192.168.1.10
+func NewClient(address, username, password string, transport http.RoundTripper) *Client {
+	return &Client{
+		address:    strings.TrimSuffix(address, "/"),
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Transport: transport, Timeout: RequestTimeout},
+	}
+}
+
+// Address returns the address of the Redfish service
+func (c *Client) Address() string {
+	return c.address
+}
+
+// do sends a request to the Redfish service, decoding the response body into out, if provided
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body for %s %s: %w", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.address+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request for %s %s: %w", method, path, err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request %s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response for %s %s: %w", method, path, err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("request %s %s failed with status %s, message=%s", method, path, resp.Status, string(data))
+	}
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to parse response for %s %s: %w", method, path, err)
+		}
+	}
+
+	return nil
+}
+
+// GetServiceRoot queries the Redfish service root
+func (c *Client) GetServiceRoot(ctx context.Context) (*ServiceRoot, error) {
+	root := &ServiceRoot{}
+	if err := c.do(ctx, http.MethodGet, ServiceRootPath, nil, root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// getCollection queries the members of a resource collection
+func (c *Client) getCollection(ctx context.Context, path string) ([]Link, error) {
+	collection := &Collection{}
+	if err := c.do(ctx, http.MethodGet, path, nil, collection); err != nil {
+		return nil, err
+	}
+	return collection.Members, nil
+}
+
+// GetSystems queries the systems managed by the BMC
+func (c *Client) GetSystems(ctx context.Context) ([]System, error) {
+	root, err := c.GetServiceRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	systemsPath := ServiceRootPath + "/Systems"
+	if root.Systems != nil && root.Systems.ODataID != "" {
+		systemsPath = root.Systems.ODataID
+	}
+
+	members, err := c.getCollection(ctx, systemsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	systems := make([]System, 0, len(members))
+	for _, member := range members {
+		system, err := c.GetSystem(ctx, member.ODataID)
+		if err != nil {
+			return nil, err
+		}
+		systems = append(systems, *system)
+	}
+
+	return systems, nil
+}
+
+// GetSystem queries a system by its resource path
+func (c *Client) GetSystem(ctx context.Context, path string) (*System, error) {
+	system := &System{}
+	if err := c.do(ctx, http.MethodGet, path, nil, system); err != nil {
+		return nil, err
+	}
+	if system.ODataID == "" {
+		system.ODataID = path
+	}
+	return system, nil
+}
+
+// GetEthernetInterfaces queries the network interfaces of a system
+func (c *Client) GetEthernetInterfaces(ctx context.Context, system *System) ([]EthernetInterface, error) {
+	path := system.ODataID + "/EthernetInterfaces"
+	if system.EthernetInterfaces != nil && system.EthernetInterfaces.ODataID != "" {
+		path = system.EthernetInterfaces.ODataID
+	}
+
+	members, err := c.getCollection(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	interfaces := make([]EthernetInterface, 0, len(members))
+	for _, member := range members {
+		iface := EthernetInterface{}
+		if err := c.do(ctx, http.MethodGet, member.ODataID, nil, &iface); err != nil {
+			return nil, err
+		}
+		interfaces = append(interfaces, iface)
+	}
+
+	return interfaces, nil
+}
+
+// GetFirmwareInventory queries the firmware components reported by the BMC
+func (c *Client) GetFirmwareInventory(ctx context.Context) ([]SoftwareInventory, error) {
+	members, err := c.getCollection(ctx, FirmwareInventoryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := make([]SoftwareInventory, 0, len(members))
+	for _, member := range members {
+		component := SoftwareInventory{}
+		if err := c.do(ctx, http.MethodGet, member.ODataID, nil, &component); err != nil {
+			return nil, err
+		}
+		inventory = append(inventory, component)
+	}
+
+	return inventory, nil
+}
+
+// biosPath returns the path of the BIOS resource of a system
+func biosPath(system *System) string {
+	if system.Bios != nil && system.Bios.ODataID != "" {
+		return system.Bios.ODataID
+	}
+	return system.ODataID + "/Bios"
+}
+
+// GetBios queries the current BIOS attributes of a system
+func (c *Client) GetBios(ctx context.Context, system *System) (*Bios, error) {
+	bios := &Bios{}
+	if err := c.do(ctx, http.MethodGet, biosPath(system), nil, bios); err != nil {
+		return nil, err
+	}
+	return bios, nil
+}
+
+// UpdateBiosSettings writes the BIOS attributes to the pending settings of a system. The settings are applied by
+// the BMC on the next reset of the system.
+func (c *Client) UpdateBiosSettings(ctx context.Context, system *System, attributes map[string]interface{}) error {
+	bios, err := c.GetBios(ctx, system)
+	if err != nil {
+		return err
+	}
+
+	settingsPath := biosPath(system) + "/Settings"
+	if bios.Settings != nil && bios.Settings.SettingsObject.ODataID != "" {
+		settingsPath = bios.Settings.SettingsObject.ODataID
+	}
+
+	return c.do(ctx, http.MethodPatch, settingsPath, BiosSettings{Attributes: attributes}, nil)
+}
+
+// ResetSystem resets a system with the specified reset type
+func (c *Client) ResetSystem(ctx context.Context, system *System, resetType string) error {
+	target := system.ODataID + "/Actions/ComputerSystem.Reset"
+	if system.Actions != nil && system.Actions.Reset != nil && system.Actions.Reset.Target != "" {
+		target = system.Actions.Reset.Target
+	}
+
+	return c.do(ctx, http.MethodPost, target, ResetRequest{ResetType: resetType}, nil)
+}
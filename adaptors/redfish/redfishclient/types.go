@@ -0,0 +1,120 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfishclient
+
+// The Redfish resources used by the adaptor. Only the properties read by the adaptor are defined. See the DMTF
+// Redfish schema for the full definitions.
+
+// Link is a reference to another Redfish resource
+type Link struct {
+	ODataID string `json:"@odata.id"`
+}
+
+// Collection is a Redfish resource collection
+type Collection struct {
+	ODataID string `json:"@odata.id,omitempty"`
+	Name    string `json:"Name,omitempty"`
+	Members []Link `json:"Members"`
+	Count   int    `json:"Members@odata.count"`
+}
+
+// ServiceRoot is the root of the Redfish service
+type ServiceRoot struct {
+	ODataID        string `json:"@odata.id,omitempty"`
+	Id             string `json:"Id,omitempty"`
+	Name           string `json:"Name,omitempty"`
+	RedfishVersion string `json:"RedfishVersion,omitempty"`
+	UUID           string `json:"UUID,omitempty"`
+	Systems        *Link  `json:"Systems,omitempty"`
+	UpdateService  *Link  `json:"UpdateService,omitempty"`
+}
+
+// ResetAction is the ComputerSystem.Reset action of a system
+type ResetAction struct {
+	Target string `json:"target"`
+}
+
+// SystemActions are the actions supported by a system
+type SystemActions struct {
+	Reset *ResetAction `json:"#ComputerSystem.Reset,omitempty"`
+}
+
+// System is a Redfish ComputerSystem
+type System struct {
+	ODataID            string         `json:"@odata.id,omitempty"`
+	Id                 string         `json:"Id"`
+	Name               string         `json:"Name,omitempty"`
+	Manufacturer       string         `json:"Manufacturer,omitempty"`
+	Model              string         `json:"Model,omitempty"`
+	SerialNumber       string         `json:"SerialNumber,omitempty"`
+	BiosVersion        string         `json:"BiosVersion,omitempty"`
+	PowerState         string         `json:"PowerState,omitempty"`
+	Bios               *Link          `json:"Bios,omitempty"`
+	EthernetInterfaces *Link          `json:"EthernetInterfaces,omitempty"`
+	Actions            *SystemActions `json:"Actions,omitempty"`
+}
+
+// EthernetInterface is a network interface of a system
+type EthernetInterface struct {
+	ODataID    string `json:"@odata.id,omitempty"`
+	Id         string `json:"Id"`
+	Name       string `json:"Name,omitempty"`
+	MACAddress string `json:"MACAddress,omitempty"`
+}
+
+// SettingsObject references the resource to which pending settings are written
+type SettingsObject struct {
+	SettingsObject Link `json:"SettingsObject"`
+}
+
+// Bios is the BIOS resource of a system
+type Bios struct {
+	ODataID    string                 `json:"@odata.id,omitempty"`
+	Id         string                 `json:"Id,omitempty"`
+	Attributes map[string]interface{} `json:"Attributes"`
+	Settings   *SettingsObject        `json:"@Redfish.Settings,omitempty"`
+}
+
+// BiosSettings is the request body for updating the pending BIOS attributes
+type BiosSettings struct {
+	Attributes map[string]interface{} `json:"Attributes"`
+}
+
+// SoftwareInventory is a firmware component in the firmware inventory
+type SoftwareInventory struct {
+	ODataID string `json:"@odata.id,omitempty"`
+	Id      string `json:"Id"`
+	Name    string `json:"Name,omitempty"`
+	Version string `json:"Version,omitempty"`
+}
+
+// ResetRequest is the request body for the ComputerSystem.Reset action
+type ResetRequest struct {
+	ResetType string `json:"ResetType"`
+}
+
+// Reset types used by the adaptor
+const (
+	ResetTypeGracefulRestart = "GracefulRestart"
+	ResetTypeForceRestart    = "ForceRestart"
+)
+
+// Power states of a system
+const (
+	PowerStateOn  = "On"
+	PowerStateOff = "Off"
+)
@@ -0,0 +1,112 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+// NewAdaptorFunc creates an instance of an adaptor. Controller setup for the adaptor is done
+// by the SetupAdaptor function of the returned instance.
+type NewAdaptorFunc func(client client.Client, scheme *runtime.Scheme, logger *slog.Logger, namespace string) adaptorinterface.HwMgrAdaptorIntf
+
+// ValidateConfigFunc verifies that a HardwareManager CR carries the config data required by the adaptor
+type ValidateConfigFunc func(hwmgr *pluginv1alpha1.HardwareManager) error
+
+// Registration defines the data an adaptor provides when registering itself
+type Registration struct {
+	ID             pluginv1alpha1.HardwareManagerAdaptorID
+	NewAdaptor     NewAdaptorFunc
+	ValidateConfig ValidateConfigFunc
+}
+
+var (
+	lock          sync.RWMutex
+	registrations = make(map[pluginv1alpha1.HardwareManagerAdaptorID]Registration)
+)
+
+// Register adds an adaptor to the registry. It is intended to be called from the init function of the
+// adaptor package, and panics on an invalid or duplicate registration.
+func Register(reg Registration) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if reg.ID == "" {
+		panic("adaptor registration missing ID")
+	}
+
+	if reg.NewAdaptor == nil {
+		panic(fmt.Sprintf("adaptor registration missing constructor: %s", reg.ID))
+	}
+
+	if _, exists := registrations[reg.ID]; exists {
+		panic(fmt.Sprintf("adaptor already registered: %s", reg.ID))
+	}
+
+	registrations[reg.ID] = reg
+}
+
+// Lookup returns the registration for the specified adaptor ID
+func Lookup(id pluginv1alpha1.HardwareManagerAdaptorID) (Registration, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	reg, exists := registrations[id]
+	return reg, exists
+}
+
+// IDs returns the sorted list of registered adaptor IDs
+func IDs() []pluginv1alpha1.HardwareManagerAdaptorID {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	ids := make([]pluginv1alpha1.HardwareManagerAdaptorID, 0, len(registrations))
+	for id := range registrations {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	return ids
+}
+
+// ValidateConfig verifies that the HardwareManager CR references a registered adaptor and carries the
+// config data that adaptor requires
+func ValidateConfig(hwmgr *pluginv1alpha1.HardwareManager) error {
+	reg, exists := Lookup(hwmgr.Spec.AdaptorID)
+	if !exists {
+		return fmt.Errorf("unsupported adaptorId (%s) HardwareManager: name=%s", hwmgr.Spec.AdaptorID, hwmgr.Name)
+	}
+
+	if reg.ValidateConfig == nil {
+		return nil
+	}
+
+	if err := reg.ValidateConfig(hwmgr); err != nil {
+		return fmt.Errorf("invalid config data in HardwareManager: name=%s: %w", hwmgr.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+func TestRegistry(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Adaptor Registry Suite")
+}
+
+func newTestAdaptor(client client.Client, scheme *runtime.Scheme, logger *slog.Logger, namespace string) adaptorinterface.HwMgrAdaptorIntf {
+	return nil
+}
+
+var _ = Describe("Adaptor registry", func() {
+	const testID = pluginv1alpha1.HardwareManagerAdaptorID("registry-test")
+
+	BeforeEach(func() {
+		lock.Lock()
+		delete(registrations, testID)
+		lock.Unlock()
+	})
+
+	It("registers and looks up an adaptor", func() {
+		Register(Registration{ID: testID, NewAdaptor: newTestAdaptor})
+
+		reg, exists := Lookup(testID)
+		Expect(exists).To(BeTrue())
+		Expect(reg.ID).To(Equal(testID))
+		Expect(IDs()).To(ContainElement(testID))
+	})
+
+	It("rejects invalid and duplicate registrations", func() {
+		Expect(func() { Register(Registration{NewAdaptor: newTestAdaptor}) }).To(Panic())
+		Expect(func() { Register(Registration{ID: testID}) }).To(Panic())
+
+		Register(Registration{ID: testID, NewAdaptor: newTestAdaptor})
+		Expect(func() { Register(Registration{ID: testID, NewAdaptor: newTestAdaptor}) }).To(Panic())
+	})
+
+	It("validates the HardwareManager config data", func() {
+		Register(Registration{
+			ID:         testID,
+			NewAdaptor: newTestAdaptor,
+			ValidateConfig: func(hwmgr *pluginv1alpha1.HardwareManager) error {
+				if hwmgr.Spec.LoopbackData == nil {
+					return errors.New("missing config data")
+				}
+				return nil
+			},
+		})
+
+		hwmgr := &pluginv1alpha1.HardwareManager{}
+		hwmgr.Spec.AdaptorID = testID
+		Expect(ValidateConfig(hwmgr)).NotTo(Succeed())
+
+		hwmgr.Spec.LoopbackData = &pluginv1alpha1.LoopbackData{}
+		Expect(ValidateConfig(hwmgr)).To(Succeed())
+
+		hwmgr.Spec.AdaptorID = "unknown"
+		Expect(ValidateConfig(hwmgr)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,213 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+const (
+	// MaxAutoRetries bounds the number of automatic retries of a NodePool that fails with a transient error
+	MaxAutoRetries = 3
+
+	// autoRetryInterval is the base interval before an automatic retry, doubled for each subsequent attempt
+	autoRetryInterval = 30 * time.Second
+)
+
+// retryTracker counts the consecutive automatic retries of each NodePool. The count is not persisted, as an automatic
+// retry is only triggered at the time of a failure. It is reset whenever the adaptor processes the NodePool without
+// error, and dropped when the NodePool is released.
+type retryTracker struct {
+	sync.Mutex
+	attempts map[types.UID]int
+}
+
+// next increments and returns the retry attempt for the NodePool
+func (t *retryTracker) next(uid types.UID) int {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.attempts == nil {
+		t.attempts = make(map[types.UID]int)
+	}
+	t.attempts[uid]++
+	return t.attempts[uid]
+}
+
+// reset clears the retry count for the NodePool
+func (t *retryTracker) reset(uid types.UID) {
+	t.Lock()
+	defer t.Unlock()
+
+	delete(t.attempts, uid)
+}
+
+// isFailedCondition checks whether a NodePool condition reports a failed or timed out operation
+func isFailedCondition(nodepool *hwmgmtv1alpha1.NodePool, conditionType hwmgmtv1alpha1.ConditionType) bool {
+	condition := meta.FindStatusCondition(nodepool.Status.Conditions, string(conditionType))
+	return condition != nil &&
+		(condition.Reason == string(hwmgmtv1alpha1.Failed) || condition.Reason == string(hwmgmtv1alpha1.TimedOut))
+}
+
+// getRetryToken returns the value of the retry annotation of the NodePool if it has not yet been handled
+func getRetryToken(nodepool *hwmgmtv1alpha1.NodePool) string {
+	annotations := nodepool.GetAnnotations()
+	token := annotations[utils.RetryAnnotation]
+	if token == annotations[utils.LastRetryAnnotation] {
+		return ""
+	}
+	return token
+}
+
+// restartNodePool resets a failed or timed out NodePool operation so that the adaptor processes it again, reconciling
+// any resources that were already allocated. A failed provisioning is resumed, while a failed configuration is
+// reapplied by marking the spec as unhandled. Returns true if an operation was restarted.
+func (c *HwMgrAdaptorController) restartNodePool(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, message string) (bool, error) {
+	switch {
+	case isFailedCondition(nodepool, hwmgmtv1alpha1.Provisioned):
+		c.Logger.InfoContext(ctx, "Restarting NodePool provisioning")
+		if err := utils.ResetNodePoolStatusCondition(ctx, c.Client, nodepool,
+			hwmgmtv1alpha1.Provisioned, hwmgmtv1alpha1.InProgress, metav1.ConditionFalse, message); err != nil {
+			return false, fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+		}
+	case utils.IsNodePoolProvisionedCompleted(nodepool) && isFailedCondition(nodepool, hwmgmtv1alpha1.Configured):
+		c.Logger.InfoContext(ctx, "Restarting NodePool configuration")
+		if err := utils.ResetNodePoolStatusCondition(ctx, c.Client, nodepool,
+			hwmgmtv1alpha1.Configured, hwmgmtv1alpha1.ConfigUpdate, metav1.ConditionFalse, message); err != nil {
+			return false, fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+		}
+
+		// Clear the observed generation, so that the adaptor processes the spec again
+		// nolint: wrapcheck
+		err := utils.RetryOnConflictOrRetriable(retry.DefaultRetry, func() error {
+			newNodepool := &hwmgmtv1alpha1.NodePool{}
+			if err := c.Client.Get(ctx, client.ObjectKeyFromObject(nodepool), newNodepool); err != nil {
+				return err
+			}
+			newNodepool.Status.HwMgrPlugin.ObservedGeneration = 0
+			return c.Client.Status().Update(ctx, newNodepool)
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to update plugin status for NodePool %s: %w", nodepool.Name, err)
+		}
+		nodepool.Status.HwMgrPlugin.ObservedGeneration = 0
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// checkNodePoolRetry handles a retry requested via the retry annotation of the NodePool, restarting a failed or timed
+// out operation. The token of the annotation is recorded so that the request is handled only once.
+func (c *HwMgrAdaptorController) checkNodePoolRetry(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) error {
+	token := getRetryToken(nodepool)
+	if token == "" {
+		return nil
+	}
+
+	c.Logger.InfoContext(ctx, "Handling NodePool retry request", slog.String("token", token))
+
+	patch := client.MergeFrom(nodepool.DeepCopy())
+	annotations := nodepool.GetAnnotations()
+	annotations[utils.LastRetryAnnotation] = token
+	nodepool.SetAnnotations(annotations)
+	if err := c.Client.Patch(ctx, nodepool, patch); err != nil {
+		return fmt.Errorf("failed to record retry token for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	c.retries.reset(nodepool.UID)
+
+	restarted, err := c.restartNodePool(ctx, nodepool, "Retry requested: "+token)
+	if err != nil {
+		return err
+	}
+	if !restarted {
+		c.Logger.InfoContext(ctx, "NodePool has no failed operation to retry")
+	}
+
+	return nil
+}
+
+// handleTransientFailure automatically retries a NodePool operation that failed with a transient error, as classified
+// by utils.IsTransientError, up to MaxAutoRetries consecutive times with an increasing interval, honouring any delay
+// requested by the failed service. Once the retries are exhausted, the operation is marked as failed, to be retried on
+// request. Returns the result for the reconcile.
+func (c *HwMgrAdaptorController) handleTransientFailure(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	failure error) (ctrl.Result, error) {
+
+	attempt := c.retries.next(nodepool.UID)
+	if attempt > MaxAutoRetries {
+		c.Logger.InfoContext(ctx, "NodePool automatic retries exhausted", slog.String("error", failure.Error()))
+		c.retries.reset(nodepool.UID)
+		if err := c.failNodePoolOperation(ctx, nodepool, "Automatic retries exhausted: "+failure.Error()); err != nil {
+			return utils.RequeueWithShortInterval(), err
+		}
+		return utils.DoNotRequeue(), nil
+	}
+
+	message := fmt.Sprintf("Retrying after transient failure (attempt %d of %d): %s", attempt, MaxAutoRetries, failure.Error())
+	// If the failure was not recorded in the conditions, the adaptor resumes processing on the requeue
+	if _, err := c.restartNodePool(ctx, nodepool, message); err != nil {
+		return utils.RequeueWithShortInterval(), err
+	}
+
+	c.Logger.InfoContext(ctx, "Automatically retrying NodePool", slog.Int("attempt", attempt), slog.String("error", failure.Error()))
+	interval := autoRetryInterval << (attempt - 1)
+	if requested := utils.GetRetryDelay(failure); requested > interval {
+		interval = requested
+	}
+	return utils.RequeueWithCustomInterval(interval), nil
+}
+
+// failNodePoolOperation marks the provisioning or configuration of the NodePool as failed, unless the failure has
+// already been recorded. The spec is marked as handled, so that the adaptor does not process it again until retried.
+func (c *HwMgrAdaptorController) failNodePoolOperation(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool, message string) error {
+	conditionType := hwmgmtv1alpha1.Provisioned
+	if utils.IsNodePoolProvisionedCompleted(nodepool) {
+		conditionType = hwmgmtv1alpha1.Configured
+	}
+	if isFailedCondition(nodepool, conditionType) {
+		return nil
+	}
+
+	if err := utils.UpdateNodePoolStatusCondition(ctx, c.Client, nodepool,
+		conditionType, hwmgmtv1alpha1.Failed, metav1.ConditionFalse, message); err != nil {
+		return fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+	if err := utils.UpdateNodePoolPluginStatus(ctx, c.Client, nodepool); err != nil {
+		return fmt.Errorf("failed to update hwMgrPlugin observedGeneration for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+var _ = Describe("NodePool retries", func() {
+	var nodepool *hwmgmtv1alpha1.NodePool
+
+	BeforeEach(func() {
+		nodepool = &hwmgmtv1alpha1.NodePool{}
+	})
+
+	It("handles each retry token once", func() {
+		Expect(getRetryToken(nodepool)).To(BeEmpty())
+
+		nodepool.SetAnnotations(map[string]string{utils.RetryAnnotation: "1"})
+		Expect(getRetryToken(nodepool)).To(Equal("1"))
+
+		nodepool.Annotations[utils.LastRetryAnnotation] = "1"
+		Expect(getRetryToken(nodepool)).To(BeEmpty())
+
+		nodepool.Annotations[utils.RetryAnnotation] = "2"
+		Expect(getRetryToken(nodepool)).To(Equal("2"))
+	})
+
+	It("identifies failed and timed out operations", func() {
+		Expect(isFailedCondition(nodepool, hwmgmtv1alpha1.Provisioned)).To(BeFalse())
+
+		nodepool.Status.Conditions = []metav1.Condition{{
+			Type:   string(hwmgmtv1alpha1.Provisioned),
+			Status: metav1.ConditionFalse,
+			Reason: string(hwmgmtv1alpha1.InProgress),
+		}}
+		Expect(isFailedCondition(nodepool, hwmgmtv1alpha1.Provisioned)).To(BeFalse())
+
+		nodepool.Status.Conditions[0].Reason = string(hwmgmtv1alpha1.Failed)
+		Expect(isFailedCondition(nodepool, hwmgmtv1alpha1.Provisioned)).To(BeTrue())
+
+		nodepool.Status.Conditions[0].Reason = string(hwmgmtv1alpha1.TimedOut)
+		Expect(isFailedCondition(nodepool, hwmgmtv1alpha1.Provisioned)).To(BeTrue())
+		Expect(isFailedCondition(nodepool, hwmgmtv1alpha1.Configured)).To(BeFalse())
+	})
+
+	It("identifies a NodePool that failed for a missing HardwareManager", func() {
+		Expect(isHwMgrNotFound(nodepool)).To(BeFalse())
+
+		nodepool.Status.Conditions = []metav1.Condition{{
+			Type:    string(hwmgmtv1alpha1.Provisioned),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(hwmgmtv1alpha1.Failed),
+			Message: "Creation request failed: not enough free resources",
+		}}
+		Expect(isHwMgrNotFound(nodepool)).To(BeFalse())
+
+		nodepool.Status.Conditions[0].Message = hwMgrNotFoundMessage + "dell-1"
+		Expect(isHwMgrNotFound(nodepool)).To(BeTrue())
+	})
+
+	It("counts automatic retries per NodePool", func() {
+		tracker := retryTracker{}
+		Expect(tracker.next(types.UID("a"))).To(Equal(1))
+		Expect(tracker.next(types.UID("a"))).To(Equal(2))
+		Expect(tracker.next(types.UID("b"))).To(Equal(1))
+
+		tracker.reset(types.UID("a"))
+		Expect(tracker.next(types.UID("a"))).To(Equal(1))
+	})
+
+	It("identifies transient errors", func() {
+		err := utils.NewTransientError("failed to discover systems: %w", errors.New("unavailable"))
+		Expect(utils.IsTransientError(err)).To(BeTrue())
+		Expect(utils.IsTransientError(errors.Join(errors.New("wrapped"), err))).To(BeTrue())
+		Expect(utils.IsTransientError(errors.New("unavailable"))).To(BeFalse())
+		Expect(utils.IsTransientError(nil)).To(BeFalse())
+
+		// An overloaded API server is transient for every adaptor, and its requested delay is honoured
+		throttled := apierrors.NewTooManyRequests("throttled", 20)
+		Expect(utils.IsTransientError(throttled)).To(BeTrue())
+		Expect(utils.GetRetryDelay(throttled)).To(Equal(20 * time.Second))
+		Expect(utils.IsTransientError(apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "a"))).To(BeFalse())
+	})
+
+	It("marks the operation failed once the automatic retries are exhausted", func() {
+		nodepool.Name = "np1"
+		nodepool.Namespace = "hwmgr"
+		nodepool.UID = types.UID("np1")
+		nodepool.Generation = 2
+		nodepool.Status.Conditions = []metav1.Condition{{
+			Type:   string(hwmgmtv1alpha1.Provisioned),
+			Status: metav1.ConditionFalse,
+			Reason: string(hwmgmtv1alpha1.InProgress),
+		}}
+
+		scheme := runtime.NewScheme()
+		Expect(hwmgmtv1alpha1.AddToScheme(scheme)).To(Succeed())
+		c := &HwMgrAdaptorController{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodepool).
+				WithStatusSubresource(nodepool).Build(),
+			Logger: slog.Default(),
+		}
+
+		failure := utils.NewTransientError("failed to discover systems: %w", errors.New("unreachable"))
+
+		ctx := context.Background()
+		for attempt := 1; attempt <= MaxAutoRetries; attempt++ {
+			result, err := c.handleTransientFailure(ctx, nodepool, failure)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(autoRetryInterval << (attempt - 1)))
+		}
+		Expect(isFailedCondition(nodepool, hwmgmtv1alpha1.Provisioned)).To(BeFalse())
+
+		result, err := c.handleTransientFailure(ctx, nodepool, failure)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+
+		current := &hwmgmtv1alpha1.NodePool{}
+		Expect(c.Client.Get(ctx, client.ObjectKeyFromObject(nodepool), current)).To(Succeed())
+		Expect(isFailedCondition(current, hwmgmtv1alpha1.Provisioned)).To(BeTrue())
+		Expect(current.Status.HwMgrPlugin.ObservedGeneration).To(Equal(nodepool.Generation))
+
+		// The count is dropped, so a retry requested for the failed NodePool starts afresh
+		Expect(c.retries.next(nodepool.UID)).To(Equal(1))
+	})
+})
@@ -0,0 +1,199 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	adaptorinterface "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/adaptor-interface"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+// DefaultProvisioningTimeout is the time allowed for provisioning or configuring a NodePool when neither the NodePool
+// nor its HardwareManager specifies a timeout
+const DefaultProvisioningTimeout = 90 * time.Minute
+
+// getProvisioningTimeout returns the time allowed for provisioning or configuring the NodePool, from its
+// hardwareProvisioningTimeout extension or the default of the HardwareManager
+func getProvisioningTimeout(hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (time.Duration, error) {
+	if value := nodepool.Spec.Extensions[utils.ProvisioningTimeoutKey]; value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return 0, fmt.Errorf("invalid %s extension: %s", utils.ProvisioningTimeoutKey, value)
+		}
+		return timeout, nil
+	}
+
+	if hwmgr.Spec.ProvisioningTimeout != nil {
+		return hwmgr.Spec.ProvisioningTimeout.Duration, nil
+	}
+
+	return DefaultProvisioningTimeout, nil
+}
+
+// findPendingCondition returns the condition of the NodePool operation in progress, which is the Provisioned condition
+// during provisioning, or the Configured condition while a spec change is applied. Returns nil if no operation is in
+// progress.
+func findPendingCondition(nodepool *hwmgmtv1alpha1.NodePool) *metav1.Condition {
+	provisionedCondition := meta.FindStatusCondition(nodepool.Status.Conditions, string(hwmgmtv1alpha1.Provisioned))
+	if provisionedCondition == nil {
+		return nil
+	}
+
+	if provisionedCondition.Status != metav1.ConditionTrue {
+		if provisionedCondition.Reason == string(hwmgmtv1alpha1.InProgress) {
+			return provisionedCondition
+		}
+		return nil
+	}
+
+	configuredCondition := meta.FindStatusCondition(nodepool.Status.Conditions, string(hwmgmtv1alpha1.Configured))
+	if configuredCondition != nil &&
+		configuredCondition.Status != metav1.ConditionTrue &&
+		(configuredCondition.Reason == string(hwmgmtv1alpha1.ConfigUpdate) ||
+			configuredCondition.Reason == string(hwmgmtv1alpha1.InProgress)) {
+		return configuredCondition
+	}
+
+	return nil
+}
+
+// isNodePoolTimedOut checks whether the provisioning of the NodePool has timed out. A timed out configuration is
+// marked as handled, so only a new spec change is processed.
+func isNodePoolTimedOut(nodepool *hwmgmtv1alpha1.NodePool) bool {
+	provisionedCondition := meta.FindStatusCondition(nodepool.Status.Conditions, string(hwmgmtv1alpha1.Provisioned))
+	return provisionedCondition != nil && provisionedCondition.Reason == string(hwmgmtv1alpha1.TimedOut)
+}
+
+// restartNodePoolConfiguration resets the Configured condition when a new spec change is received after a previous
+// change completed, failed or timed out, so that the timeout for the change starts from now. A change waiting for a
+// maintenance window is also reset, so that the time spent waiting does not count towards the timeout.
+func (c *HwMgrAdaptorController) restartNodePoolConfiguration(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) error {
+	if !isNodePoolSpecChanged(nodepool) {
+		return nil
+	}
+
+	configuredCondition := meta.FindStatusCondition(nodepool.Status.Conditions, string(hwmgmtv1alpha1.Configured))
+	if configuredCondition != nil &&
+		(configuredCondition.Reason == string(hwmgmtv1alpha1.ConfigUpdate) ||
+			configuredCondition.Reason == string(hwmgmtv1alpha1.InProgress)) {
+		return nil
+	}
+
+	c.Logger.InfoContext(ctx, "Starting NodePool configuration")
+
+	if err := utils.ResetNodePoolStatusCondition(ctx, c.Client, nodepool,
+		hwmgmtv1alpha1.Configured, hwmgmtv1alpha1.ConfigUpdate, metav1.ConditionFalse,
+		string(hwmgmtv1alpha1.AwaitConfig)); err != nil {
+		return fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	return nil
+}
+
+// getTimeoutStart returns the time from which the timeout of the operation in progress is measured. A configuration
+// rolled out in batches is allowed the timeout for each batch, measured from the completion of the previous batch, so
+// that a rollout over many batches is not cut short.
+func getTimeoutStart(nodepool *hwmgmtv1alpha1.NodePool, condition *metav1.Condition) time.Time {
+	start := condition.LastTransitionTime.Time
+	if condition.Type != string(hwmgmtv1alpha1.Configured) {
+		return start
+	}
+
+	completed, err := time.Parse(time.RFC3339, nodepool.GetAnnotations()[utils.RolloutBatchCompletedAnnotation])
+	if err == nil && completed.After(start) {
+		return completed
+	}
+	return start
+}
+
+// checkNodePoolTimeout checks whether the NodePool operation in progress has exceeded its timeout. A timed out
+// operation is marked with a TimedOut reason on its condition, and the adaptor is given the chance to clean up the
+// hardware manager job. Returns true if the operation timed out, or otherwise the time remaining for an operation in
+// progress.
+func (c *HwMgrAdaptorController) checkNodePoolTimeout(
+	ctx context.Context,
+	adaptor adaptorinterface.HwMgrAdaptorIntf,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (bool, time.Duration, error) {
+
+	condition := findPendingCondition(nodepool)
+	if condition == nil {
+		return false, 0, nil
+	}
+
+	timeout, err := getProvisioningTimeout(hwmgr, nodepool)
+	if err != nil {
+		c.Logger.InfoContext(ctx, "Using default provisioning timeout", slog.String("error", err.Error()))
+		timeout = DefaultProvisioningTimeout
+	}
+
+	remaining := timeout - time.Since(getTimeoutStart(nodepool, condition))
+	if remaining > 0 {
+		return false, remaining, nil
+	}
+
+	conditionType := hwmgmtv1alpha1.ConditionType(condition.Type)
+	operation := "provisioning"
+	if conditionType == hwmgmtv1alpha1.Configured {
+		operation = "configuration"
+	}
+	message := fmt.Sprintf("Hardware %s timed out after %s", operation, timeout)
+	c.Logger.InfoContext(ctx, "NodePool operation timed out", slog.String("condition", condition.Type))
+
+	if handler, ok := adaptor.(adaptorinterface.NodePoolTimeoutHandler); ok {
+		if err := handler.HandleNodePoolTimeout(ctx, hwmgr, nodepool); err != nil {
+			return true, 0, fmt.Errorf("failed to clean up timed out NodePool %s: %w", nodepool.Name, err)
+		}
+	}
+
+	if err := utils.UpdateNodePoolStatusCondition(ctx, c.Client, nodepool,
+		conditionType, hwmgmtv1alpha1.TimedOut, metav1.ConditionFalse, message); err != nil {
+		return true, 0, fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	if conditionType == hwmgmtv1alpha1.Configured {
+		// Mark the spec change as handled, so that the adaptor does not continue to process it
+		if err := utils.UpdateNodePoolPluginStatus(ctx, c.Client, nodepool); err != nil {
+			return true, 0, fmt.Errorf("failed to update plugin status for NodePool %s: %w", nodepool.Name, err)
+		}
+	}
+
+	return true, 0, nil
+}
+
+// requeueBeforeTimeout ensures that a NodePool with an operation in progress is reconciled by the time it times out
+func requeueBeforeTimeout(result ctrl.Result, remaining time.Duration) ctrl.Result {
+	if remaining <= 0 || (result.Requeue && result.RequeueAfter == 0) {
+		return result
+	}
+
+	if result.RequeueAfter == 0 || result.RequeueAfter > remaining {
+		result.RequeueAfter = remaining
+	}
+
+	return result
+}
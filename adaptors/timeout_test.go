@@ -0,0 +1,118 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adaptors
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+var _ = Describe("NodePool timeouts", func() {
+	var (
+		hwmgr    *pluginv1alpha1.HardwareManager
+		nodepool *hwmgmtv1alpha1.NodePool
+	)
+
+	BeforeEach(func() {
+		hwmgr = &pluginv1alpha1.HardwareManager{}
+		nodepool = &hwmgmtv1alpha1.NodePool{}
+	})
+
+	It("takes the timeout from the NodePool, then the HardwareManager", func() {
+		Expect(getProvisioningTimeout(hwmgr, nodepool)).To(Equal(DefaultProvisioningTimeout))
+
+		hwmgr.Spec.ProvisioningTimeout = &metav1.Duration{Duration: time.Hour}
+		Expect(getProvisioningTimeout(hwmgr, nodepool)).To(Equal(time.Hour))
+
+		nodepool.Spec.Extensions = map[string]string{utils.ProvisioningTimeoutKey: "30m"}
+		Expect(getProvisioningTimeout(hwmgr, nodepool)).To(Equal(30 * time.Minute))
+
+		nodepool.Spec.Extensions[utils.ProvisioningTimeoutKey] = "soon"
+		_, err := getProvisioningTimeout(hwmgr, nodepool)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("finds the condition of the operation in progress", func() {
+		Expect(findPendingCondition(nodepool)).To(BeNil())
+
+		nodepool.Status.Conditions = []metav1.Condition{{
+			Type:   string(hwmgmtv1alpha1.Provisioned),
+			Status: metav1.ConditionFalse,
+			Reason: string(hwmgmtv1alpha1.InProgress),
+		}}
+		Expect(findPendingCondition(nodepool).Type).To(Equal(string(hwmgmtv1alpha1.Provisioned)))
+
+		nodepool.Status.Conditions[0].Reason = string(hwmgmtv1alpha1.Failed)
+		Expect(findPendingCondition(nodepool)).To(BeNil())
+
+		nodepool.Status.Conditions[0].Status = metav1.ConditionTrue
+		nodepool.Status.Conditions[0].Reason = string(hwmgmtv1alpha1.Completed)
+		Expect(findPendingCondition(nodepool)).To(BeNil())
+
+		nodepool.Status.Conditions = append(nodepool.Status.Conditions, metav1.Condition{
+			Type:   string(hwmgmtv1alpha1.Configured),
+			Status: metav1.ConditionFalse,
+			Reason: string(hwmgmtv1alpha1.ConfigUpdate),
+		})
+		Expect(findPendingCondition(nodepool).Type).To(Equal(string(hwmgmtv1alpha1.Configured)))
+
+		nodepool.Status.Conditions[1].Reason = string(hwmgmtv1alpha1.TimedOut)
+		Expect(findPendingCondition(nodepool)).To(BeNil())
+	})
+
+	It("measures the timeout of a batched configuration from the last batch", func() {
+		started := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+		condition := metav1.Condition{
+			Type:               string(hwmgmtv1alpha1.Configured),
+			Status:             metav1.ConditionFalse,
+			Reason:             string(hwmgmtv1alpha1.InProgress),
+			LastTransitionTime: metav1.NewTime(started),
+		}
+		Expect(getTimeoutStart(nodepool, &condition)).To(Equal(started))
+
+		batchCompleted := started.Add(time.Hour)
+		utils.SetRolloutBatchCompleted(nodepool, batchCompleted)
+		Expect(getTimeoutStart(nodepool, &condition)).To(BeTemporally("==", batchCompleted))
+
+		// A batch completed by an earlier configuration is ignored
+		utils.SetRolloutBatchCompleted(nodepool, started.Add(-time.Hour))
+		Expect(getTimeoutStart(nodepool, &condition)).To(Equal(started))
+
+		// Provisioning is not batched
+		utils.SetRolloutBatchCompleted(nodepool, batchCompleted)
+		condition.Type = string(hwmgmtv1alpha1.Provisioned)
+		Expect(getTimeoutStart(nodepool, &condition)).To(Equal(started))
+	})
+
+	It("requeues by the time an operation times out", func() {
+		Expect(requeueBeforeTimeout(ctrl.Result{}, 0)).To(Equal(ctrl.Result{}))
+		Expect(requeueBeforeTimeout(ctrl.Result{}, time.Minute)).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+		Expect(requeueBeforeTimeout(ctrl.Result{RequeueAfter: time.Hour}, time.Minute)).To(
+			Equal(ctrl.Result{RequeueAfter: time.Minute}))
+		Expect(requeueBeforeTimeout(ctrl.Result{RequeueAfter: time.Second}, time.Minute)).To(
+			Equal(ctrl.Result{RequeueAfter: time.Second}))
+		Expect(requeueBeforeTimeout(ctrl.Result{Requeue: true}, time.Minute)).To(Equal(ctrl.Result{Requeue: true}))
+	})
+})
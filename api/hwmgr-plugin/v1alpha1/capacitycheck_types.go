@@ -0,0 +1,142 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CapacityCheckNodeGroup defines a nodegroup of the NodePool request to be checked
+type CapacityCheckNodeGroup struct {
+	// Name is the name of the nodegroup
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	// +required
+	Name string `json:"name"`
+
+	// ResourcePoolId is the resource pool from which the nodes of the nodegroup would be allocated
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	// +required
+	ResourcePoolId string `json:"resourcePoolId"`
+
+	// HwProfile is the hardware profile that would be requested for the nodes of the nodegroup
+	// +optional
+	HwProfile string `json:"hwProfile,omitempty"`
+
+	// Size is the number of nodes that would be requested for the nodegroup
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Required
+	// +required
+	Size int `json:"size"`
+
+	// HwMgrId selects a hardware manager for the nodegroup other than the hwMgrId of the CapacityCheck
+	// +optional
+	HwMgrId string `json:"hwMgrId,omitempty"`
+}
+
+// CapacityCheckSpec defines the NodePool request to be checked
+type CapacityCheckSpec struct {
+	// HwMgrId is the name of the HardwareManager CR that would handle the NodePool
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:Required
+	// +required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Hardware Manager ID",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	HwMgrId string `json:"hwMgrId"`
+
+	// Site is the site of the NodePool
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Site",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Site string `json:"site,omitempty"`
+
+	// NodeGroups lists the nodegroups of the NodePool
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Required
+	// +required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	NodeGroups []CapacityCheckNodeGroup `json:"nodeGroups"`
+}
+
+// NodeGroupCapacity reports the result of the capacity check for a nodegroup
+type NodeGroupCapacity struct {
+	// Name is the name of the nodegroup
+	Name string `json:"name"`
+
+	// HwMgrId is the name of the HardwareManager CR that was checked for the nodegroup
+	HwMgrId string `json:"hwMgrId"`
+
+	// ResourcePoolId is the resource pool that was checked for the nodegroup
+	ResourcePoolId string `json:"resourcePoolId"`
+
+	// Requested is the number of nodes requested for the nodegroup
+	Requested int `json:"requested"`
+
+	// Available is the number of free nodes in the resource pool, less those requested by preceding nodegroups
+	// allocated from the same resource pool
+	Available int `json:"available"`
+}
+
+// CapacityCheckStatus defines the observed state of CapacityCheck
+type CapacityCheckStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions describe the result of the capacity check
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// NodeGroups reports the requested and available node counts of each nodegroup
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	NodeGroups []NodeGroupCapacity `json:"nodeGroups,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=capacitychecks,scope=Namespaced
+// +kubebuilder:resource:shortName=capcheck;capchecks
+// +kubebuilder:printcolumn:name="HwMgr Id",type="string",JSONPath=".spec.hwMgrId",description="The hardware manager checked for capacity."
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="The age of the CapacityCheck resource."
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[-1:].reason"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[-1:].status"
+// +kubebuilder:printcolumn:name="Details",type="string",JSONPath=".status.conditions[-1:].message"
+
+// CapacityCheck is the Schema for the capacitychecks API, reporting whether a NodePool request could currently be
+// satisfied, without allocating any nodes
+type CapacityCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CapacityCheckSpec   `json:"spec,omitempty"`
+	Status CapacityCheckStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CapacityCheckList contains a list of CapacityCheck
+type CapacityCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CapacityCheck `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CapacityCheck{}, &CapacityCheckList{})
+}
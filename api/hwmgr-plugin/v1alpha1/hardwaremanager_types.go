@@ -21,15 +21,23 @@ import (
 )
 
 // HardwareManagerAdaptorID defines the type for the Hardware Manager Adaptor
+// +kubebuilder:validation:MinLength=1
 type HardwareManagerAdaptorID string
 
-// SupportedAdaptors defines the string values for valid stages
+// SupportedAdaptors defines the IDs of the adaptors built into the plugin. Adaptors register themselves with the
+// adaptor registry, so this list is not used to validate the adaptorId field.
 var SupportedAdaptors = struct {
-	Loopback HardwareManagerAdaptorID
-	Dell     HardwareManagerAdaptorID
+	Loopback  HardwareManagerAdaptorID
+	Dell      HardwareManagerAdaptorID
+	Metal3    HardwareManagerAdaptorID
+	Redfish   HardwareManagerAdaptorID
+	Inventory HardwareManagerAdaptorID
 }{
-	Loopback: "loopback",
-	Dell:     "dell-hwmgr",
+	Loopback:  "loopback",
+	Dell:      "dell-hwmgr",
+	Metal3:    "metal3",
+	Redfish:   "redfish",
+	Inventory: "inventory",
 }
 
 // ConditionType is a string representing the condition's type
@@ -37,9 +45,15 @@ type ConditionType string
 
 // ConditionTypes define the different types of conditions that will be set
 var ConditionTypes = struct {
-	Validation ConditionType
+	Validation        ConditionType
+	CapacityAvailable ConditionType
+	Degraded          ConditionType
+	Subscribed        ConditionType
 }{
-	Validation: "Validation",
+	Validation:        "Validation",
+	CapacityAvailable: "CapacityAvailable",
+	Degraded:          "Degraded",
+	Subscribed:        "Subscribed",
 }
 
 // ConditionReason is a string representing the condition's reason
@@ -47,13 +61,21 @@ type ConditionReason string
 
 // ConditionReasons define the different reasons that conditions will be set for
 var ConditionReasons = struct {
-	Completed  ConditionReason
-	Failed     ConditionReason
-	InProgress ConditionReason
+	Completed     ConditionReason
+	Failed        ConditionReason
+	InProgress    ConditionReason
+	Insufficient  ConditionReason
+	Unsupported   ConditionReason
+	CircuitOpen   ConditionReason
+	CircuitClosed ConditionReason
 }{
-	Completed:  "Completed",
-	Failed:     "Failed",
-	InProgress: "InProgress",
+	Completed:     "Completed",
+	Failed:        "Failed",
+	InProgress:    "InProgress",
+	Insufficient:  "Insufficient",
+	Unsupported:   "Unsupported",
+	CircuitOpen:   "CircuitOpen",
+	CircuitClosed: "CircuitClosed",
 }
 
 // OAuthGrantType is a string representing the OAuth2 grant type
@@ -97,19 +119,235 @@ type DellData struct {
 	// +optional
 	Tenant *string `json:"tenant,omitempty"`
 
+	// GrantType is the OAuth grant type used to request a token. The password grant requires the client-id, username
+	// and password keys in the auth secret, while the client_credentials grant requires the client-id and
+	// client-secret keys.
+	// +kubebuilder:validation:Enum=password;client_credentials
+	// +kubebuilder:default=password
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OAuth Grant Type"
+	GrantType OAuthGrantType `json:"grantType,omitempty"`
+
+	// TokenUrl is the absolute URL of an OAuth token endpoint to request the token from. If not set, the token is
+	// requested from the token API of the hardware manager.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OAuth Token URL"
+	TokenUrl string `json:"tokenUrl,omitempty"`
+
+	// Scopes is the list of OAuth scopes requested with the token.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="OAuth Scopes"
+	Scopes []string `json:"scopes,omitempty"`
+
+	// RateLimit bounds the rate and concurrency of the requests sent to the hardware manager. If not set, the defaults
+	// described for each field are used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rate Limit"
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+
+	// CircuitBreaker configures the suspension of requests to the hardware manager after consecutive failures. If not
+	// set, the defaults described for each field are used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Circuit Breaker"
+	CircuitBreaker *CircuitBreaker `json:"circuitBreaker,omitempty"`
+
+	// Notifications enables push notifications from the hardware manager, for which the plugin registers and maintains
+	// a resource subscription. Job and resource events then trigger the reconcile of the affected NodePools, with the
+	// polling of job status kept only as a fallback.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Notifications"
+	Notifications *DellNotifications `json:"notifications,omitempty"`
+
 	// insecureSkipTLSVerify indicates that the plugin should not confirm the validity of the TLS certificate of the hardware manager.
 	// This is insecure and is not recommended.
 	// +optional
 	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
 }
 
+// DellNotifications configures the push notifications from a Dell hardware manager
+type DellNotifications struct {
+	// CallbackUrl is the absolute base URL of the notification receiver of the plugin, as reachable from the hardware
+	// manager, such as the URL of a route to the notification service.
+	// +kubebuilder:validation:Required
+	// +required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Callback URL"
+	CallbackUrl string `json:"callbackUrl"`
+
+	// FallbackPollInterval is the interval at which the status of hardware manager jobs is polled while subscribed to
+	// notifications, in case a notification is lost. If not set, an interval of 5 minutes is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Fallback Poll Interval"
+	FallbackPollInterval *metav1.Duration `json:"fallbackPollInterval,omitempty"`
+}
+
+// RateLimit bounds the requests sent to a hardware manager
+type RateLimit struct {
+	// RequestsPerSecond is the sustained rate of requests. If not set, a rate of 10 requests per second is used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Requests Per Second"
+	RequestsPerSecond int32 `json:"requestsPerSecond,omitempty"`
+
+	// Burst is the number of requests that may be sent at once above the sustained rate. If not set, a burst of 20
+	// requests is used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Burst"
+	Burst int32 `json:"burst,omitempty"`
+
+	// MaxConcurrentRequests is the maximum number of requests in flight at a time. If not set, up to 10 concurrent
+	// requests are allowed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Concurrent Requests"
+	MaxConcurrentRequests int32 `json:"maxConcurrentRequests,omitempty"`
+}
+
+// CircuitBreaker configures the suspension of requests to a failing hardware manager
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failed requests, such as connection failures or server errors,
+	// after which the circuit is opened and requests are suspended. If not set, a threshold of 5 is used.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Failure Threshold"
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// OpenDuration is the time requests are suspended while the circuit is open, after which a single trial request
+	// is sent to determine whether the hardware manager has recovered. If not set, a duration of 1 minute is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Open Duration"
+	OpenDuration *metav1.Duration `json:"openDuration,omitempty"`
+}
+
+// Metal3Data defines configuration data for metal3 adaptor instance
+type Metal3Data struct {
+	// BmhNamespace is the namespace of the BareMetalHost CRs from which nodes are allocated. If not set, the
+	// namespace of the HardwareManager CR is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	BmhNamespace string `json:"bmhNamespace,omitempty"`
+}
+
+// InventoryData defines configuration data for inventory adaptor instance
+type InventoryData struct {
+	// NodeNamespace is the namespace of the HardwareNode CRs from which nodes are allocated. If not set, the
+	// namespace of the HardwareManager CR is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	NodeNamespace string `json:"nodeNamespace,omitempty"`
+}
+
+// RedfishBMC defines a BMC endpoint managed by the redfish adaptor
+type RedfishBMC struct {
+	// Name identifies the BMC. It is combined with the Redfish system ID to form the hardware manager node ID, so it
+	// must be unique within the HardwareManager.
+	// +kubebuilder:validation:Required
+	// +required
+	Name string `json:"name"`
+
+	// Address is the URL of the Redfish service of the BMC, such as https://192.168.1.10
+	// +kubebuilder:validation:Required
+	// +required
+	Address string `json:"address"`
+
+	// AuthSecret references a kubernetes.io/basic-auth secret with the username and password for the BMC. If not set,
+	// the authSecret of the redfishData is used.
+	// +optional
+	AuthSecret *string `json:"authSecret,omitempty"`
+
+	// ResourcePoolId is the resource pool from which the systems managed by the BMC are allocated
+	// +kubebuilder:validation:Required
+	// +required
+	ResourcePoolId string `json:"resourcePoolId"`
+}
+
+// RedfishData defines configuration data for redfish adaptor instance, which manages systems directly via their BMCs
+type RedfishData struct {
+	// Bmcs lists the BMC endpoints managed by the adaptor
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Bmcs []RedfishBMC `json:"bmcs,omitempty"`
+
+	// InventorySecret references a secret with an "inventory" key listing additional BMC endpoints, in YAML. Each
+	// entry has the name, address and resourcePoolId fields of a BMC, along with its username and password.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	InventorySecret *string `json:"inventorySecret,omitempty"`
+
+	// AuthSecret references a kubernetes.io/basic-auth secret with the default username and password for the BMCs
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	AuthSecret *string `json:"authSecret,omitempty"`
+
+	// HwProfilesConfigMap references a config map defining the hardware profiles. Each key is a hardware profile name,
+	// with a YAML value defining the biosAttributes applied for the profile and, optionally, the bootInterface NIC.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	HwProfilesConfigMap *string `json:"hwProfilesConfigMap,omitempty"`
+
+	// CaBundleName references a config map that contains a set of custom CA certificates to be used when communicating
+	// with BMCs that have their TLS certificates signed by a non-public CA certificate.
+	// +optional
+	CaBundleName *string `json:"caBundleName,omitempty"`
+
+	// insecureSkipTLSVerify indicates that the plugin should not confirm the validity of the TLS certificates of the BMCs.
+	// This is insecure and is not recommended.
+	// +optional
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+}
+
+// GrpcData defines configuration data for grpc adaptor instance, which forwards requests to an out-of-process adaptor
+type GrpcData struct {
+	// Endpoint is the address of the out-of-process adaptor, in host:port form. This can be a sidecar container in
+	// the plugin pod (e.g. localhost:50051) or a Service (e.g. my-adaptor.my-namespace.svc:50051).
+	// +kubebuilder:validation:Required
+	// +required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Endpoint string `json:"endpoint"`
+
+	// CaBundleName references a config map that contains a set of custom CA certificates to be used when communicating
+	// with an adaptor that has its TLS certificate signed by a non-public CA certificate.
+	// +optional
+	CaBundleName *string `json:"caBundleName,omitempty"`
+
+	// ClientCertSecret references a kubernetes.io/tls secret with the client certificate and key to present to the
+	// adaptor, for mutual TLS.
+	// +optional
+	ClientCertSecret *string `json:"clientCertSecret,omitempty"`
+
+	// insecureSkipTLSVerify indicates that the plugin should not confirm the validity of the TLS certificate of the adaptor.
+	// This is insecure and is not recommended.
+	// +optional
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+
+	// Plaintext disables TLS for the connection to the adaptor. This is only intended for adaptors running as a
+	// sidecar container in the plugin pod.
+	// +optional
+	Plaintext bool `json:"plaintext,omitempty"`
+}
+
+// DriftDetection configures the periodic comparison of provisioned NodePools with their allocation in the hardware
+// manager
+type DriftDetection struct {
+	// Interval is the time between checks of each provisioned NodePool. If not set, an interval of 10 minutes is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Drift Check Interval"
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// AutoRemediate enables the remediation of a detected drift, by releasing the nodes no longer allocated to the
+	// NodePool and restarting its provisioning to allocate replacements
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Drift Auto Remediation"
+	AutoRemediate bool `json:"autoRemediate,omitempty"`
+}
+
 // HardwareManagerSpec defines the desired state of HardwareManager
 type HardwareManagerSpec struct {
 	// Important: Run "make" to regenerate code after modifying this file
 
-	// The adaptor ID
+	// The adaptor ID. The value must match the ID of an adaptor registered with the plugin,
+	// such as loopback or dell-hwmgr.
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=loopback;dell-hwmgr
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	AdaptorID HardwareManagerAdaptorID `json:"adaptorId"`
 
@@ -120,6 +358,70 @@ type HardwareManagerSpec struct {
 	// Config data for an instance of the dell-hwmgr adaptor
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	DellData *DellData `json:"dellData,omitempty"`
+
+	// Config data for an instance of the metal3 adaptor
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Metal3Data *Metal3Data `json:"metal3Data,omitempty"`
+
+	// Config data for an instance of the redfish adaptor
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	RedfishData *RedfishData `json:"redfishData,omitempty"`
+
+	// Config data for an instance of the inventory adaptor
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	InventoryData *InventoryData `json:"inventoryData,omitempty"`
+
+	// Config data for an instance of the grpc adaptor
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	GrpcData *GrpcData `json:"grpcData,omitempty"`
+
+	// ProvisioningTimeout is the default time allowed for provisioning or configuring a NodePool, after which the
+	// operation is marked as TimedOut. It can be overridden per NodePool via the hardwareProvisioningTimeout extension.
+	// If not set, a timeout of 90 minutes is used.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Provisioning Timeout"
+	ProvisioningTimeout *metav1.Duration `json:"provisioningTimeout,omitempty"`
+
+	// DriftDetection configures the periodic check of provisioned NodePools against the hardware manager, for adaptors
+	// that support it. If not set, NodePools are checked every 10 minutes without remediation.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Drift Detection"
+	DriftDetection *DriftDetection `json:"driftDetection,omitempty"`
+
+	// MaintenancePolicyConfigMap references a config map with a "maintenancePolicy" key, defining the maintenance
+	// windows within which disruptive operations, such as hardware profile updates and node releases, are started on
+	// NodePools. It can be overridden per NodePool via the maintenancePolicyConfigMap extension. If not set,
+	// disruptive operations are started at any time.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maintenance Policy Config Map",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	MaintenancePolicyConfigMap string `json:"maintenancePolicyConfigMap,omitempty"`
+}
+
+// AdaptorCapabilities describes the NodePool operations supported by the adaptor handling a HardwareManager
+type AdaptorCapabilities struct {
+	// ProfileUpdate indicates that the adaptor supports changing the hardware profile of provisioned nodes
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ProfileUpdate bool `json:"profileUpdate"`
+
+	// ScaleOut indicates that the adaptor supports increasing the size of a provisioned node group
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ScaleOut bool `json:"scaleOut"`
+
+	// ScaleIn indicates that the adaptor supports decreasing the size of a provisioned node group
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ScaleIn bool `json:"scaleIn"`
+
+	// PowerControl indicates that the adaptor supports powering nodes on and off
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	PowerControl bool `json:"powerControl"`
+
+	// NodeReplacement indicates that the adaptor supports replacing a failed node in a provisioned node group
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	NodeReplacement bool `json:"nodeReplacement"`
+
+	// InventoryListing indicates that the adaptor supports listing the hardware inventory of the hardware manager
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	InventoryListing bool `json:"inventoryListing"`
 }
 
 type ResourcePoolList []string
@@ -140,6 +442,10 @@ type HardwareManagerStatus struct {
 	// ResourcePools provides a per-site list of resource pools
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	ResourcePools PerSiteResourcePoolList `json:"resourcePools,omitempty"`
+
+	// Capabilities describes the NodePool operations supported by the adaptor
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Capabilities *AdaptorCapabilities `json:"capabilities,omitempty"`
 }
 
 // +operator-sdk:csv:customresourcedefinitions:resources={{Service,v1,policy-engine-service}}
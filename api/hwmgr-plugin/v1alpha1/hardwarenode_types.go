@@ -0,0 +1,129 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HardwareNodeBMC defines the BMC of a physical node
+type HardwareNodeBMC struct {
+	// Address is the BMC address, in the format expected by the O-Cloud Manager, such as
+	// idrac-virtualmedia+https://192.168.1.10/redfish/v1/Systems/System.Embedded.1
+	// +kubebuilder:validation:Required
+	// +required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Address string `json:"address"`
+
+	// CredentialsName references a secret in the namespace of the HardwareNode with the username and password keys
+	// for the BMC
+	// +kubebuilder:validation:Required
+	// +required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	CredentialsName string `json:"credentialsName"`
+}
+
+// HardwareNodeInterface defines a network interface of a physical node
+type HardwareNodeInterface struct {
+	// Name is the name of the interface
+	// +kubebuilder:validation:Required
+	// +required
+	Name string `json:"name"`
+
+	// Label identifies the role of the interface, such as bootable-interface for the interface used to boot the node
+	// +optional
+	Label string `json:"label,omitempty"`
+
+	// MACAddress is the MAC address of the interface
+	// +kubebuilder:validation:Required
+	// +required
+	MACAddress string `json:"macAddress"`
+}
+
+// HardwareNodeSpec defines the desired state of HardwareNode
+type HardwareNodeSpec struct {
+	// ResourcePoolId is the resource pool to which the node belongs
+	// +kubebuilder:validation:Required
+	// +required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	ResourcePoolId string `json:"resourcePoolId"`
+
+	// BMC is the BMC of the node
+	// +kubebuilder:validation:Required
+	// +required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	BMC HardwareNodeBMC `json:"bmc"`
+
+	// Interfaces lists the network interfaces of the node
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Interfaces []HardwareNodeInterface `json:"interfaces,omitempty"`
+}
+
+// HardwareNodeAllocation records the NodePool to which a HardwareNode is allocated
+type HardwareNodeAllocation struct {
+	// HwMgrId is the name of the HardwareManager CR that allocated the node
+	HwMgrId string `json:"hwMgrId"`
+
+	// NodePool is the name of the NodePool CR to which the node is allocated
+	NodePool string `json:"nodePool"`
+
+	// NodeGroup is the name of the nodegroup to which the node is allocated
+	NodeGroup string `json:"nodeGroup"`
+
+	// NodeName is the name of the Node CR created for the allocation
+	NodeName string `json:"nodeName"`
+}
+
+// HardwareNodeStatus defines the observed state of HardwareNode
+type HardwareNodeStatus struct {
+	// Allocation records the NodePool to which the node is allocated. A node without an allocation is free.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Allocation *HardwareNodeAllocation `json:"allocation,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=hardwarenodes,scope=Namespaced
+// +kubebuilder:resource:shortName=hwnode;hwnodes
+// +kubebuilder:printcolumn:name="Resource Pool",type="string",JSONPath=".spec.resourcePoolId",description="The resource pool of the node."
+// +kubebuilder:printcolumn:name="NodePool",type="string",JSONPath=".status.allocation.nodePool",description="The NodePool to which the node is allocated."
+// +kubebuilder:printcolumn:name="Node",type="string",JSONPath=".status.allocation.nodeName",description="The Node CR created for the allocation.",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="The age of the HardwareNode resource."
+
+// HardwareNode is the Schema for the hardwarenodes API, describing a physical node managed by the inventory adaptor
+type HardwareNode struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HardwareNodeSpec   `json:"spec,omitempty"`
+	Status HardwareNodeStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HardwareNodeList contains a list of HardwareNode
+type HardwareNodeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HardwareNode `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HardwareNode{}, &HardwareNodeList{})
+}
@@ -25,6 +25,162 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdaptorCapabilities) DeepCopyInto(out *AdaptorCapabilities) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdaptorCapabilities.
+func (in *AdaptorCapabilities) DeepCopy() *AdaptorCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(AdaptorCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityCheck) DeepCopyInto(out *CapacityCheck) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityCheck.
+func (in *CapacityCheck) DeepCopy() *CapacityCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CapacityCheck) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityCheckList) DeepCopyInto(out *CapacityCheckList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CapacityCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityCheckList.
+func (in *CapacityCheckList) DeepCopy() *CapacityCheckList {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityCheckList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CapacityCheckList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityCheckNodeGroup) DeepCopyInto(out *CapacityCheckNodeGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityCheckNodeGroup.
+func (in *CapacityCheckNodeGroup) DeepCopy() *CapacityCheckNodeGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityCheckNodeGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityCheckSpec) DeepCopyInto(out *CapacityCheckSpec) {
+	*out = *in
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]CapacityCheckNodeGroup, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityCheckSpec.
+func (in *CapacityCheckSpec) DeepCopy() *CapacityCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CapacityCheckStatus) DeepCopyInto(out *CapacityCheckStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]NodeGroupCapacity, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CapacityCheckStatus.
+func (in *CapacityCheckStatus) DeepCopy() *CapacityCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CapacityCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreaker) DeepCopyInto(out *CircuitBreaker) {
+	*out = *in
+	if in.OpenDuration != nil {
+		in, out := &in.OpenDuration, &out.OpenDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreaker.
+func (in *CircuitBreaker) DeepCopy() *CircuitBreaker {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreaker)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DellData) DeepCopyInto(out *DellData) {
 	*out = *in
@@ -38,6 +194,26 @@ func (in *DellData) DeepCopyInto(out *DellData) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimit)
+		**out = **in
+	}
+	if in.CircuitBreaker != nil {
+		in, out := &in.CircuitBreaker, &out.CircuitBreaker
+		*out = new(CircuitBreaker)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(DellNotifications)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DellData.
@@ -50,6 +226,71 @@ func (in *DellData) DeepCopy() *DellData {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DellNotifications) DeepCopyInto(out *DellNotifications) {
+	*out = *in
+	if in.FallbackPollInterval != nil {
+		in, out := &in.FallbackPollInterval, &out.FallbackPollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DellNotifications.
+func (in *DellNotifications) DeepCopy() *DellNotifications {
+	if in == nil {
+		return nil
+	}
+	out := new(DellNotifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetection) DeepCopyInto(out *DriftDetection) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetection.
+func (in *DriftDetection) DeepCopy() *DriftDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrpcData) DeepCopyInto(out *GrpcData) {
+	*out = *in
+	if in.CaBundleName != nil {
+		in, out := &in.CaBundleName, &out.CaBundleName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClientCertSecret != nil {
+		in, out := &in.ClientCertSecret, &out.ClientCertSecret
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrpcData.
+func (in *GrpcData) DeepCopy() *GrpcData {
+	if in == nil {
+		return nil
+	}
+	out := new(GrpcData)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HardwareManager) DeepCopyInto(out *HardwareManager) {
 	*out = *in
@@ -122,6 +363,36 @@ func (in *HardwareManagerSpec) DeepCopyInto(out *HardwareManagerSpec) {
 		*out = new(DellData)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Metal3Data != nil {
+		in, out := &in.Metal3Data, &out.Metal3Data
+		*out = new(Metal3Data)
+		**out = **in
+	}
+	if in.RedfishData != nil {
+		in, out := &in.RedfishData, &out.RedfishData
+		*out = new(RedfishData)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InventoryData != nil {
+		in, out := &in.InventoryData, &out.InventoryData
+		*out = new(InventoryData)
+		**out = **in
+	}
+	if in.GrpcData != nil {
+		in, out := &in.GrpcData, &out.GrpcData
+		*out = new(GrpcData)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProvisioningTimeout != nil {
+		in, out := &in.ProvisioningTimeout, &out.ProvisioningTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetection)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareManagerSpec.
@@ -160,6 +431,11 @@ func (in *HardwareManagerStatus) DeepCopyInto(out *HardwareManagerStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(AdaptorCapabilities)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareManagerStatus.
@@ -172,6 +448,166 @@ func (in *HardwareManagerStatus) DeepCopy() *HardwareManagerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareNode) DeepCopyInto(out *HardwareNode) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareNode.
+func (in *HardwareNode) DeepCopy() *HardwareNode {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareNode) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareNodeAllocation) DeepCopyInto(out *HardwareNodeAllocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareNodeAllocation.
+func (in *HardwareNodeAllocation) DeepCopy() *HardwareNodeAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareNodeAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareNodeBMC) DeepCopyInto(out *HardwareNodeBMC) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareNodeBMC.
+func (in *HardwareNodeBMC) DeepCopy() *HardwareNodeBMC {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareNodeBMC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareNodeInterface) DeepCopyInto(out *HardwareNodeInterface) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareNodeInterface.
+func (in *HardwareNodeInterface) DeepCopy() *HardwareNodeInterface {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareNodeInterface)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareNodeList) DeepCopyInto(out *HardwareNodeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HardwareNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareNodeList.
+func (in *HardwareNodeList) DeepCopy() *HardwareNodeList {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareNodeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HardwareNodeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareNodeSpec) DeepCopyInto(out *HardwareNodeSpec) {
+	*out = *in
+	out.BMC = in.BMC
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]HardwareNodeInterface, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareNodeSpec.
+func (in *HardwareNodeSpec) DeepCopy() *HardwareNodeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareNodeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareNodeStatus) DeepCopyInto(out *HardwareNodeStatus) {
+	*out = *in
+	if in.Allocation != nil {
+		in, out := &in.Allocation, &out.Allocation
+		*out = new(HardwareNodeAllocation)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareNodeStatus.
+func (in *HardwareNodeStatus) DeepCopy() *HardwareNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryData) DeepCopyInto(out *InventoryData) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryData.
+func (in *InventoryData) DeepCopy() *InventoryData {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryData)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoopbackData) DeepCopyInto(out *LoopbackData) {
 	*out = *in
@@ -187,6 +623,36 @@ func (in *LoopbackData) DeepCopy() *LoopbackData {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Metal3Data) DeepCopyInto(out *Metal3Data) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Metal3Data.
+func (in *Metal3Data) DeepCopy() *Metal3Data {
+	if in == nil {
+		return nil
+	}
+	out := new(Metal3Data)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupCapacity) DeepCopyInto(out *NodeGroupCapacity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupCapacity.
+func (in *NodeGroupCapacity) DeepCopy() *NodeGroupCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in PerSiteResourcePoolList) DeepCopyInto(out *PerSiteResourcePoolList) {
 	{
@@ -217,6 +683,83 @@ func (in PerSiteResourcePoolList) DeepCopy() PerSiteResourcePoolList {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedfishBMC) DeepCopyInto(out *RedfishBMC) {
+	*out = *in
+	if in.AuthSecret != nil {
+		in, out := &in.AuthSecret, &out.AuthSecret
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedfishBMC.
+func (in *RedfishBMC) DeepCopy() *RedfishBMC {
+	if in == nil {
+		return nil
+	}
+	out := new(RedfishBMC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedfishData) DeepCopyInto(out *RedfishData) {
+	*out = *in
+	if in.Bmcs != nil {
+		in, out := &in.Bmcs, &out.Bmcs
+		*out = make([]RedfishBMC, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InventorySecret != nil {
+		in, out := &in.InventorySecret, &out.InventorySecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.AuthSecret != nil {
+		in, out := &in.AuthSecret, &out.AuthSecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.HwProfilesConfigMap != nil {
+		in, out := &in.HwProfilesConfigMap, &out.HwProfilesConfigMap
+		*out = new(string)
+		**out = **in
+	}
+	if in.CaBundleName != nil {
+		in, out := &in.CaBundleName, &out.CaBundleName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedfishData.
+func (in *RedfishData) DeepCopy() *RedfishData {
+	if in == nil {
+		return nil
+	}
+	out := new(RedfishData)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ResourcePoolList) DeepCopyInto(out *ResourcePoolList) {
 	{
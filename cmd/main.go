@@ -23,24 +23,31 @@ import (
 	"log/slog"
 	"os"
 
+	// Embed the time zone database, for the time zones of maintenance policies
+	_ "time/tzdata"
+
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/logging"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/notifications"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/readiness"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
-	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	hwmgrplugincontroller "github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/hwmgr-plugin"
 	o2imshardwaremanagementcontroller "github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/o2ims-hardwaremanagement"
+	hwmgrpluginwebhook "github.com/openshift-kni/oran-hwmgr-plugin/internal/webhook/hwmgr-plugin"
+	o2imshardwaremanagementwebhook "github.com/openshift-kni/oran-hwmgr-plugin/internal/webhook/o2ims-hardwaremanagement"
 
 	//+kubebuilder:scaffold:imports
 
@@ -65,6 +72,9 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var readinessPolicy string
+	var notificationAddr string
+	var notificationCertDir string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -74,6 +84,13 @@ func main() {
 		"If set the metrics endpoint is served securely")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&readinessPolicy, "hwmgr-readiness-policy", string(readiness.PolicyAny),
+		"The HardwareManagers that must pass their readiness check for the plugin to report ready: any, all or none")
+	flag.StringVar(&notificationAddr, "notification-bind-address", "0",
+		"The address the notification receiver binds to, to receive push notifications from hardware managers. "+
+			"Set this to '0' to disable the receiver.")
+	flag.StringVar(&notificationCertDir, "notification-cert-dir", notifications.DefaultCertDir,
+		"The directory containing the tls.crt and tls.key serving certificate of the notification receiver")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -102,6 +119,12 @@ func main() {
 		TLSOpts: tlsOpts,
 	})
 
+	policy, err := readiness.ParsePolicy(readinessPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid readiness policy")
+		os.Exit(1)
+	}
+
 	myNamespace := os.Getenv("MY_POD_NAMESPACE")
 	if myNamespace == "" {
 		setupLog.Error(fmt.Errorf("unable to find env variable MY_POD_NAMESPACE"), "unable to determine namespace")
@@ -122,8 +145,9 @@ func main() {
 			SecureServing: secureMetrics,
 			TLSOpts:       tlsOpts,
 		},
-		WebhookServer:          webhookServer,
-		HealthProbeBindAddress: probeAddr,
+		WebhookServer: webhookServer,
+		// The health probe server is added below, to serve per-HardwareManager readiness checks
+		HealthProbeBindAddress: "0",
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "d5b3dd42.oran.openshift.io",
 
@@ -148,11 +172,26 @@ func main() {
 		os.Exit(1)
 	}
 
+	var notificationReceiver *notifications.Receiver
+	if notificationAddr != "0" {
+		notificationReceiver = notifications.NewReceiver(notifications.Options{
+			BindAddress: notificationAddr,
+			CertDir:     notificationCertDir,
+			TLSOpts:     tlsOpts,
+		}, slog.New(logging.NewLoggingContextHandler(slog.LevelInfo)).With("server", "notifications"))
+		if err := mgr.Add(notificationReceiver); err != nil {
+			setupLog.Error(err, "unable to set up notification receiver")
+			os.Exit(1)
+		}
+	}
+
 	hwmgrAdaptor := &adaptors.HwMgrAdaptorController{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		Logger:    slog.New(logging.NewLoggingContextHandler(slog.LevelInfo)).With("controller", "adaptors"),
-		Namespace: myNamespace,
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Logger:        slog.New(logging.NewLoggingContextHandler(slog.LevelInfo)).With("controller", "adaptors"),
+		Namespace:     myNamespace,
+		Recorder:      mgr.GetEventRecorderFor("oran-hwmgr-plugin"),
+		Notifications: notificationReceiver,
 	}
 	if err = hwmgrAdaptor.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to setup adaptor controller")
@@ -169,16 +208,57 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "NodePool")
 		os.Exit(1)
 	}
-	//+kubebuilder:scaffold:builder
 
-	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up health check")
+	if err = (&hwmgrplugincontroller.CapacityCheckReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Logger:       slog.New(logging.NewLoggingContextHandler(slog.LevelInfo)).With("controller", "CapacityCheck"),
+		Namespace:    myNamespace,
+		HwMgrAdaptor: hwmgrAdaptor,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "CapacityCheck")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up ready check")
+
+	// The webhooks can be disabled when running the manager locally, without a serving certificate
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = (&o2imshardwaremanagementwebhook.NodePoolValidator{
+			Client:    mgr.GetClient(),
+			Logger:    slog.New(logging.NewLoggingContextHandler(slog.LevelInfo)).With("webhook", "NodePool"),
+			Namespace: myNamespace,
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NodePool")
+			os.Exit(1)
+		}
+
+		if err = (&hwmgrpluginwebhook.HardwareManagerWebhook{
+			Client:    mgr.GetClient(),
+			Logger:    slog.New(logging.NewLoggingContextHandler(slog.LevelInfo)).With("webhook", "HardwareManager"),
+			Namespace: myNamespace,
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "HardwareManager")
+			os.Exit(1)
+		}
+	}
+	//+kubebuilder:scaffold:builder
+
+	readinessMonitor := &readiness.Monitor{
+		Client:    mgr.GetClient(),
+		Logger:    slog.New(logging.NewLoggingContextHandler(slog.LevelInfo)).With("controller", "readiness"),
+		Namespace: myNamespace,
+		Check:     hwmgrAdaptor.CheckReadiness,
+		Policy:    policy,
+	}
+	if err := mgr.Add(readinessMonitor); err != nil {
+		setupLog.Error(err, "unable to set up readiness monitor")
 		os.Exit(1)
 	}
+	if probeAddr != "0" {
+		if err := mgr.Add(readiness.NewProbeServer(probeAddr, readinessMonitor)); err != nil {
+			setupLog.Error(err, "unable to set up health probe server")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
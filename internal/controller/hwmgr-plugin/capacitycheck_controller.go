@@ -0,0 +1,142 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/logging"
+)
+
+// CapacityCheckReconciler reconciles a CapacityCheck object
+type CapacityCheckReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	Logger       *slog.Logger
+	Namespace    string
+	HwMgrAdaptor *adaptors.HwMgrAdaptorController
+}
+
+//+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=capacitychecks,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=hwmgr-plugin.oran.openshift.io,resources=capacitychecks/status,verbs=get;update;patch
+
+// Reconcile runs the capacity check for each new generation of a CapacityCheck CR
+func (r *CapacityCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx = logging.AppendCtx(ctx, slog.String("capacitycheck", req.Name))
+
+	check := &pluginv1alpha1.CapacityCheck{}
+	if err := r.Client.Get(ctx, req.NamespacedName, check); err != nil {
+		if apierrors.IsNotFound(err) {
+			return utils.DoNotRequeue(), nil
+		}
+		r.Logger.ErrorContext(ctx, "Unable to fetch CapacityCheck", slog.String("error", err.Error()))
+		return utils.RequeueWithShortInterval(), fmt.Errorf("failed to get CapacityCheck %s: %w", req.Name, err)
+	}
+
+	// The check is run once per generation. A new check is requested by updating the spec, or recreating the CR.
+	if check.Status.ObservedGeneration == check.Generation {
+		return utils.DoNotRequeue(), nil
+	}
+
+	r.Logger.InfoContext(ctx, "Checking capacity", slog.String("hwmgr", check.Spec.HwMgrId))
+
+	nodegroups, err := r.HwMgrAdaptor.CheckCapacity(ctx, check)
+	if err != nil {
+		r.Logger.InfoContext(ctx, "Capacity check failed", slog.String("error", err.Error()))
+
+		if errors.Is(err, adaptors.ErrCapacityCheckUnsupported) {
+			// Retrying will not help until the CapacityCheck is changed to select another hardware manager
+			return r.updateStatus(ctx, check, nil, metav1.ConditionUnknown,
+				pluginv1alpha1.ConditionReasons.Unsupported, err.Error(), utils.DoNotRequeue())
+		}
+
+		// The HardwareManager may not exist yet, or may be temporarily unreachable
+		return r.updateStatus(ctx, check, nil, metav1.ConditionFalse,
+			pluginv1alpha1.ConditionReasons.Failed, err.Error(), utils.RequeueWithMediumInterval())
+	}
+
+	if !adaptors.IsCapacityAvailable(nodegroups) {
+		var insufficient []string
+		for _, nodegroup := range nodegroups {
+			if nodegroup.Requested > nodegroup.Available {
+				insufficient = append(insufficient, fmt.Sprintf("%s (requested %d, available %d)",
+					nodegroup.Name, nodegroup.Requested, nodegroup.Available))
+			}
+		}
+		return r.updateStatus(ctx, check, nodegroups, metav1.ConditionFalse,
+			pluginv1alpha1.ConditionReasons.Insufficient,
+			"Insufficient capacity for nodegroups: "+strings.Join(insufficient, ", "), utils.DoNotRequeue())
+	}
+
+	return r.updateStatus(ctx, check, nodegroups, metav1.ConditionTrue,
+		pluginv1alpha1.ConditionReasons.Completed, "Capacity available for all nodegroups", utils.DoNotRequeue())
+}
+
+// updateStatus records the result of the capacity check. The generation is marked as observed unless the check is to
+// be retried.
+func (r *CapacityCheckReconciler) updateStatus(
+	ctx context.Context,
+	check *pluginv1alpha1.CapacityCheck,
+	nodegroups []pluginv1alpha1.NodeGroupCapacity,
+	conditionStatus metav1.ConditionStatus,
+	conditionReason pluginv1alpha1.ConditionReason,
+	message string,
+	result ctrl.Result) (ctrl.Result, error) {
+
+	check.Status.NodeGroups = nodegroups
+	if result.IsZero() {
+		check.Status.ObservedGeneration = check.Generation
+	}
+
+	utils.SetStatusCondition(&check.Status.Conditions,
+		string(pluginv1alpha1.ConditionTypes.CapacityAvailable),
+		string(conditionReason),
+		conditionStatus,
+		message)
+
+	if err := utils.UpdateK8sCRStatus(ctx, r.Client, check); err != nil {
+		return utils.RequeueWithShortInterval(), fmt.Errorf("failed to update status for CapacityCheck %s: %w", check.Name, err)
+	}
+
+	return result, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *CapacityCheckReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&pluginv1alpha1.CapacityCheck{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to setup CapacityCheck controller: %w", err)
+	}
+
+	return nil
+}
@@ -22,14 +22,22 @@ import (
 	"log/slog"
 
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/logging"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	adaptors "github.com/openshift-kni/oran-hwmgr-plugin/adaptors"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
 	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
 	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
 )
@@ -43,7 +51,7 @@ type NodePoolReconciler struct {
 	HwMgrAdaptor *adaptors.HwMgrAdaptorController
 }
 
-//+kubebuilder:rbac:groups=o2ims-hardwaremanagement.oran.openshift.io,resources=nodepools,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=o2ims-hardwaremanagement.oran.openshift.io,resources=nodepools,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=o2ims-hardwaremanagement.oran.openshift.io,resources=nodepools/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=o2ims-hardwaremanagement.oran.openshift.io,resources=nodepools/finalizers,verbs=update
 //+kubebuilder:rbac:groups=o2ims-hardwaremanagement.oran.openshift.io,resources=nodes,verbs=get;create;list;watch;update;patch;delete
@@ -51,6 +59,7 @@ type NodePoolReconciler struct {
 //+kubebuilder:rbac:groups=o2ims-hardwaremanagement.oran.openshift.io,resources=nodes/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;create;update;patch;watch
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;create;update;patch;watch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -87,16 +96,7 @@ func (r *NodePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		// Handle deletion
 		r.Logger.InfoContext(ctx, "Nodepool is being deleted")
 		if controllerutil.ContainsFinalizer(nodepool, utils.NodepoolFinalizer) {
-			if err := r.HwMgrAdaptor.HandleNodePoolDeletion(ctx, nodepool); err != nil {
-				// Log the failure and continue, to remove the finalizer and allow the deletion
-				r.Logger.InfoContext(ctx, "Failed HandleNodePoolDeletion", slog.String("error", err.Error()))
-			}
-
-			if err := utils.NodepoolRemoveFinalizer(ctx, r.Client, nodepool); err != nil {
-				return utils.RequeueImmediately(), fmt.Errorf("failed to remove finalizer from nodepool: %w", err)
-			}
-
-			return utils.DoNotRequeue(), nil
+			return r.handleNodePoolDeletion(ctx, nodepool)
 		}
 		return utils.DoNotRequeue(), nil
 	}
@@ -111,6 +111,61 @@ func (r *NodePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 	return
 }
 
+// handleNodePoolDeletion releases the resources of a NodePool being deleted, requeueing until the adaptor confirms the
+// release. The finalizer is then removed, or immediately if the NodePool is annotated for force deletion.
+func (r *NodePoolReconciler) handleNodePoolDeletion(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+	released, err := r.HwMgrAdaptor.HandleNodePoolDeletion(ctx, nodepool)
+
+	if utils.IsNodePoolForceDelete(nodepool) {
+		if err != nil {
+			r.Logger.InfoContext(ctx, "Failed HandleNodePoolDeletion", slog.String("error", err.Error()))
+		}
+		if !released || err != nil {
+			r.Logger.InfoContext(ctx, "Force deleting NodePool without confirmed release of resources")
+		}
+	} else if err != nil {
+		if updateErr := utils.UpdateNodePoolStatusCondition(ctx, r.Client, nodepool,
+			utils.NodePoolDeprovisioning, hwmgmtv1alpha1.Failed, metav1.ConditionFalse,
+			"Release failed: "+err.Error()); updateErr != nil {
+			r.Logger.InfoContext(ctx, "Failed to update status", slog.String("error", updateErr.Error()))
+		}
+		return utils.RequeueWithMediumInterval(), fmt.Errorf("failed HandleNodePoolDeletion: %w", err)
+	} else if !released {
+		if err := utils.UpdateNodePoolStatusCondition(ctx, r.Client, nodepool,
+			utils.NodePoolDeprovisioning, hwmgmtv1alpha1.InProgress, metav1.ConditionFalse,
+			"Releasing resources"); err != nil {
+			return utils.RequeueWithShortInterval(), fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+		}
+		return utils.RequeueWithShortInterval(), nil
+	}
+
+	if err := utils.NodepoolRemoveFinalizer(ctx, r.Client, nodepool); err != nil {
+		return utils.RequeueImmediately(), fmt.Errorf("failed to remove finalizer from nodepool: %w", err)
+	}
+
+	return utils.DoNotRequeue(), nil
+}
+
+// mapHardwareManagerToNodePools returns a reconcile request for each NodePool referencing the HardwareManager
+func (r *NodePoolReconciler) mapHardwareManagerToNodePools(ctx context.Context, obj client.Object) []reconcile.Request {
+	var nodepools hwmgmtv1alpha1.NodePoolList
+	if err := r.Client.List(ctx, &nodepools,
+		client.InNamespace(r.Namespace),
+		client.MatchingFields{utils.NodePoolSpecHwMgrIdKey: obj.GetName()}); err != nil {
+		r.Logger.ErrorContext(ctx, "Unable to list NodePools for HardwareManager",
+			slog.String("hwmgr", obj.GetName()),
+			slog.String("error", err.Error()))
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(nodepools.Items))
+	for _, nodepool := range nodepools.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&nodepool)})
+	}
+
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *NodePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Setup Node CRD indexer. This field indexer allows us to query a list of Node CRs, filtered by the spec.nodePool field.
@@ -122,9 +177,35 @@ func (r *NodePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return fmt.Errorf("failed to setup node indexer: %w", err)
 	}
 
-	if err := ctrl.NewControllerManagedBy(mgr).
+	// Setup NodePool CRD indexer. This field indexer allows us to query a list of NodePool CRs, filtered by the
+	// spec.hwMgrId field.
+	nodepoolIndexFunc := func(obj client.Object) []string {
+		return []string{obj.(*hwmgmtv1alpha1.NodePool).Spec.HwMgrId}
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &hwmgmtv1alpha1.NodePool{}, utils.NodePoolSpecHwMgrIdKey, nodepoolIndexFunc); err != nil {
+		return fmt.Errorf("failed to setup nodepool indexer: %w", err)
+	}
+
+	// The NodePool also owns the child NodePools created for nodegroups assigned to other hardware managers, so
+	// changes to a child trigger the aggregation of its status into the parent. Changes to the Node CRs and BMC
+	// secrets created for the NodePool, which reference it without being controlled by it, trigger a reconcile as
+	// well, as do changes to the HardwareManager referenced by the NodePool.
+	bldr := ctrl.NewControllerManagedBy(mgr).
 		For(&hwmgmtv1alpha1.NodePool{}).
-		Complete(r); err != nil {
+		Owns(&hwmgmtv1alpha1.NodePool{}).
+		Owns(&hwmgmtv1alpha1.Node{}, builder.MatchEveryOwner).
+		Owns(&corev1.Secret{}, builder.MatchEveryOwner).
+		Watches(&pluginv1alpha1.HardwareManager{},
+			handler.EnqueueRequestsFromMapFunc(r.mapHardwareManagerToNodePools),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+
+	// Notifications pushed by the hardware managers trigger a reconcile of the affected NodePools
+	if r.HwMgrAdaptor != nil && r.HwMgrAdaptor.Notifications != nil {
+		bldr = bldr.WatchesRawSource(source.Channel(r.HwMgrAdaptor.Notifications.NodePoolEvents(), &handler.EnqueueRequestForObject{}))
+	}
+
+	if err := bldr.Complete(r); err != nil {
 		return fmt.Errorf("failed to create controller: %w", err)
 	}
 
@@ -0,0 +1,167 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NodeAllocator is implemented by the adaptors that allocate nodes to a NodePool from an inventory they manage
+// themselves, rather than delegating to a hardware manager.
+type NodeAllocator interface {
+	// ProcessNewNodePool validates a new NodePool, verifying that the request can be satisfied
+	ProcessNewNodePool(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) error
+
+	// CheckNodePoolProgress claims resources and creates Node CRs as needed, returning whether the NodePool is
+	// fully allocated. A conflict error indicates another NodePool claimed a resource first.
+	CheckNodePoolProgress(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) (bool, error)
+
+	// GetAllocatedNodes returns the names of the Node CRs allocated to the NodePool
+	GetAllocatedNodes(ctx context.Context, nodepool *hwmgmtv1alpha1.NodePool) ([]string, error)
+
+	// ReleaseNodePool frees the resources claimed by a NodePool. The Node CRs and BMC secrets are owned by the
+	// NodePool, and are cleaned up by garbage collection.
+	ReleaseNodePool(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) error
+}
+
+// GetNodePoolSize returns the total number of nodes requested by the NodePool
+func GetNodePoolSize(nodepool *hwmgmtv1alpha1.NodePool) int {
+	total := 0
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		total += nodegroup.Size
+	}
+	return total
+}
+
+// CheckFreeResources verifies that each nodegroup of the NodePool can be satisfied by the free resources in its
+// resource pool, as counted by the freeCount callback
+func CheckFreeResources(
+	nodepool *hwmgmtv1alpha1.NodePool,
+	freeCount func(nodegroup hwmgmtv1alpha1.NodeGroup) (int, error)) error {
+
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		free, err := freeCount(nodegroup)
+		if err != nil {
+			return err
+		}
+		if nodegroup.Size > free {
+			return fmt.Errorf("not enough free resources in resource pool %s: freenodes=%d", nodegroup.NodePoolData.ResourcePoolId, free)
+		}
+	}
+
+	return nil
+}
+
+// HandleAllocationCreate processes a new NodePool CR for a NodeAllocator, setting the Provisioned condition to
+// InProgress if the request can be satisfied, or Failed otherwise. A transient failure is returned, to be retried.
+func HandleAllocationCreate(
+	ctx context.Context,
+	c client.Client,
+	logger *slog.Logger,
+	allocator NodeAllocator,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	conditionType := hwmgmtv1alpha1.Provisioned
+	var conditionReason hwmgmtv1alpha1.ConditionReason
+	var conditionStatus metav1.ConditionStatus
+	var message string
+
+	processErr := allocator.ProcessNewNodePool(ctx, hwmgr, nodepool)
+	if processErr != nil {
+		logger.Error("failed createNodePool", "err", processErr)
+		conditionReason = hwmgmtv1alpha1.Failed
+		conditionStatus = metav1.ConditionFalse
+		message = "Creation request failed: " + processErr.Error()
+	} else {
+		conditionReason = hwmgmtv1alpha1.InProgress
+		conditionStatus = metav1.ConditionFalse
+		message = "Handling creation"
+	}
+
+	if err := UpdateNodePoolStatusCondition(ctx, c, nodepool,
+		conditionType, conditionReason, conditionStatus, message); err != nil {
+		return RequeueWithMediumInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+	// Update the Node Pool hwMgrPlugin status
+	if err := UpdateNodePoolPluginStatus(ctx, c, nodepool); err != nil {
+		return RequeueWithShortInterval(), fmt.Errorf("failed to update hwMgrPlugin observedGeneration Status: %w", err)
+	}
+
+	if IsTransientError(processErr) {
+		// The request may succeed if reissued, once the inventory is reachable
+		return DoNotRequeue(), processErr
+	}
+	return DoNotRequeue(), nil
+}
+
+// HandleAllocationProcessing checks the progress of an in-progress NodePool for a NodeAllocator, updating the
+// allocated node names and setting the Provisioned condition to Completed once the NodePool is fully allocated
+func HandleAllocationProcessing(
+	ctx context.Context,
+	c client.Client,
+	logger *slog.Logger,
+	allocator NodeAllocator,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (ctrl.Result, error) {
+
+	full, err := allocator.CheckNodePoolProgress(ctx, hwmgr, nodepool)
+	if err != nil {
+		if errors.IsConflict(err) {
+			// Another NodePool claimed the resource first, so try again with the updated list
+			logger.InfoContext(ctx, "Conflict claiming resources, retrying", slog.String("error", err.Error()))
+			return RequeueImmediately(), nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed CheckNodePoolProgress: %w", err)
+	}
+
+	allocatedNodes, err := allocator.GetAllocatedNodes(ctx, nodepool)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get allocated nodes for %s: %w", nodepool.Name, err)
+	}
+	nodepool.Status.Properties.NodeNames = allocatedNodes
+
+	if err := UpdateNodePoolProperties(ctx, c, nodepool); err != nil {
+		return RequeueWithMediumInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	if !full {
+		logger.InfoContext(ctx, "NodePool request in progress")
+		return RequeueWithShortInterval(), nil
+	}
+
+	logger.InfoContext(ctx, "NodePool request is fully allocated")
+
+	if err := UpdateNodePoolStatusCondition(ctx, c, nodepool,
+		hwmgmtv1alpha1.Provisioned, hwmgmtv1alpha1.Completed, metav1.ConditionTrue, "Created"); err != nil {
+		return RequeueWithMediumInterval(),
+			fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	return DoNotRequeue(), nil
+}
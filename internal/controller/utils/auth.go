@@ -73,7 +73,7 @@ const (
 )
 
 // The following regex pattern is used to match keys to automatically redact from the message tracing logs
-var redactionPattern = regexp.MustCompile(`(?i)password|token|client_id|username`)
+var redactionPattern = regexp.MustCompile(`(?i)password|token|client_id|client_secret|username`)
 
 // Replacement string for redacted fields in message tracing logs
 const redactedValue = "*redacted*"
@@ -277,6 +277,36 @@ func (t LoggingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error
 	return resp, err // nolint: wrapcheck
 }
 
+// GetOAuthTokenConfig returns the configuration used to acquire a token with the grant type of the OAuth client config
+func GetOAuthTokenConfig(config OAuthClientConfig) (*clientcredentials.Config, error) {
+	switch config.GrantType {
+	case pluginv1alpha1.OAuthGrantTypes.ClientCredentials:
+		return &clientcredentials.Config{
+			ClientID:       config.ClientId,
+			ClientSecret:   config.ClientSecret,
+			TokenURL:       config.TokenUrl,
+			Scopes:         config.Scopes,
+			EndpointParams: nil,
+			AuthStyle:      oauth2.AuthStyleInParams,
+		}, nil
+
+	case pluginv1alpha1.OAuthGrantTypes.Password:
+		return &clientcredentials.Config{
+			ClientID: config.ClientId,
+			TokenURL: config.TokenUrl,
+			Scopes:   config.Scopes,
+			EndpointParams: url.Values{
+				"grant_type": {string(config.GrantType)},
+				"username":   {config.Username},
+				"password":   {config.Password},
+			},
+			AuthStyle: oauth2.AuthStyleAutoDetect,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported grant_type: %s", config.GrantType)
+	}
+}
+
 // SetupOAuthClient creates an HTTP client capable of acquiring an OAuth token used to authorize client requests.  If
 // the config excludes the OAuth specific sections then the client produced is a simple HTTP client without OAuth
 // capabilities.
@@ -291,32 +321,9 @@ func SetupOAuthClient(ctx context.Context, config OAuthClientConfig, insecureSki
 	}
 
 	if config.ClientId != "" {
-		var clientConfig clientcredentials.Config
-		switch config.GrantType {
-		case pluginv1alpha1.OAuthGrantTypes.ClientCredentials:
-			clientConfig = clientcredentials.Config{
-				ClientID:       config.ClientId,
-				ClientSecret:   config.ClientSecret,
-				TokenURL:       config.TokenUrl,
-				Scopes:         config.Scopes,
-				EndpointParams: nil,
-				AuthStyle:      oauth2.AuthStyleInParams,
-			}
-
-		case pluginv1alpha1.OAuthGrantTypes.Password:
-			clientConfig = clientcredentials.Config{
-				ClientID: config.ClientId,
-				TokenURL: config.TokenUrl,
-				Scopes:   config.Scopes,
-				EndpointParams: url.Values{
-					"grant_type": {string(config.GrantType)},
-					"username":   {config.Username},
-					"password":   {config.Password},
-				},
-				AuthStyle: oauth2.AuthStyleAutoDetect,
-			}
-		default:
-			return nil, fmt.Errorf("unsupported grant_type: %s", config.GrantType)
+		clientConfig, err := GetOAuthTokenConfig(config)
+		if err != nil {
+			return nil, err
 		}
 
 		ctx = context.WithValue(ctx, oauth2.HTTPClient, c)
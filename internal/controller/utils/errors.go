@@ -19,6 +19,9 @@ package utils
 import (
 	"errors"
 	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 // InputError wraps a standard error and provides a custom error type for input-related errors
@@ -41,3 +44,66 @@ func IsInputError(err error) bool {
 
 	return errors.As(err, &inputErr)
 }
+
+// RetriableError is implemented by errors that classify whether the failed operation may succeed on retry, and the
+// delay requested before retrying, if any. Adaptors may return their own error types implementing it, so that the
+// adaptor controller retries their transient failures.
+type RetriableError interface {
+	error
+	Transient() bool
+	RetryDelay() time.Duration
+}
+
+// TransientError wraps a standard error and provides a custom error type for failures that may succeed on retry,
+// such as a failure to communicate with the hardware manager
+type TransientError struct {
+	err error
+}
+
+func (t *TransientError) Error() string {
+	return t.err.Error()
+}
+
+func (t *TransientError) Unwrap() error {
+	return t.err
+}
+
+func (t *TransientError) Transient() bool {
+	return true
+}
+
+func (t *TransientError) RetryDelay() time.Duration {
+	return 0
+}
+
+func NewTransientError(format string, args ...interface{}) *TransientError {
+	return &TransientError{
+		err: fmt.Errorf(format, args...),
+	}
+}
+
+// IsTransientError checks whether the error is a failure that may succeed on retry, either as classified by a
+// RetriableError in its chain or as a Kubernetes API failure due to an overloaded or unavailable API server
+func IsTransientError(err error) bool {
+	var retriable RetriableError
+	if errors.As(err, &retriable) {
+		return retriable.Transient()
+	}
+
+	return apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) || apierrors.IsServiceUnavailable(err)
+}
+
+// GetRetryDelay returns the delay requested before retrying a failed operation, or zero if none was requested
+func GetRetryDelay(err error) time.Duration {
+	var retriable RetriableError
+	if errors.As(err, &retriable) {
+		return retriable.RetryDelay()
+	}
+
+	if seconds, ok := apierrors.SuggestsClientDelay(err); ok {
+		return time.Duration(seconds) * time.Second
+	}
+
+	return 0
+}
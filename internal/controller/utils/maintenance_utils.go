@@ -0,0 +1,334 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+const (
+	// MaintenancePolicyConfigMapKey is the NodePool extension key that selects the maintenance policy ConfigMap of the
+	// NodePool, overriding the maintenancePolicyConfigMap of the HardwareManager
+	MaintenancePolicyConfigMapKey = "maintenancePolicyConfigMap"
+
+	// MaintenancePolicyKey is the ConfigMap key holding a MaintenancePolicy, in YAML
+	MaintenancePolicyKey = "maintenancePolicy"
+)
+
+// ConfigWaitingForWindow is the reason of the Configured condition of a NodePool with a disruptive operation that is
+// waiting for a maintenance window
+const ConfigWaitingForWindow hwmgmtv1alpha1.ConditionReason = "WaitingForMaintenanceWindow"
+
+// maxScheduleLookahead bounds the search for the next start of a maintenance window
+const maxScheduleLookahead = 5 * 366 * 24 * time.Hour
+
+// MaintenanceWindow is a recurring window, opening at the times matched by a cron schedule
+type MaintenanceWindow struct {
+	// Schedule is a cron expression with five fields: minute, hour, day of month, month and day of week
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window stays open, as a duration string
+	Duration string `json:"duration"`
+}
+
+// MaintenancePolicy defines the windows within which disruptive operations can be started
+type MaintenancePolicy struct {
+	// TimeZone is the IANA time zone of the schedules, such as America/Toronto. Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+
+	Windows []MaintenanceWindow `json:"windows"`
+}
+
+// cronField is the set of values matched by a field of a cron schedule
+type cronField uint64
+
+// cronSchedule is a parsed cron expression
+type cronSchedule struct {
+	minute, hour, dom, month, dow cronField
+	domAny, dowAny                bool
+}
+
+// parseCronField parses a comma-separated list of values, ranges and steps, such as "*/15", "1-5" or "0,30"
+func parseCronField(value string, minValue, maxValue int) (cronField, error) {
+	var field cronField
+
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step: %s", part)
+			}
+		}
+
+		low, high := minValue, maxValue
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+			if low, err = strconv.Atoi(lowPart); err != nil {
+				return 0, fmt.Errorf("invalid value: %s", part)
+			}
+			high = low
+			if isRange {
+				if high, err = strconv.Atoi(highPart); err != nil {
+					return 0, fmt.Errorf("invalid range: %s", part)
+				}
+			} else if hasStep {
+				high = maxValue
+			}
+		}
+
+		if low < minValue || high > maxValue || low > high {
+			return 0, fmt.Errorf("value out of range %d-%d: %s", minValue, maxValue, part)
+		}
+
+		for i := low; i <= high; i += step {
+			field |= 1 << i
+		}
+	}
+
+	return field, nil
+}
+
+func (f cronField) matches(value int) bool {
+	return f&(1<<value) != 0
+}
+
+// parseCronSchedule parses a cron expression with five fields. A day of week of 7 is Sunday, as is 0.
+func parseCronSchedule(spec string) (*cronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, found %d", spec, len(fields))
+	}
+
+	var s cronSchedule
+	var err error
+	for i, target := range []struct {
+		field    *cronField
+		min, max int
+	}{
+		{&s.minute, 0, 59},
+		{&s.hour, 0, 23},
+		{&s.dom, 1, 31},
+		{&s.month, 1, 12},
+		{&s.dow, 0, 7},
+	} {
+		if *target.field, err = parseCronField(fields[i], target.min, target.max); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+	}
+
+	if s.dow.matches(7) {
+		s.dow |= 1
+	}
+	s.domAny = fields[2] == "*"
+	s.dowAny = fields[4] == "*"
+
+	return &s, nil
+}
+
+// matchesDay checks the day of month and day of week fields. As with cron, when both are restricted, a day matching
+// either field matches.
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	domMatch := s.dom.matches(t.Day())
+	dowMatch := s.dow.matches(int(t.Weekday()))
+	if !s.domAny && !s.dowAny {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// next returns the first time matching the schedule that is not before t, or the zero time if there is none within
+// maxScheduleLookahead
+func (s *cronSchedule) next(t time.Time) time.Time {
+	if truncated := t.Truncate(time.Minute); truncated.Before(t) {
+		t = truncated.Add(time.Minute)
+	}
+	limit := t.Add(maxScheduleLookahead)
+
+	for t.Before(limit) {
+		var candidate time.Time
+		loc := t.Location()
+		switch {
+		case !s.month.matches(int(t.Month())):
+			candidate = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchesDay(t):
+			candidate = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !s.hour.matches(t.Hour()):
+			candidate = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case !s.minute.matches(t.Minute()):
+			candidate = t.Add(time.Minute)
+		default:
+			return t
+		}
+
+		// A daylight saving transition can map the candidate back to an earlier time, in which case the search
+		// proceeds a minute at a time
+		if !candidate.After(t) {
+			candidate = t.Add(time.Minute)
+		}
+		t = candidate
+	}
+
+	return time.Time{}
+}
+
+// maintenanceWindow is a parsed MaintenanceWindow
+type maintenanceWindow struct {
+	schedule *cronSchedule
+	duration time.Duration
+}
+
+// MaintenanceSchedule is a parsed MaintenancePolicy
+type MaintenanceSchedule struct {
+	location *time.Location
+	windows  []maintenanceWindow
+}
+
+// ParseMaintenancePolicy validates a maintenance policy, returning its schedule
+func ParseMaintenancePolicy(policy *MaintenancePolicy) (*MaintenanceSchedule, error) {
+	location := time.UTC
+	if policy.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(policy.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid timeZone %q: %w", policy.TimeZone, err)
+		}
+	}
+
+	if len(policy.Windows) == 0 {
+		return nil, fmt.Errorf("no maintenance windows defined")
+	}
+
+	schedule := &MaintenanceSchedule{location: location}
+	for _, window := range policy.Windows {
+		cron, err := parseCronSchedule(window.Schedule)
+		if err != nil {
+			return nil, err
+		}
+
+		duration, err := time.ParseDuration(window.Duration)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid duration %q for schedule %q", window.Duration, window.Schedule)
+		}
+
+		schedule.windows = append(schedule.windows, maintenanceWindow{schedule: cron, duration: duration})
+	}
+
+	return schedule, nil
+}
+
+// Check returns whether a maintenance window is open at the given time. If not, the time at which the next window
+// opens is also returned, or the zero time if no window is scheduled.
+func (m *MaintenanceSchedule) Check(now time.Time) (bool, time.Time) {
+	now = now.In(m.location)
+
+	var next time.Time
+	for _, window := range m.windows {
+		// A window that opened within its duration before now is still open
+		if start := window.schedule.next(now.Add(-window.duration).Add(time.Nanosecond)); !start.IsZero() && !start.After(now) {
+			return true, time.Time{}
+		}
+
+		if start := window.schedule.next(now); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+
+	return false, next
+}
+
+// GetMaintenancePolicyConfigMap returns the name of the maintenance policy ConfigMap selected for the NodePool, from
+// its maintenancePolicyConfigMap extension or its HardwareManager. Returns an empty string if there is none.
+func GetMaintenancePolicyConfigMap(hwmgr *pluginv1alpha1.HardwareManager, nodepool *hwmgmtv1alpha1.NodePool) string {
+	if name := nodepool.Spec.Extensions[MaintenancePolicyConfigMapKey]; name != "" {
+		return name
+	}
+	return hwmgr.Spec.MaintenancePolicyConfigMap
+}
+
+// GetMaintenanceSchedule loads the maintenance policy from the named ConfigMap
+func GetMaintenanceSchedule(ctx context.Context, c client.Client, name, namespace string) (*MaintenanceSchedule, error) {
+	cm, err := GetConfigmap(ctx, c, name, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance policy: %w", err)
+	}
+
+	policy, err := ExtractDataFromConfigMap[MaintenancePolicy](cm, MaintenancePolicyKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance policy: %w", err)
+	}
+
+	schedule, err := ParseMaintenancePolicy(&policy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance policy in ConfigMap %s: %w", name, err)
+	}
+
+	return schedule, nil
+}
+
+// WaitForMaintenanceWindow checks whether a disruptive operation can be started on the NodePool, given its
+// maintenance policy. Outside of a maintenance window, the Configured condition of the NodePool reports the wait, and
+// the returned result requeues the NodePool for when the next window opens. Returns true if the operation must wait.
+func WaitForMaintenanceWindow(
+	ctx context.Context,
+	c client.Client,
+	hwmgr *pluginv1alpha1.HardwareManager,
+	nodepool *hwmgmtv1alpha1.NodePool) (bool, ctrl.Result, error) {
+
+	name := GetMaintenancePolicyConfigMap(hwmgr, nodepool)
+	if name == "" {
+		return false, ctrl.Result{}, nil
+	}
+
+	schedule, err := GetMaintenanceSchedule(ctx, c, name, nodepool.Namespace)
+	if err != nil {
+		return true, RequeueWithMediumInterval(), err
+	}
+
+	open, next := schedule.Check(time.Now())
+	if open {
+		return false, ctrl.Result{}, nil
+	}
+
+	message := "Waiting for maintenance window"
+	result := RequeueWithLongInterval()
+	if !next.IsZero() {
+		message = fmt.Sprintf("Waiting for maintenance window, opening at %s", next.Format(time.RFC3339))
+		result = RequeueWithCustomInterval(time.Until(next))
+	}
+
+	if err := UpdateNodePoolStatusCondition(ctx, c, nodepool,
+		hwmgmtv1alpha1.Configured, ConfigWaitingForWindow, metav1.ConditionFalse, message); err != nil {
+		return true, RequeueWithShortInterval(), fmt.Errorf("failed to update status for NodePool %s: %w", nodepool.Name, err)
+	}
+
+	return true, result, nil
+}
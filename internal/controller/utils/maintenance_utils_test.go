@@ -0,0 +1,111 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MaintenanceSchedule", func() {
+	mustParse := func(policy *MaintenancePolicy) *MaintenanceSchedule {
+		schedule, err := ParseMaintenancePolicy(policy)
+		ExpectWithOffset(1, err).ToNot(HaveOccurred())
+		return schedule
+	}
+
+	It("parses cron schedules", func() {
+		schedule, err := parseCronSchedule("*/15 1-3,22 * * 1-5")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schedule.minute.matches(45)).To(BeTrue())
+		Expect(schedule.minute.matches(50)).To(BeFalse())
+		Expect(schedule.hour.matches(2)).To(BeTrue())
+		Expect(schedule.hour.matches(22)).To(BeTrue())
+		Expect(schedule.hour.matches(4)).To(BeFalse())
+		Expect(schedule.dow.matches(0)).To(BeFalse())
+
+		schedule, err = parseCronSchedule("0 0 * * 7")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schedule.dow.matches(0)).To(BeTrue())
+
+		for _, spec := range []string{"0 0 * *", "60 0 * * *", "0 0 0 * *", "0 0 * * mon", "*/0 0 * * *", "0 5-2 * * *"} {
+			_, err := parseCronSchedule(spec)
+			Expect(err).To(HaveOccurred(), spec)
+		}
+	})
+
+	It("finds the next start of a schedule", func() {
+		schedule, err := parseCronSchedule("30 2 * * 6")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Wednesday
+		now := time.Date(2026, time.October, 14, 12, 0, 30, 0, time.UTC)
+		Expect(schedule.next(now)).To(Equal(time.Date(2026, time.October, 17, 2, 30, 0, 0, time.UTC)))
+		Expect(schedule.next(time.Date(2026, time.October, 17, 2, 30, 0, 0, time.UTC))).To(
+			Equal(time.Date(2026, time.October, 17, 2, 30, 0, 0, time.UTC)))
+
+		// Day of month or day of week, when both are restricted
+		schedule, err = parseCronSchedule("0 0 1 * 6")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schedule.next(time.Date(2026, time.October, 26, 0, 0, 0, 0, time.UTC))).To(
+			Equal(time.Date(2026, time.October, 31, 0, 0, 0, 0, time.UTC)))
+		Expect(schedule.next(time.Date(2026, time.October, 31, 0, 1, 0, 0, time.UTC))).To(
+			Equal(time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)))
+
+		schedule, err = parseCronSchedule("0 0 31 2 *")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schedule.next(now)).To(BeZero())
+	})
+
+	It("checks whether a window is open", func() {
+		schedule := mustParse(&MaintenancePolicy{
+			TimeZone: "America/Toronto",
+			Windows: []MaintenanceWindow{
+				{Schedule: "0 22 * * 6", Duration: "6h"},
+				{Schedule: "0 1 * * 3", Duration: "1h"},
+			},
+		})
+		toronto, err := time.LoadLocation("America/Toronto")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Sunday, within the window opened on Saturday evening
+		open, _ := schedule.Check(time.Date(2026, time.October, 18, 3, 59, 0, 0, toronto))
+		Expect(open).To(BeTrue())
+
+		open, next := schedule.Check(time.Date(2026, time.October, 18, 4, 0, 0, 0, toronto))
+		Expect(open).To(BeFalse())
+		Expect(next.Equal(time.Date(2026, time.October, 21, 1, 0, 0, 0, toronto))).To(BeTrue())
+
+		// The check is made in the time zone of the policy
+		open, _ = schedule.Check(time.Date(2026, time.October, 18, 3, 0, 0, 0, time.UTC))
+		Expect(open).To(BeTrue())
+	})
+
+	It("rejects invalid policies", func() {
+		for _, policy := range []MaintenancePolicy{
+			{},
+			{TimeZone: "Mars/Olympus", Windows: []MaintenanceWindow{{Schedule: "0 0 * * *", Duration: "1h"}}},
+			{Windows: []MaintenanceWindow{{Schedule: "0 0 * *", Duration: "1h"}}},
+			{Windows: []MaintenanceWindow{{Schedule: "0 0 * * *", Duration: "0s"}}},
+		} {
+			_, err := ParseMaintenancePolicy(&policy)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+})
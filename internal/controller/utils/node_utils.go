@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/google/uuid"
 	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
@@ -66,6 +67,45 @@ func FindNodeInList(nodelist hwmgmtv1alpha1.NodeList, hwMgrId, nodeId string) st
 	return ""
 }
 
+// FindNodeDrift compares the nodes of a NodePool with the nodes allocated to it by the hardware manager, with the key
+// function identifying the allocated node of each Node CR. Returns the names of the NodePool nodes that are no longer
+// allocated, along with the allocated nodes that have no Node CR.
+func FindNodeDrift(
+	nodeNames []string,
+	nodelist *hwmgmtv1alpha1.NodeList,
+	allocated []string,
+	key func(node *hwmgmtv1alpha1.Node) string) (missing, unexpected []string) {
+
+	allocatedSet := make(map[string]bool, len(allocated))
+	for _, id := range allocated {
+		allocatedSet[id] = true
+	}
+
+	nodes := make(map[string]*hwmgmtv1alpha1.Node, len(nodelist.Items))
+	known := make(map[string]bool, len(nodelist.Items))
+	for i := range nodelist.Items {
+		node := &nodelist.Items[i]
+		nodes[node.Name] = node
+		known[key(node)] = true
+	}
+
+	for _, nodename := range nodeNames {
+		if node, exists := nodes[nodename]; !exists || !allocatedSet[key(node)] {
+			missing = append(missing, nodename)
+		}
+	}
+
+	for _, id := range allocated {
+		if !known[id] {
+			unexpected = append(unexpected, id)
+		}
+	}
+
+	slices.Sort(missing)
+	slices.Sort(unexpected)
+	return
+}
+
 // GetChildNodes gets a list of nodes allocated to a NodePool
 func GetChildNodes(
 	ctx context.Context,
@@ -89,15 +129,16 @@ func GetChildNodes(
 	return nodelist, nil
 }
 
-// FindNodeUpdateInProgress scans the nodelist to find the first node with jobId annotation
-func FindNodeUpdateInProgress(nodelist *hwmgmtv1alpha1.NodeList) *hwmgmtv1alpha1.Node {
-	for _, node := range nodelist.Items {
-		if GetJobId(&node) != "" {
-			return &node
+// FindNodesUpdateInProgress scans the nodelist to find the nodes with a jobId annotation
+func FindNodesUpdateInProgress(nodelist *hwmgmtv1alpha1.NodeList) []*hwmgmtv1alpha1.Node {
+	var nodes []*hwmgmtv1alpha1.Node
+	for i := range nodelist.Items {
+		if GetJobId(&nodelist.Items[i]) != "" {
+			nodes = append(nodes, &nodelist.Items[i])
 		}
 	}
 
-	return nil
+	return nodes
 }
 
 // FindNextNodeToUpdate scans the nodelist to find the first node with stale HwProfile
@@ -0,0 +1,73 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+var _ = Describe("FindNodeDrift", func() {
+	byNodeId := func(node *hwmgmtv1alpha1.Node) string { return node.Spec.HwMgrNodeId }
+
+	newNode := func(name, nodeId string) hwmgmtv1alpha1.Node {
+		return hwmgmtv1alpha1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       hwmgmtv1alpha1.NodeSpec{HwMgrNodeId: nodeId},
+		}
+	}
+
+	var nodelist *hwmgmtv1alpha1.NodeList
+
+	BeforeEach(func() {
+		nodelist = &hwmgmtv1alpha1.NodeList{Items: []hwmgmtv1alpha1.Node{
+			newNode("node-a", "id-a"),
+			newNode("node-b", "id-b"),
+		}}
+	})
+
+	It("reports no drift when the allocation matches", func() {
+		missing, unexpected := FindNodeDrift([]string{"node-a", "node-b"}, nodelist, []string{"id-b", "id-a"}, byNodeId)
+		Expect(missing).To(BeEmpty())
+		Expect(unexpected).To(BeEmpty())
+	})
+
+	It("reports nodes that are no longer allocated", func() {
+		missing, unexpected := FindNodeDrift([]string{"node-a", "node-b"}, nodelist, []string{"id-a"}, byNodeId)
+		Expect(missing).To(Equal([]string{"node-b"}))
+		Expect(unexpected).To(BeEmpty())
+
+		missing, _ = FindNodeDrift([]string{"node-a", "node-b"}, nodelist, nil, byNodeId)
+		Expect(missing).To(Equal([]string{"node-a", "node-b"}))
+	})
+
+	It("reports nodes whose Node CR was deleted", func() {
+		nodelist.Items = nodelist.Items[:1]
+		missing, unexpected := FindNodeDrift([]string{"node-a", "node-b"}, nodelist, []string{"id-a", "id-b"}, byNodeId)
+		Expect(missing).To(Equal([]string{"node-b"}))
+		Expect(unexpected).To(Equal([]string{"id-b"}))
+	})
+
+	It("reports allocated nodes without a Node CR", func() {
+		missing, unexpected := FindNodeDrift([]string{"node-a", "node-b"}, nodelist, []string{"id-c", "id-a", "id-b"}, byNodeId)
+		Expect(missing).To(BeEmpty())
+		Expect(unexpected).To(Equal([]string{"id-c"}))
+	})
+})
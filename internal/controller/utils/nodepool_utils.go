@@ -19,10 +19,13 @@ package utils
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -31,12 +34,163 @@ import (
 const (
 	NodepoolFinalizer = "oran-hwmgr-plugin/nodepool-finalizer"
 	ResourceTypeIdKey = "resourceTypeId"
+
+	// NodePoolSpecHwMgrIdKey is the field index of NodePools by spec.hwMgrId
+	NodePoolSpecHwMgrIdKey = "spec.hwMgrId"
+
+	// NodeGroupHwMgrIdKeyPrefix prefixes the NodePool extension keys that assign a nodegroup to a hardware manager
+	// other than spec.hwMgrId, as hwMgrId.<nodegroup name>
+	NodeGroupHwMgrIdKeyPrefix = "hwMgrId."
+
+	// ParentNodePoolLabel identifies the NodePool from which a per-hardware manager child NodePool was created
+	ParentNodePoolLabel = "hwmgr-plugin.oran.openshift.io/parent-nodepool"
+
+	// ScaleInPolicyKey is the NodePool extension key that selects the ScaleInPolicy for removing nodes
+	ScaleInPolicyKey = "scaleInPolicy"
+
+	// RemoveNodeAnnotation marks a Node as a preferred candidate for removal when its nodegroup is scaled in
+	RemoveNodeAnnotation = "hwmgr-plugin.oran.openshift.io/remove"
+
+	// ProvisioningTimeoutKey is the NodePool extension key that sets the time allowed for provisioning or configuring
+	// the NodePool, as a duration string
+	ProvisioningTimeoutKey = "hardwareProvisioningTimeout"
+
+	// RetryAnnotation requests a retry of a failed or timed out NodePool. Each new value is handled once, and is
+	// then recorded in LastRetryAnnotation.
+	RetryAnnotation     = "hwmgr-plugin.oran.openshift.io/retry"
+	LastRetryAnnotation = "hwmgr-plugin.oran.openshift.io/lastRetry"
+
+	// ForceDeleteAnnotation allows a NodePool to be deleted without confirmation that its resources were released
+	ForceDeleteAnnotation = "hwmgr-plugin.oran.openshift.io/force-delete"
+)
+
+// NodePoolDeprovisioning is the NodePool condition that reports the progress of the release of its resources on deletion
+const NodePoolDeprovisioning hwmgmtv1alpha1.ConditionType = "Deprovisioning"
+
+// NodePoolScaled is the NodePool condition that reports the progress of a change to the size of its nodegroups
+const NodePoolScaled hwmgmtv1alpha1.ConditionType = "Scaled"
+
+// NodePoolInSync is the NodePool condition that reports whether a provisioned NodePool matches its allocation in the
+// hardware manager
+const NodePoolInSync hwmgmtv1alpha1.ConditionType = "InSync"
+
+// Reasons for the InSync condition
+const (
+	NodePoolSynced        hwmgmtv1alpha1.ConditionReason = "Synced"
+	NodePoolDriftDetected hwmgmtv1alpha1.ConditionReason = "DriftDetected"
 )
 
+// ScaleInPolicy determines which nodes are removed from a nodegroup when it is scaled in
+type ScaleInPolicy string
+
+const (
+	// ScaleInPolicyNewest removes the most recently allocated nodes first
+	ScaleInPolicyNewest ScaleInPolicy = "Newest"
+	// ScaleInPolicyOldest removes the least recently allocated nodes first
+	ScaleInPolicyOldest ScaleInPolicy = "Oldest"
+)
+
+// GetScaleInPolicy returns the scale-in policy of the NodePool, which defaults to ScaleInPolicyNewest
+func GetScaleInPolicy(nodepool *hwmgmtv1alpha1.NodePool) (ScaleInPolicy, error) {
+	policy := ScaleInPolicy(nodepool.Spec.Extensions[ScaleInPolicyKey])
+	switch policy {
+	case "":
+		return ScaleInPolicyNewest, nil
+	case ScaleInPolicyNewest, ScaleInPolicyOldest:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid %s extension: %s", ScaleInPolicyKey, policy)
+	}
+}
+
+// SelectNodesForRemoval chooses count nodes to remove from a nodegroup being scaled in. Nodes annotated with
+// RemoveNodeAnnotation are selected first, with the remainder chosen according to the scale-in policy of the NodePool.
+func SelectNodesForRemoval(
+	nodepool *hwmgmtv1alpha1.NodePool,
+	nodes []hwmgmtv1alpha1.Node,
+	count int) ([]hwmgmtv1alpha1.Node, error) {
+
+	policy, err := GetScaleInPolicy(nodepool)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := slices.Clone(nodes)
+	slices.SortStableFunc(candidates, func(a, b hwmgmtv1alpha1.Node) int {
+		_, aMarked := a.Annotations[RemoveNodeAnnotation]
+		_, bMarked := b.Annotations[RemoveNodeAnnotation]
+		if aMarked != bMarked {
+			if aMarked {
+				return -1
+			}
+			return 1
+		}
+
+		var order int
+		if policy == ScaleInPolicyOldest {
+			order = a.CreationTimestamp.Compare(b.CreationTimestamp.Time)
+		} else {
+			order = b.CreationTimestamp.Compare(a.CreationTimestamp.Time)
+		}
+		if order != 0 {
+			return order
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+	return candidates[:max(count, 0)], nil
+}
+
 func GetResourceTypeId(nodepool *hwmgmtv1alpha1.NodePool) string {
 	return nodepool.Spec.Extensions[ResourceTypeIdKey]
 }
 
+// GetNodeGroupHwMgrId returns the hardware manager assigned to the nodegroup, which is spec.hwMgrId unless overridden
+// by a hwMgrId.<nodegroup name> extension
+func GetNodeGroupHwMgrId(nodepool *hwmgmtv1alpha1.NodePool, groupname string) string {
+	if hwMgrId := nodepool.Spec.Extensions[NodeGroupHwMgrIdKeyPrefix+groupname]; hwMgrId != "" {
+		return hwMgrId
+	}
+	return nodepool.Spec.HwMgrId
+}
+
+// ValidateNodePoolSpec performs the static validation of a NodePool spec, checking that each nodegroup has a unique
+// name and a positive size, and that a hardware manager is selected
+func ValidateNodePoolSpec(nodepool *hwmgmtv1alpha1.NodePool) field.ErrorList {
+	var errs field.ErrorList
+
+	if nodepool.Spec.HwMgrId == "" {
+		errs = append(errs, field.Required(field.NewPath("spec", "hwMgrId"), "a hardware manager must be selected"))
+	}
+
+	nodeGroupPath := field.NewPath("spec", "nodeGroup")
+	names := make(map[string]bool)
+	for i, nodegroup := range nodepool.Spec.NodeGroup {
+		path := nodeGroupPath.Index(i)
+		name := nodegroup.NodePoolData.Name
+
+		if names[name] {
+			errs = append(errs, field.Duplicate(path.Child("nodePoolData", "name"), name))
+		}
+		names[name] = true
+
+		if nodegroup.Size <= 0 {
+			errs = append(errs, field.Invalid(path.Child("size"), nodegroup.Size, "must be greater than zero"))
+		}
+	}
+
+	return errs
+}
+
+// IsNodePoolForceDelete checks whether the NodePool is annotated for deletion without confirmed release
+func IsNodePoolForceDelete(nodepool *hwmgmtv1alpha1.NodePool) bool {
+	_, exists := nodepool.GetAnnotations()[ForceDeleteAnnotation]
+	return exists
+}
+
 func GetNodePoolProvisionedCondition(nodepool *hwmgmtv1alpha1.NodePool) *metav1.Condition {
 	return meta.FindStatusCondition(
 		nodepool.Status.Conditions,
@@ -100,6 +254,49 @@ func UpdateNodePoolStatusCondition(
 	return nil
 }
 
+// ResetNodePoolStatusCondition replaces a NodePool condition, restarting its LastTransitionTime even if the status
+// is unchanged
+func ResetNodePoolStatusCondition(
+	ctx context.Context,
+	c client.Client,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	conditionType hwmgmtv1alpha1.ConditionType,
+	conditionReason hwmgmtv1alpha1.ConditionReason,
+	conditionStatus metav1.ConditionStatus,
+	message string) error {
+
+	meta.RemoveStatusCondition(&nodepool.Status.Conditions, string(conditionType))
+	SetStatusCondition(&nodepool.Status.Conditions,
+		string(conditionType),
+		string(conditionReason),
+		conditionStatus,
+		message)
+
+	// nolint: wrapcheck
+	err := RetryOnConflictOrRetriable(retry.DefaultRetry, func() error {
+		newNodepool := &hwmgmtv1alpha1.NodePool{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(nodepool), newNodepool); err != nil {
+			return err
+		}
+		meta.RemoveStatusCondition(&newNodepool.Status.Conditions, string(conditionType))
+		SetStatusCondition(&newNodepool.Status.Conditions,
+			string(conditionType),
+			string(conditionReason),
+			conditionStatus,
+			message)
+		if err := c.Status().Update(ctx, newNodepool); err != nil {
+			return err
+		}
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to reset nodepool condition: %s, %w", nodepool.Name, err)
+	}
+
+	return nil
+}
+
 func UpdateNodePoolProperties(
 	ctx context.Context,
 	c client.Client,
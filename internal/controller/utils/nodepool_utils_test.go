@@ -0,0 +1,100 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+func TestUtils(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Utils Suite")
+}
+
+var _ = Describe("SelectNodesForRemoval", func() {
+	created := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	newNode := func(name string, age time.Duration, annotations map[string]string) hwmgmtv1alpha1.Node {
+		return hwmgmtv1alpha1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(created.Add(-age)),
+				Annotations:       annotations,
+			},
+		}
+	}
+
+	newNodePool := func(policy string) *hwmgmtv1alpha1.NodePool {
+		nodepool := &hwmgmtv1alpha1.NodePool{}
+		if policy != "" {
+			nodepool.Spec.Extensions = map[string]string{ScaleInPolicyKey: policy}
+		}
+		return nodepool
+	}
+
+	names := func(nodes []hwmgmtv1alpha1.Node) []string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, node.Name)
+		}
+		return result
+	}
+
+	nodes := []hwmgmtv1alpha1.Node{
+		newNode("old", 3*time.Hour, nil),
+		newNode("new", time.Hour, nil),
+		newNode("middle", 2*time.Hour, nil),
+	}
+
+	It("removes the newest nodes by default", func() {
+		selected, err := SelectNodesForRemoval(newNodePool(""), nodes, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(selected)).To(Equal([]string{"new", "middle"}))
+	})
+
+	It("removes the oldest nodes with the Oldest policy", func() {
+		selected, err := SelectNodesForRemoval(newNodePool(string(ScaleInPolicyOldest)), nodes, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(selected)).To(Equal([]string{"old", "middle"}))
+	})
+
+	It("removes annotated nodes first", func() {
+		marked := append(nodes, newNode("marked", 4*time.Hour, map[string]string{RemoveNodeAnnotation: ""}))
+		selected, err := SelectNodesForRemoval(newNodePool(""), marked, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names(selected)).To(Equal([]string{"marked", "new"}))
+	})
+
+	It("limits the selection to the available nodes", func() {
+		selected, err := SelectNodesForRemoval(newNodePool(""), nodes, 5)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selected).To(HaveLen(3))
+	})
+
+	It("rejects an invalid policy", func() {
+		_, err := SelectNodesForRemoval(newNodePool("Random"), nodes, 1)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,320 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+const (
+	// RolloutMaxUnavailableKey is the NodePool extension key that sets how many nodes of a nodegroup have their
+	// hardware profile updated at a time, as a count or a percentage of the nodegroup size. It can be set for a single
+	// nodegroup as rolloutMaxUnavailable.<nodegroup name>.
+	RolloutMaxUnavailableKey = "rolloutMaxUnavailable"
+
+	// RolloutOrderKey is the NodePool extension key that lists the nodegroups in the order their hardware profile
+	// updates are rolled out, separated by commas. Nodegroups that are not listed follow, in the order of the spec.
+	RolloutOrderKey = "rolloutOrder"
+
+	// RolloutBatchPauseKey is the NodePool extension key that sets the time to wait after a batch of hardware profile
+	// updates completes before starting the next batch, as a duration string
+	RolloutBatchPauseKey = "rolloutBatchPause"
+
+	// RolloutFailurePolicyKey is the NodePool extension key that selects the RolloutFailurePolicy applied when a
+	// hardware profile update fails
+	RolloutFailurePolicyKey = "rolloutFailurePolicy"
+
+	// RolloutBatchCompletedAnnotation records the time at which the last batch of hardware profile updates of a
+	// NodePool completed
+	RolloutBatchCompletedAnnotation = "hwmgr-plugin.oran.openshift.io/rolloutBatchCompleted"
+
+	// RolloutRollbackAnnotation marks a NodePool whose failed rollout is being rolled back, recording the failure
+	RolloutRollbackAnnotation = "hwmgr-plugin.oran.openshift.io/rollback"
+
+	// PreviousHwProfileAnnotation records the hardware profile of a Node before the rollout in progress, to which the
+	// node is restored if the rollout is rolled back
+	PreviousHwProfileAnnotation = "hwmgr-plugin.oran.openshift.io/previousHwProfile"
+
+	// ProfileUpdateFailedAnnotation records the failure of the hardware profile update of a Node, which is skipped for
+	// the rest of the rollout
+	ProfileUpdateFailedAnnotation = "hwmgr-plugin.oran.openshift.io/profileUpdateFailed"
+)
+
+// RolloutFailurePolicy determines how a rollout proceeds when the hardware profile update of a node fails
+type RolloutFailurePolicy string
+
+const (
+	// RolloutFailurePolicyHalt stops the rollout, leaving the nodes already updated on the new profile
+	RolloutFailurePolicyHalt RolloutFailurePolicy = "Halt"
+	// RolloutFailurePolicyRollback stops the rollout and restores the nodes already updated to their previous profile
+	RolloutFailurePolicyRollback RolloutFailurePolicy = "Rollback"
+	// RolloutFailurePolicyContinue skips the failed nodes and updates the remaining nodes, reporting a partial result
+	RolloutFailurePolicyContinue RolloutFailurePolicy = "Continue"
+)
+
+// DefaultRolloutMaxUnavailable updates the nodes of a nodegroup one at a time
+var DefaultRolloutMaxUnavailable = intstr.FromInt32(1)
+
+// RolloutStrategy controls how a hardware profile change is rolled out to the nodes of a NodePool. The nodes are
+// updated in batches, one nodegroup at a time. A batch holds up to MaxUnavailable nodes of the nodegroup, and the next
+// batch is started once every update of the batch has completed and BatchPause has elapsed.
+type RolloutStrategy struct {
+	MaxUnavailable          intstr.IntOrString
+	NodeGroupMaxUnavailable map[string]intstr.IntOrString
+	Order                   []string
+	BatchPause              time.Duration
+	FailurePolicy           RolloutFailurePolicy
+}
+
+// parseMaxUnavailable parses a maxUnavailable extension, which must be a positive count or percentage
+func parseMaxUnavailable(key, value string) (intstr.IntOrString, error) {
+	maxUnavailable := intstr.Parse(value)
+	if maxUnavailable.Type == intstr.Int {
+		if maxUnavailable.IntVal <= 0 {
+			return maxUnavailable, fmt.Errorf("invalid %s extension: %s", key, value)
+		}
+		return maxUnavailable, nil
+	}
+
+	percent, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if !strings.HasSuffix(value, "%") || err != nil || percent <= 0 || percent > 100 {
+		return maxUnavailable, fmt.Errorf("invalid %s extension: %s", key, value)
+	}
+	return maxUnavailable, nil
+}
+
+// GetRolloutStrategy returns the rollout strategy of the NodePool, from its rollout extensions. By default, the nodes
+// are updated one at a time, without pause, with the nodegroups in the order of the spec, and the rollout halts on
+// failure.
+func GetRolloutStrategy(nodepool *hwmgmtv1alpha1.NodePool) (*RolloutStrategy, error) {
+	strategy := &RolloutStrategy{
+		MaxUnavailable:          DefaultRolloutMaxUnavailable,
+		NodeGroupMaxUnavailable: make(map[string]intstr.IntOrString),
+		FailurePolicy:           RolloutFailurePolicyHalt,
+	}
+
+	if value := nodepool.Spec.Extensions[RolloutMaxUnavailableKey]; value != "" {
+		maxUnavailable, err := parseMaxUnavailable(RolloutMaxUnavailableKey, value)
+		if err != nil {
+			return nil, err
+		}
+		strategy.MaxUnavailable = maxUnavailable
+	}
+
+	groupnames := make(map[string]bool)
+	for _, nodegroup := range nodepool.Spec.NodeGroup {
+		groupname := nodegroup.NodePoolData.Name
+		groupnames[groupname] = true
+
+		key := RolloutMaxUnavailableKey + "." + groupname
+		if value := nodepool.Spec.Extensions[key]; value != "" {
+			maxUnavailable, err := parseMaxUnavailable(key, value)
+			if err != nil {
+				return nil, err
+			}
+			strategy.NodeGroupMaxUnavailable[groupname] = maxUnavailable
+		}
+	}
+
+	if value := nodepool.Spec.Extensions[RolloutOrderKey]; value != "" {
+		for _, groupname := range strings.Split(value, ",") {
+			groupname = strings.TrimSpace(groupname)
+			if !groupnames[groupname] || slices.Contains(strategy.Order, groupname) {
+				return nil, fmt.Errorf("invalid %s extension: %s", RolloutOrderKey, value)
+			}
+			strategy.Order = append(strategy.Order, groupname)
+		}
+	}
+
+	if value := nodepool.Spec.Extensions[RolloutBatchPauseKey]; value != "" {
+		pause, err := time.ParseDuration(value)
+		if err != nil || pause < 0 {
+			return nil, fmt.Errorf("invalid %s extension: %s", RolloutBatchPauseKey, value)
+		}
+		strategy.BatchPause = pause
+	}
+
+	switch policy := RolloutFailurePolicy(nodepool.Spec.Extensions[RolloutFailurePolicyKey]); policy {
+	case "":
+	case RolloutFailurePolicyHalt, RolloutFailurePolicyRollback, RolloutFailurePolicyContinue:
+		strategy.FailurePolicy = policy
+	default:
+		return nil, fmt.Errorf("invalid %s extension: %s", RolloutFailurePolicyKey, policy)
+	}
+
+	return strategy, nil
+}
+
+// OrderedNodeGroups returns the nodegroups of the NodePool in rollout order
+func (s *RolloutStrategy) OrderedNodeGroups(nodepool *hwmgmtv1alpha1.NodePool) []hwmgmtv1alpha1.NodeGroup {
+	nodegroups := slices.Clone(nodepool.Spec.NodeGroup)
+	slices.SortStableFunc(nodegroups, func(a, b hwmgmtv1alpha1.NodeGroup) int {
+		aIndex := slices.Index(s.Order, a.NodePoolData.Name)
+		bIndex := slices.Index(s.Order, b.NodePoolData.Name)
+		switch {
+		case aIndex == bIndex:
+			return 0
+		case aIndex < 0:
+			return 1
+		case bIndex < 0:
+			return -1
+		default:
+			return aIndex - bIndex
+		}
+	})
+	return nodegroups
+}
+
+// GetBatchSize returns the number of nodes of the nodegroup that are updated at a time. A percentage is rounded down,
+// with at least one node updated per batch.
+func (s *RolloutStrategy) GetBatchSize(nodegroup hwmgmtv1alpha1.NodeGroup) int {
+	maxUnavailable, exists := s.NodeGroupMaxUnavailable[nodegroup.NodePoolData.Name]
+	if !exists {
+		maxUnavailable = s.MaxUnavailable
+	}
+
+	size, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, nodegroup.Size, false)
+	if err != nil {
+		return 1
+	}
+	return max(size, 1)
+}
+
+// GetBatchPauseRemaining returns the time left before the next batch of updates can be started, given the completion
+// time of the last batch recorded in the RolloutBatchCompletedAnnotation of the NodePool
+func (s *RolloutStrategy) GetBatchPauseRemaining(nodepool *hwmgmtv1alpha1.NodePool, now time.Time) time.Duration {
+	if s.BatchPause == 0 {
+		return 0
+	}
+
+	completed, err := time.Parse(time.RFC3339, nodepool.GetAnnotations()[RolloutBatchCompletedAnnotation])
+	if err != nil {
+		return 0
+	}
+
+	return max(completed.Add(s.BatchPause).Sub(now), 0)
+}
+
+// selectBatch selects up to the batch size of nodes of the first nodegroup in rollout order with nodes matching the
+// filter, in name order
+func (s *RolloutStrategy) selectBatch(
+	nodepool *hwmgmtv1alpha1.NodePool,
+	nodelist *hwmgmtv1alpha1.NodeList,
+	filter func(hwmgmtv1alpha1.NodeGroup, *hwmgmtv1alpha1.Node) bool) (hwmgmtv1alpha1.NodeGroup, []*hwmgmtv1alpha1.Node) {
+
+	for _, nodegroup := range s.OrderedNodeGroups(nodepool) {
+		var batch []*hwmgmtv1alpha1.Node
+		for i := range nodelist.Items {
+			node := &nodelist.Items[i]
+			if node.Spec.GroupName == nodegroup.NodePoolData.Name && filter(nodegroup, node) {
+				batch = append(batch, node)
+			}
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
+		slices.SortFunc(batch, func(a, b *hwmgmtv1alpha1.Node) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		return nodegroup, batch[:min(len(batch), s.GetBatchSize(nodegroup))]
+	}
+
+	return hwmgmtv1alpha1.NodeGroup{}, nil
+}
+
+// SelectRolloutBatch selects the next batch of nodes to update, from the first nodegroup in rollout order with nodes
+// that do not have the hardware profile of the nodegroup. Nodes with a failed update are skipped. Returns the profile
+// of the nodegroup and the selected nodes, or no nodes once all nodegroups are up to date.
+func (s *RolloutStrategy) SelectRolloutBatch(
+	nodepool *hwmgmtv1alpha1.NodePool,
+	nodelist *hwmgmtv1alpha1.NodeList) (string, []*hwmgmtv1alpha1.Node) {
+
+	nodegroup, batch := s.selectBatch(nodepool, nodelist,
+		func(nodegroup hwmgmtv1alpha1.NodeGroup, node *hwmgmtv1alpha1.Node) bool {
+			_, failed := node.GetAnnotations()[ProfileUpdateFailedAnnotation]
+			return !failed && node.Spec.HwProfile != nodegroup.NodePoolData.HwProfile
+		})
+	return nodegroup.NodePoolData.HwProfile, batch
+}
+
+// SelectRollbackBatch selects the next batch of nodes to restore to the profile recorded in their
+// PreviousHwProfileAnnotation, or no nodes once the rollback is complete
+func (s *RolloutStrategy) SelectRollbackBatch(
+	nodepool *hwmgmtv1alpha1.NodePool,
+	nodelist *hwmgmtv1alpha1.NodeList) []*hwmgmtv1alpha1.Node {
+
+	_, batch := s.selectBatch(nodepool, nodelist,
+		func(_ hwmgmtv1alpha1.NodeGroup, node *hwmgmtv1alpha1.Node) bool {
+			previous, exists := node.GetAnnotations()[PreviousHwProfileAnnotation]
+			return exists && node.Spec.HwProfile != previous
+		})
+	return batch
+}
+
+// FindNodesUpdated returns the nodes that have completed an update from the profile recorded in their
+// PreviousHwProfileAnnotation
+func FindNodesUpdated(nodelist *hwmgmtv1alpha1.NodeList) []*hwmgmtv1alpha1.Node {
+	var nodes []*hwmgmtv1alpha1.Node
+	for i := range nodelist.Items {
+		node := &nodelist.Items[i]
+		previous, exists := node.GetAnnotations()[PreviousHwProfileAnnotation]
+		if exists && node.Status.HwProfile != previous && GetJobId(node) == "" {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+// FindNodesUpdateFailed returns the nodes with a failed update, annotated with ProfileUpdateFailedAnnotation
+func FindNodesUpdateFailed(nodelist *hwmgmtv1alpha1.NodeList) []*hwmgmtv1alpha1.Node {
+	var nodes []*hwmgmtv1alpha1.Node
+	for i := range nodelist.Items {
+		if _, failed := nodelist.Items[i].GetAnnotations()[ProfileUpdateFailedAnnotation]; failed {
+			nodes = append(nodes, &nodelist.Items[i])
+		}
+	}
+
+	return nodes
+}
+
+func SetRolloutBatchCompleted(object client.Object, completed time.Time) {
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[RolloutBatchCompletedAnnotation] = completed.UTC().Format(time.RFC3339)
+	object.SetAnnotations(annotations)
+}
+
+func ClearRolloutBatchCompleted(object client.Object) {
+	annotations := object.GetAnnotations()
+	if annotations != nil {
+		delete(annotations, RolloutBatchCompletedAnnotation)
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+var _ = Describe("RolloutStrategy", func() {
+	var nodepool *hwmgmtv1alpha1.NodePool
+
+	newNodeGroup := func(name, hwprofile string, size int) hwmgmtv1alpha1.NodeGroup {
+		return hwmgmtv1alpha1.NodeGroup{
+			NodePoolData: hwmgmtv1alpha1.NodePoolData{Name: name, HwProfile: hwprofile},
+			Size:         size,
+		}
+	}
+
+	newNodeList := func(groupname, hwprofile string, count int) *hwmgmtv1alpha1.NodeList {
+		nodelist := &hwmgmtv1alpha1.NodeList{}
+		for i := count - 1; i >= 0; i-- {
+			nodelist.Items = append(nodelist.Items, hwmgmtv1alpha1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%d", groupname, i)},
+				Spec:       hwmgmtv1alpha1.NodeSpec{GroupName: groupname, HwProfile: hwprofile},
+			})
+		}
+		return nodelist
+	}
+
+	BeforeEach(func() {
+		nodepool = &hwmgmtv1alpha1.NodePool{
+			Spec: hwmgmtv1alpha1.NodePoolSpec{
+				NodeGroup: []hwmgmtv1alpha1.NodeGroup{
+					newNodeGroup("master", "profile-b", 3),
+					newNodeGroup("worker", "profile-b", 10),
+				},
+				Extensions: map[string]string{},
+			},
+		}
+	})
+
+	It("defaults to one node at a time in spec order", func() {
+		strategy, err := GetRolloutStrategy(nodepool)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strategy.GetBatchSize(nodepool.Spec.NodeGroup[1])).To(Equal(1))
+		Expect(strategy.BatchPause).To(BeZero())
+		Expect(strategy.FailurePolicy).To(Equal(RolloutFailurePolicyHalt))
+
+		nodegroups := strategy.OrderedNodeGroups(nodepool)
+		Expect(nodegroups[0].NodePoolData.Name).To(Equal("master"))
+		Expect(nodegroups[1].NodePoolData.Name).To(Equal("worker"))
+	})
+
+	It("sizes batches from a count or percentage", func() {
+		nodepool.Spec.Extensions[RolloutMaxUnavailableKey] = "25%"
+		nodepool.Spec.Extensions[RolloutMaxUnavailableKey+".master"] = "2"
+
+		strategy, err := GetRolloutStrategy(nodepool)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strategy.GetBatchSize(nodepool.Spec.NodeGroup[0])).To(Equal(2))
+		Expect(strategy.GetBatchSize(nodepool.Spec.NodeGroup[1])).To(Equal(2))
+
+		nodepool.Spec.Extensions[RolloutMaxUnavailableKey] = "5%"
+		strategy, err = GetRolloutStrategy(nodepool)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strategy.GetBatchSize(nodepool.Spec.NodeGroup[1])).To(Equal(1))
+	})
+
+	It("rejects invalid extensions", func() {
+		for key, value := range map[string]string{
+			RolloutMaxUnavailableKey:             "0",
+			RolloutMaxUnavailableKey + ".worker": "150%",
+			RolloutOrderKey:                      "worker,storage",
+			RolloutBatchPauseKey:                 "later",
+			RolloutFailurePolicyKey:              "Ignore",
+		} {
+			nodepool.Spec.Extensions = map[string]string{key: value}
+			_, err := GetRolloutStrategy(nodepool)
+			Expect(err).To(MatchError(ContainSubstring(key)))
+		}
+
+		nodepool.Spec.Extensions = map[string]string{RolloutOrderKey: "worker,worker"}
+		_, err := GetRolloutStrategy(nodepool)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("orders the nodegroups", func() {
+		nodepool.Spec.NodeGroup = append(nodepool.Spec.NodeGroup, newNodeGroup("storage", "profile-b", 2))
+		nodepool.Spec.Extensions[RolloutOrderKey] = "worker, storage"
+
+		strategy, err := GetRolloutStrategy(nodepool)
+		Expect(err).ToNot(HaveOccurred())
+
+		var names []string
+		for _, nodegroup := range strategy.OrderedNodeGroups(nodepool) {
+			names = append(names, nodegroup.NodePoolData.Name)
+		}
+		Expect(names).To(Equal([]string{"worker", "storage", "master"}))
+	})
+
+	It("selects the next batch", func() {
+		nodepool.Spec.Extensions[RolloutMaxUnavailableKey] = "3"
+		nodepool.Spec.Extensions[RolloutOrderKey] = "worker"
+		strategy, err := GetRolloutStrategy(nodepool)
+		Expect(err).ToNot(HaveOccurred())
+
+		nodelist := newNodeList("master", "profile-a", 3)
+		nodelist.Items = append(nodelist.Items, newNodeList("worker", "profile-a", 4).Items...)
+
+		hwprofile, batch := strategy.SelectRolloutBatch(nodepool, nodelist)
+		Expect(hwprofile).To(Equal("profile-b"))
+		Expect(batch).To(HaveLen(3))
+		Expect(batch[0].Name).To(Equal("worker-0"))
+		Expect(batch[2].Name).To(Equal("worker-2"))
+
+		for _, node := range batch {
+			node.Spec.HwProfile = hwprofile
+		}
+		_, batch = strategy.SelectRolloutBatch(nodepool, nodelist)
+		Expect(batch).To(HaveLen(1))
+		Expect(batch[0].Name).To(Equal("worker-3"))
+
+		batch[0].Spec.HwProfile = hwprofile
+		_, batch = strategy.SelectRolloutBatch(nodepool, nodelist)
+		Expect(batch).To(HaveLen(3))
+		Expect(batch[0].Spec.GroupName).To(Equal("master"))
+
+		for _, node := range batch {
+			node.Spec.HwProfile = hwprofile
+		}
+		_, batch = strategy.SelectRolloutBatch(nodepool, nodelist)
+		Expect(batch).To(BeEmpty())
+	})
+
+	It("pauses between batches", func() {
+		nodepool.Spec.Extensions[RolloutBatchPauseKey] = "10m"
+		strategy, err := GetRolloutStrategy(nodepool)
+		Expect(err).ToNot(HaveOccurred())
+
+		now := time.Now()
+		Expect(strategy.GetBatchPauseRemaining(nodepool, now)).To(BeZero())
+
+		SetRolloutBatchCompleted(nodepool, now.Add(-4*time.Minute))
+		Expect(strategy.GetBatchPauseRemaining(nodepool, now)).To(BeNumerically("~", 6*time.Minute, time.Second))
+		Expect(strategy.GetBatchPauseRemaining(nodepool, now.Add(time.Hour))).To(BeZero())
+
+		ClearRolloutBatchCompleted(nodepool)
+		Expect(strategy.GetBatchPauseRemaining(nodepool, now)).To(BeZero())
+	})
+
+	It("selects the nodes to roll back", func() {
+		nodepool.Spec.Extensions[RolloutMaxUnavailableKey] = "2"
+		nodepool.Spec.Extensions[RolloutFailurePolicyKey] = string(RolloutFailurePolicyRollback)
+		strategy, err := GetRolloutStrategy(nodepool)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strategy.FailurePolicy).To(Equal(RolloutFailurePolicyRollback))
+
+		nodelist := newNodeList("worker", "profile-b", 4)
+		for i := range nodelist.Items {
+			nodelist.Items[i].Status.HwProfile = "profile-b"
+		}
+		Expect(FindNodesUpdated(nodelist)).To(BeEmpty())
+		Expect(strategy.SelectRollbackBatch(nodepool, nodelist)).To(BeEmpty())
+
+		// worker-3 and worker-2 were updated, and worker-1 failed and was reverted
+		for _, i := range []int{0, 1} {
+			nodelist.Items[i].Annotations = map[string]string{PreviousHwProfileAnnotation: "profile-a"}
+		}
+		nodelist.Items[2].Annotations = map[string]string{
+			PreviousHwProfileAnnotation:   "profile-a",
+			ProfileUpdateFailedAnnotation: "failed",
+		}
+		nodelist.Items[2].Spec.HwProfile = "profile-a"
+		nodelist.Items[2].Status.HwProfile = "profile-a"
+
+		updated := FindNodesUpdated(nodelist)
+		Expect(updated).To(HaveLen(2))
+		Expect(FindNodesUpdateFailed(nodelist)).To(HaveLen(1))
+
+		batch := strategy.SelectRollbackBatch(nodepool, nodelist)
+		Expect(batch).To(HaveLen(2))
+		Expect(batch[0].Name).To(Equal("worker-2"))
+		Expect(batch[1].Name).To(Equal("worker-3"))
+	})
+
+	It("skips nodes with a failed update", func() {
+		strategy, err := GetRolloutStrategy(nodepool)
+		Expect(err).ToNot(HaveOccurred())
+
+		nodelist := newNodeList("worker", "profile-a", 2)
+		nodelist.Items[1].Annotations = map[string]string{ProfileUpdateFailedAnnotation: "failed"}
+
+		_, batch := strategy.SelectRolloutBatch(nodepool, nodelist)
+		Expect(batch).To(HaveLen(1))
+		Expect(batch[0].Name).To(Equal("worker-1"))
+
+		batch[0].Spec.HwProfile = "profile-b"
+		_, batch = strategy.SelectRolloutBatch(nodepool, nodelist)
+		Expect(batch).To(BeEmpty())
+	})
+})
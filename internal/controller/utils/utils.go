@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -43,7 +44,8 @@ const (
 )
 
 const (
-	JobIdAnnotation = "hwmgr-plugin.oran.openshift.io/jobId"
+	JobIdAnnotation       = "hwmgr-plugin.oran.openshift.io/jobId"
+	DeleteJobIdAnnotation = "hwmgr-plugin.oran.openshift.io/deleteJobId"
 )
 
 func UpdateK8sCRStatus(ctx context.Context, c client.Client, object client.Object) error {
@@ -208,6 +210,35 @@ func GetSecretField(secret *corev1.Secret, fieldName string) (string, error) {
 	return string(encoded), nil
 }
 
+// GetBMCCredentials reads the username and password from a BMC credentials secret
+func GetBMCCredentials(ctx context.Context, c client.Client, name, namespace string) (username, password []byte, err error) {
+	if name == "" {
+		err = fmt.Errorf("no BMC credentials secret specified")
+		return
+	}
+
+	// The secret is read as an unstructured object to bypass the manager cache, as it may be in another namespace
+	object := &unstructured.Unstructured{}
+	object.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	if err = c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, object); err != nil {
+		err = fmt.Errorf("failed to get BMC credentials secret %s: %w", name, err)
+		return
+	}
+
+	secret := &corev1.Secret{}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, secret); err != nil {
+		err = fmt.Errorf("failed to parse BMC credentials secret %s: %w", name, err)
+		return
+	}
+
+	username, password = secret.Data["username"], secret.Data["password"]
+	if len(username) == 0 || len(password) == 0 {
+		err = fmt.Errorf("BMC credentials secret %s missing username or password", name)
+	}
+
+	return
+}
+
 func GetAdaptorIdFromHwMgrId(hwMgrId string) string {
 	fields := strings.Split(hwMgrId, ",")
 	if len(fields) == 0 {
@@ -243,6 +274,32 @@ func ClearJobId(object client.Object) {
 	}
 }
 
+func GetDeleteJobId(object client.Object) string {
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		return ""
+	}
+
+	return annotations[DeleteJobIdAnnotation]
+}
+
+func SetDeleteJobId(object client.Object, jobId string) {
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+
+	annotations[DeleteJobIdAnnotation] = jobId
+	object.SetAnnotations(annotations)
+}
+
+func ClearDeleteJobId(object client.Object) {
+	annotations := object.GetAnnotations()
+	if annotations != nil {
+		delete(annotations, DeleteJobIdAnnotation)
+	}
+}
+
 //
 // Reconciler utilities
 //
@@ -0,0 +1,165 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+const (
+	// DefaultCertDir is the directory of the serving certificate of the receiver, matching the layout of the webhook
+	// server certificate
+	DefaultCertDir = "/tmp/k8s-notification-server/serving-certs"
+
+	// PathPrefix is the prefix of the paths at which the notifications for each adaptor are received
+	PathPrefix = "/notifications"
+
+	// eventQueueSize bounds the NodePool events queued for the NodePool controller
+	eventQueueSize = 1000
+)
+
+// Options configures the notification receiver
+type Options struct {
+	// BindAddress is the address the receiver listens on
+	BindAddress string
+
+	// CertDir is the directory containing the tls.crt and tls.key files of the serving certificate
+	CertDir string
+
+	// TLSOpts are applied to the TLS configuration of the server
+	TLSOpts []func(*tls.Config)
+}
+
+// Receiver is the HTTPS server receiving push notifications from the hardware managers. Adaptors register the handlers
+// for the notifications of their hardware managers, which enqueue the reconcile of the affected NodePools.
+type Receiver struct {
+	options Options
+	logger  *slog.Logger
+	mux     *http.ServeMux
+	events  chan event.GenericEvent
+}
+
+// NewReceiver creates the notification receiver
+func NewReceiver(options Options, logger *slog.Logger) *Receiver {
+	if options.CertDir == "" {
+		options.CertDir = DefaultCertDir
+	}
+
+	return &Receiver{
+		options: options,
+		logger:  logger,
+		mux:     http.NewServeMux(),
+		events:  make(chan event.GenericEvent, eventQueueSize),
+	}
+}
+
+// Path returns the path at which the notifications for a HardwareManager of the adaptor are received
+func Path(adaptorID pluginv1alpha1.HardwareManagerAdaptorID, hwmgr string) string {
+	return fmt.Sprintf("%s/%s/%s", PathPrefix, adaptorID, hwmgr)
+}
+
+// Handle registers the handler for the given pattern, which should be under the Path of the adaptor
+func (r *Receiver) Handle(pattern string, handler http.Handler) {
+	r.mux.Handle(pattern, handler)
+}
+
+// ServeHTTP implements http.Handler
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
+}
+
+// NodePoolEvents returns the channel of the NodePools to be reconciled in response to notifications
+func (r *Receiver) NodePoolEvents() <-chan event.GenericEvent {
+	return r.events
+}
+
+// EnqueueNodePool triggers the reconcile of a NodePool. If the queue is full, the event is dropped, and the NodePool
+// is reconciled when the fallback polling interval elapses.
+func (r *Receiver) EnqueueNodePool(name types.NamespacedName) {
+	select {
+	case r.events <- event.GenericEvent{Object: &hwmgmtv1alpha1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: name.Namespace, Name: name.Name},
+	}}:
+	default:
+		r.logger.Warn("Notification event queue full, dropping NodePool event", slog.String("nodepool", name.String()))
+	}
+}
+
+// Start implements manager.Runnable, serving the notifications until the context is cancelled
+func (r *Receiver) Start(ctx context.Context) error {
+	watcher, err := certwatcher.New(
+		filepath.Join(r.options.CertDir, "tls.crt"),
+		filepath.Join(r.options.CertDir, "tls.key"))
+	if err != nil {
+		return fmt.Errorf("failed to load notification receiver certificate: %w", err)
+	}
+
+	go func() {
+		if err := watcher.Start(ctx); err != nil {
+			r.logger.Error("Notification receiver certificate watcher failed", slog.String("error", err.Error()))
+		}
+	}()
+
+	config := &tls.Config{
+		GetCertificate: watcher.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+	for _, opt := range r.options.TLSOpts {
+		opt(config)
+	}
+
+	listener, err := tls.Listen("tcp", r.options.BindAddress, config)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", r.options.BindAddress, err)
+	}
+
+	server := &http.Server{
+		Handler:           r.mux,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			r.logger.Error("Failed to shut down notification receiver", slog.String("error", err.Error()))
+		}
+	}()
+
+	r.logger.Info("Starting notification receiver", slog.String("address", r.options.BindAddress))
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("notification receiver failed: %w", err)
+	}
+
+	return nil
+}
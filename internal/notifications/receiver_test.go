@@ -0,0 +1,84 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+func TestNotifications(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Notifications Suite")
+}
+
+var _ = Describe("Notification receiver", func() {
+	var (
+		receiver *Receiver
+		server   *httptest.Server
+	)
+
+	BeforeEach(func() {
+		receiver = NewReceiver(Options{}, slog.New(slog.NewTextHandler(GinkgoWriter, nil)))
+		server = httptest.NewServer(receiver)
+		DeferCleanup(server.Close)
+	})
+
+	It("routes the notifications to the handler of the adaptor", func() {
+		receiver.Handle("POST "+Path(pluginv1alpha1.SupportedAdaptors.Dell, "{hwmgr}"),
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receiver.EnqueueNodePool(types.NamespacedName{Namespace: "oran-hwmgr-plugin", Name: r.PathValue("hwmgr")})
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+		Expect(Path(pluginv1alpha1.SupportedAdaptors.Dell, "dell-1")).To(Equal("/notifications/dell-hwmgr/dell-1"))
+
+		resp, err := http.Post(server.URL+"/notifications/dell-hwmgr/dell-1", "application/json", nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+		var evt event.GenericEvent
+		Expect(receiver.NodePoolEvents()).To(Receive(&evt))
+		Expect(evt.Object.GetNamespace()).To(Equal("oran-hwmgr-plugin"))
+		Expect(evt.Object.GetName()).To(Equal("dell-1"))
+
+		// Paths without a registered handler are rejected
+		resp, err = http.Post(server.URL+"/notifications/loopback/loopback-1", "application/json", nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		Expect(receiver.NodePoolEvents()).NotTo(Receive())
+	})
+
+	It("drops events when the queue is full", func() {
+		for i := 0; i < eventQueueSize+1; i++ {
+			receiver.EnqueueNodePool(types.NamespacedName{Namespace: "oran-hwmgr-plugin", Name: "nodepool"})
+		}
+		Expect(receiver.NodePoolEvents()).To(HaveLen(eventQueueSize))
+	})
+})
@@ -0,0 +1,257 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+// Policy defines how the readiness of the individual HardwareManagers is aggregated
+type Policy string
+
+const (
+	// PolicyAll requires every HardwareManager to be ready
+	PolicyAll Policy = "all"
+	// PolicyAny requires at least one HardwareManager to be ready
+	PolicyAny Policy = "any"
+	// PolicyNone reports the readiness of each HardwareManager without making the plugin readiness depend on it
+	PolicyNone Policy = "none"
+)
+
+const (
+	// CheckName is the name of the aggregate readiness check, and the sub-path of the per-HardwareManager checks
+	CheckName = "hwmgr"
+
+	DefaultInterval     = 1 * time.Minute
+	DefaultCheckTimeout = 30 * time.Second
+)
+
+// ParsePolicy validates a readiness policy string
+func ParsePolicy(value string) (Policy, error) {
+	switch policy := Policy(value); policy {
+	case PolicyAll, PolicyAny, PolicyNone:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid readiness policy %q, must be one of: %s, %s, %s", value, PolicyAll, PolicyAny, PolicyNone)
+	}
+}
+
+// CheckFunc verifies that a HardwareManager is usable, typically by calling into the adaptor
+type CheckFunc func(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) error
+
+type result struct {
+	err       error
+	checkedAt time.Time
+}
+
+// Monitor periodically checks the readiness of each HardwareManager in the namespace, caching the results to be
+// served by the readiness probe
+type Monitor struct {
+	client.Client
+	Logger       *slog.Logger
+	Namespace    string
+	Check        CheckFunc
+	Policy       Policy
+	Interval     time.Duration
+	CheckTimeout time.Duration
+
+	lock    sync.RWMutex
+	checked bool
+	results map[string]result
+}
+
+var (
+	_ manager.Runnable               = (*Monitor)(nil)
+	_ manager.LeaderElectionRunnable = (*Monitor)(nil)
+)
+
+// NeedLeaderElection returns false, as every replica must report its own readiness
+func (m *Monitor) NeedLeaderElection() bool {
+	return false
+}
+
+// Start runs the readiness checks until the context is cancelled
+func (m *Monitor) Start(ctx context.Context) error {
+	interval := m.Interval
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.checkAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAll runs the readiness check for each HardwareManager, replacing the cached results
+func (m *Monitor) checkAll(ctx context.Context) {
+	hwmgrs := &pluginv1alpha1.HardwareManagerList{}
+	if err := m.List(ctx, hwmgrs, client.InNamespace(m.Namespace)); err != nil {
+		m.Logger.ErrorContext(ctx, "Unable to list HardwareManagers for readiness check", slog.String("error", err.Error()))
+		return
+	}
+
+	timeout := m.CheckTimeout
+	if timeout == 0 {
+		timeout = DefaultCheckTimeout
+	}
+
+	var wg sync.WaitGroup
+	var resultsLock sync.Mutex
+	results := make(map[string]result, len(hwmgrs.Items))
+
+	for i := range hwmgrs.Items {
+		hwmgr := &hwmgrs.Items[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			err := m.Check(checkCtx, hwmgr)
+			if err != nil {
+				m.Logger.InfoContext(ctx, "HardwareManager readiness check failed",
+					slog.String("hwmgr", hwmgr.Name), slog.String("error", err.Error()))
+			}
+
+			resultsLock.Lock()
+			results[hwmgr.Name] = result{err: err, checkedAt: time.Now()}
+			resultsLock.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	m.lock.Lock()
+	m.results = results
+	m.checked = true
+	m.lock.Unlock()
+}
+
+// CheckHardwareManager returns the cached readiness result for the named HardwareManager
+func (m *Monitor) CheckHardwareManager(name string) (known bool, err error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	r, known := m.results[name]
+	return known, r.err
+}
+
+// Checker is the aggregate readiness check, applying the configured policy to the cached results
+func (m *Monitor) Checker(_ *http.Request) error {
+	if m.Policy == PolicyNone {
+		return nil
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if !m.checked {
+		return errors.New("HardwareManager readiness checks have not completed")
+	}
+
+	// No configured HardwareManagers is a valid state, as there is nothing to be unready
+	if len(m.results) == 0 {
+		return nil
+	}
+
+	var failed []string
+	for name, r := range m.results {
+		if r.err != nil {
+			failed = append(failed, name)
+		}
+	}
+	slices.Sort(failed)
+
+	switch {
+	case len(failed) == 0:
+		return nil
+	case m.Policy == PolicyAny && len(failed) < len(m.results):
+		return nil
+	default:
+		return fmt.Errorf("HardwareManagers not ready: %s", strings.Join(failed, ", "))
+	}
+}
+
+// ServeHTTP serves the readiness of an individual HardwareManager, named by the request path, or the aggregate
+// readiness if no name is given
+func (m *Monitor) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	name := strings.Trim(path.Clean("/"+req.URL.Path), "/")
+	if name == "" {
+		healthz.CheckHandler{Checker: m.Checker}.ServeHTTP(resp, req)
+		return
+	}
+
+	known, err := m.CheckHardwareManager(name)
+	if !known {
+		http.NotFoundHandler().ServeHTTP(resp, req)
+		return
+	}
+
+	healthz.CheckHandler{Checker: func(_ *http.Request) error { return err }}.ServeHTTP(resp, req)
+}
+
+// NewProbeServer creates the health probe server, replacing the one built into the manager so that the readiness
+// of each HardwareManager can be served on a /readyz/hwmgr/<name> sub-path. The aggregate HardwareManager readiness is
+// part of /readyz, subject to the configured policy.
+func NewProbeServer(addr string, monitor *Monitor) *manager.Server {
+	healthzHandler := &healthz.Handler{Checks: map[string]healthz.Checker{"healthz": healthz.Ping}}
+	readyzHandler := &healthz.Handler{Checks: map[string]healthz.Checker{
+		"readyz":  healthz.Ping,
+		CheckName: monitor.Checker,
+	}}
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", http.StripPrefix("/healthz", healthzHandler))
+	mux.Handle("/healthz/", http.StripPrefix("/healthz", healthzHandler))
+	mux.Handle("/readyz", http.StripPrefix("/readyz", readyzHandler))
+	mux.Handle("/readyz/", http.StripPrefix("/readyz", readyzHandler))
+	mux.Handle("/readyz/"+CheckName, http.StripPrefix("/readyz/"+CheckName, monitor))
+	mux.Handle("/readyz/"+CheckName+"/", http.StripPrefix("/readyz/"+CheckName, monitor))
+
+	return &manager.Server{
+		Name: "health probe",
+		Server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 30 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+func TestReadiness(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Readiness Suite")
+}
+
+func newHardwareManager(name string) *pluginv1alpha1.HardwareManager {
+	return &pluginv1alpha1.HardwareManager{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       pluginv1alpha1.HardwareManagerSpec{AdaptorID: "loopback"},
+	}
+}
+
+var _ = Describe("HardwareManager readiness", func() {
+	var (
+		ctx     context.Context
+		monitor *Monitor
+		server  *httptest.Server
+	)
+
+	probe := func(path string) int {
+		resp, err := http.Get(server.URL + path)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(pluginv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		monitor = &Monitor{
+			Client: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(newHardwareManager("ready"), newHardwareManager("unreachable")).Build(),
+			Logger:    slog.New(slog.NewTextHandler(GinkgoWriter, nil)),
+			Namespace: "default",
+			Check: func(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) error {
+				if hwmgr.Name == "unreachable" {
+					return errors.New("connection refused")
+				}
+				return nil
+			},
+			Policy: PolicyAll,
+		}
+
+		server = httptest.NewServer(NewProbeServer("", monitor).Server.Handler)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("is not ready until the checks have run", func() {
+		Expect(monitor.Checker(nil)).To(HaveOccurred())
+		Expect(probe("/readyz")).To(Equal(http.StatusInternalServerError))
+		Expect(probe("/readyz/hwmgr")).To(Equal(http.StatusInternalServerError))
+		Expect(probe("/healthz")).To(Equal(http.StatusOK))
+	})
+
+	It("serves the readiness of each HardwareManager", func() {
+		monitor.checkAll(ctx)
+
+		Expect(probe("/readyz/hwmgr/ready")).To(Equal(http.StatusOK))
+		Expect(probe("/readyz/hwmgr/unreachable")).To(Equal(http.StatusInternalServerError))
+		Expect(probe("/readyz/hwmgr/unknown")).To(Equal(http.StatusNotFound))
+	})
+
+	It("applies the all policy", func() {
+		monitor.checkAll(ctx)
+
+		Expect(monitor.Checker(nil)).To(MatchError(ContainSubstring("unreachable")))
+		Expect(probe("/readyz")).To(Equal(http.StatusInternalServerError))
+		Expect(probe("/readyz/hwmgr")).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("applies the any policy", func() {
+		monitor.Policy = PolicyAny
+		monitor.checkAll(ctx)
+
+		Expect(monitor.Checker(nil)).To(Succeed())
+		Expect(probe("/readyz")).To(Equal(http.StatusOK))
+		Expect(probe("/readyz/hwmgr")).To(Equal(http.StatusOK))
+	})
+
+	It("applies the none policy", func() {
+		monitor.Policy = PolicyNone
+		Expect(probe("/readyz")).To(Equal(http.StatusOK))
+
+		monitor.checkAll(ctx)
+		Expect(probe("/readyz")).To(Equal(http.StatusOK))
+		Expect(probe("/readyz/hwmgr/unreachable")).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("rejects an invalid policy", func() {
+		_, err := ParsePolicy("some")
+		Expect(err).To(HaveOccurred())
+
+		policy, err := ParsePolicy("any")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).To(Equal(PolicyAny))
+	})
+})
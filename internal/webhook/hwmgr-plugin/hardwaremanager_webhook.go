@@ -0,0 +1,377 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrplugin
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/hwmgrclient"
+	grpcadaptor "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/grpc"
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/registry"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+)
+
+const (
+	caBundleKey = "ca-bundle.pem"
+)
+
+//+kubebuilder:webhook:path=/mutate-hwmgr-plugin-oran-openshift-io-v1alpha1-hardwaremanager,mutating=true,failurePolicy=fail,sideEffects=None,groups=hwmgr-plugin.oran.openshift.io,resources=hardwaremanagers,verbs=create;update,versions=v1alpha1,name=mhardwaremanager.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-hwmgr-plugin-oran-openshift-io-v1alpha1-hardwaremanager,mutating=false,failurePolicy=fail,sideEffects=None,groups=hwmgr-plugin.oran.openshift.io,resources=hardwaremanagers,verbs=create;update,versions=v1alpha1,name=vhardwaremanager.kb.io,admissionReviewVersions=v1
+
+// HardwareManagerWebhook defaults and validates HardwareManager CRs, so that a misconfigured hardware manager is
+// rejected on admission rather than being reported by its adaptor after a reconcile
+type HardwareManagerWebhook struct {
+	client.Client
+	Logger    *slog.Logger
+	Namespace string
+}
+
+var (
+	_ admission.CustomDefaulter = &HardwareManagerWebhook{}
+	_ admission.CustomValidator = &HardwareManagerWebhook{}
+)
+
+// SetupWebhookWithManager registers the HardwareManager defaulting and validating webhooks with the manager's
+// webhook server
+func (w *HardwareManagerWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&pluginv1alpha1.HardwareManager{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// Default sets the defaults of the adaptor config data
+func (w *HardwareManagerWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	hwmgr, ok := obj.(*pluginv1alpha1.HardwareManager)
+	if !ok {
+		return fmt.Errorf("expected a HardwareManager object but got %T", obj)
+	}
+
+	if hwmgr.Namespace != w.Namespace {
+		return nil
+	}
+
+	if dellData := hwmgr.Spec.DellData; dellData != nil && (dellData.Tenant == nil || *dellData.Tenant == "") {
+		tenant := hwmgrclient.DefaultTenant
+		dellData.Tenant = &tenant
+	}
+
+	return nil
+}
+
+// ValidateCreate validates a new HardwareManager
+func (w *HardwareManagerWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	hwmgr, ok := obj.(*pluginv1alpha1.HardwareManager)
+	if !ok {
+		return nil, fmt.Errorf("expected a HardwareManager object but got %T", obj)
+	}
+
+	return w.validate(ctx, hwmgr)
+}
+
+// ValidateUpdate validates a HardwareManager spec change
+func (w *HardwareManagerWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldHwMgr, ok := oldObj.(*pluginv1alpha1.HardwareManager)
+	if !ok {
+		return nil, fmt.Errorf("expected a HardwareManager object but got %T", oldObj)
+	}
+	hwmgr, ok := newObj.(*pluginv1alpha1.HardwareManager)
+	if !ok {
+		return nil, fmt.Errorf("expected a HardwareManager object but got %T", newObj)
+	}
+
+	// Metadata and status updates, and updates of a HardwareManager being deleted, are not restricted
+	if !hwmgr.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldHwMgr.Spec, hwmgr.Spec) {
+		return nil, nil
+	}
+
+	return w.validate(ctx, hwmgr)
+}
+
+// ValidateDelete allows all HardwareManager deletions
+func (w *HardwareManagerWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the adaptor config data of the HardwareManager, including the resources it references
+func (w *HardwareManagerWebhook) validate(ctx context.Context, hwmgr *pluginv1alpha1.HardwareManager) (admission.Warnings, error) {
+	if hwmgr.Namespace != w.Namespace {
+		return nil, nil
+	}
+
+	var warnings admission.Warnings
+	errs := validateAdaptorData(hwmgr)
+
+	specPath := field.NewPath("spec")
+	if dellData := hwmgr.Spec.DellData; dellData != nil {
+		path := specPath.Child("dellData")
+
+		if err := validateApiUrl(dellData.ApiUrl); err != nil {
+			errs = append(errs, field.Invalid(path.Child("apiUrl"), dellData.ApiUrl, err.Error()))
+		}
+
+		if dellData.InsecureSkipTLSVerify {
+			warnings = append(warnings, insecureSkipTLSVerifyWarning(path))
+		}
+
+		if dellData.TokenUrl != "" {
+			if err := validateApiUrl(dellData.TokenUrl); err != nil {
+				errs = append(errs, field.Invalid(path.Child("tokenUrl"), dellData.TokenUrl, err.Error()))
+			}
+		}
+
+		if breaker := dellData.CircuitBreaker; breaker != nil && breaker.OpenDuration != nil && breaker.OpenDuration.Duration <= 0 {
+			errs = append(errs, field.Invalid(path.Child("circuitBreaker", "openDuration"), breaker.OpenDuration.Duration.String(),
+				"must be a positive duration"))
+		}
+
+		if notifications := dellData.Notifications; notifications != nil {
+			errs = append(errs, validateNotifications(path.Child("notifications"), notifications)...)
+		}
+
+		secretErrs, err := w.checkSecret(ctx, path.Child("authSecret"), dellData.AuthSecret,
+			hwmgrclient.GetAuthSecretKeys(hwmgrclient.GetGrantType(dellData))...)
+		if err != nil {
+			return warnings, apierrors.NewInternalError(err)
+		}
+		errs = append(errs, secretErrs...)
+
+		cmErrs, err := w.checkCaBundle(ctx, path.Child("caBundleName"), dellData.CaBundleName)
+		if err != nil {
+			return warnings, apierrors.NewInternalError(err)
+		}
+		errs = append(errs, cmErrs...)
+	}
+
+	if redfishData := hwmgr.Spec.RedfishData; redfishData != nil {
+		path := specPath.Child("redfishData")
+
+		if redfishData.InsecureSkipTLSVerify {
+			warnings = append(warnings, insecureSkipTLSVerifyWarning(path))
+		}
+
+		cmErrs, err := w.checkCaBundle(ctx, path.Child("caBundleName"), redfishData.CaBundleName)
+		if err != nil {
+			return warnings, apierrors.NewInternalError(err)
+		}
+		errs = append(errs, cmErrs...)
+	}
+
+	if grpcData := hwmgr.Spec.GrpcData; grpcData != nil {
+		path := specPath.Child("grpcData")
+
+		if grpcData.InsecureSkipTLSVerify {
+			warnings = append(warnings, insecureSkipTLSVerifyWarning(path))
+		}
+
+		cmErrs, err := w.checkCaBundle(ctx, path.Child("caBundleName"), grpcData.CaBundleName)
+		if err != nil {
+			return warnings, apierrors.NewInternalError(err)
+		}
+		errs = append(errs, cmErrs...)
+	}
+
+	cmErrs, err := w.checkMaintenancePolicy(ctx, specPath.Child("maintenancePolicyConfigMap"), hwmgr.Spec.MaintenancePolicyConfigMap)
+	if err != nil {
+		return warnings, apierrors.NewInternalError(err)
+	}
+	errs = append(errs, cmErrs...)
+
+	if len(errs) == 0 {
+		return warnings, nil
+	}
+
+	w.Logger.InfoContext(ctx, "Rejecting HardwareManager",
+		slog.String("hwmgr", hwmgr.Name),
+		slog.String("reason", errs.ToAggregate().Error()))
+
+	return warnings, apierrors.NewInvalid(
+		pluginv1alpha1.GroupVersion.WithKind("HardwareManager").GroupKind(), hwmgr.Name, errs)
+}
+
+// validateAdaptorData checks that the adaptorId is registered, and that the only adaptor config data block set is the
+// one for that adaptor
+func validateAdaptorData(hwmgr *pluginv1alpha1.HardwareManager) field.ErrorList {
+	var errs field.ErrorList
+
+	specPath := field.NewPath("spec")
+	adaptorIdPath := specPath.Child("adaptorId")
+
+	if _, exists := registry.Lookup(hwmgr.Spec.AdaptorID); !exists {
+		supported := make([]string, 0)
+		for _, id := range registry.IDs() {
+			supported = append(supported, string(id))
+		}
+		return append(errs, field.NotSupported(adaptorIdPath, hwmgr.Spec.AdaptorID, supported))
+	}
+
+	blocks := []struct {
+		id   pluginv1alpha1.HardwareManagerAdaptorID
+		name string
+		set  bool
+	}{
+		{pluginv1alpha1.SupportedAdaptors.Loopback, "loopbackData", hwmgr.Spec.LoopbackData != nil},
+		{pluginv1alpha1.SupportedAdaptors.Dell, "dellData", hwmgr.Spec.DellData != nil},
+		{pluginv1alpha1.SupportedAdaptors.Metal3, "metal3Data", hwmgr.Spec.Metal3Data != nil},
+		{pluginv1alpha1.SupportedAdaptors.Redfish, "redfishData", hwmgr.Spec.RedfishData != nil},
+		{pluginv1alpha1.SupportedAdaptors.Inventory, "inventoryData", hwmgr.Spec.InventoryData != nil},
+		{grpcadaptor.AdaptorID, "grpcData", hwmgr.Spec.GrpcData != nil},
+	}
+	for _, block := range blocks {
+		if block.set && block.id != hwmgr.Spec.AdaptorID {
+			errs = append(errs, field.Forbidden(specPath.Child(block.name),
+				fmt.Sprintf("config data for adaptor %s is not allowed with adaptorId %s", block.id, hwmgr.Spec.AdaptorID)))
+		}
+	}
+
+	// The adaptor checks that its own config data is present and valid
+	if err := registry.ValidateConfig(hwmgr); err != nil {
+		errs = append(errs, field.Invalid(adaptorIdPath, hwmgr.Spec.AdaptorID, err.Error()))
+	}
+
+	return errs
+}
+
+// validateApiUrl checks that a hardware manager API or token URL is an absolute http or https URL
+func validateApiUrl(apiUrl string) error {
+	parsed, err := url.Parse(apiUrl)
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return fmt.Errorf("unsupported URL scheme %q, expected https or http", parsed.Scheme)
+	}
+
+	if parsed.Host == "" {
+		return fmt.Errorf("URL has no host")
+	}
+
+	return nil
+}
+
+// validateNotifications checks the push notification configuration of a Dell hardware manager. The notification
+// receiver of the plugin only serves https.
+func validateNotifications(path *field.Path, notifications *pluginv1alpha1.DellNotifications) field.ErrorList {
+	var errs field.ErrorList
+
+	if err := validateApiUrl(notifications.CallbackUrl); err != nil {
+		errs = append(errs, field.Invalid(path.Child("callbackUrl"), notifications.CallbackUrl, err.Error()))
+	} else if !strings.HasPrefix(notifications.CallbackUrl, "https://") {
+		errs = append(errs, field.Invalid(path.Child("callbackUrl"), notifications.CallbackUrl, "must be an https URL"))
+	}
+
+	if interval := notifications.FallbackPollInterval; interval != nil && interval.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("fallbackPollInterval"), interval.Duration.String(),
+			"must be a positive duration"))
+	}
+
+	return errs
+}
+
+func insecureSkipTLSVerifyWarning(path *field.Path) string {
+	return fmt.Sprintf("%s: TLS certificate verification is disabled, which is insecure and not recommended",
+		path.Child("insecureSkipTLSVerify"))
+}
+
+// checkSecret checks that a referenced Secret exists with the required keys
+func (w *HardwareManagerWebhook) checkSecret(
+	ctx context.Context,
+	path *field.Path,
+	name string,
+	keys ...string) (field.ErrorList, error) {
+
+	secret := &corev1.Secret{}
+	if err := w.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: w.Namespace}, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.ErrorList{field.NotFound(path, name)}, nil
+		}
+		return nil, fmt.Errorf("failed to get Secret %s: %w", name, err)
+	}
+
+	var errs field.ErrorList
+	for _, key := range keys {
+		if _, exists := secret.Data[key]; !exists {
+			errs = append(errs, field.Invalid(path, name, fmt.Sprintf("the Secret does not contain a %s key", key)))
+		}
+	}
+
+	return errs, nil
+}
+
+// checkCaBundle checks that a referenced CA bundle ConfigMap exists with the ca-bundle.pem key
+func (w *HardwareManagerWebhook) checkCaBundle(ctx context.Context, path *field.Path, name *string) (field.ErrorList, error) {
+	if name == nil {
+		return nil, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := w.Client.Get(ctx, types.NamespacedName{Name: *name, Namespace: w.Namespace}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.ErrorList{field.NotFound(path, *name)}, nil
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap %s: %w", *name, err)
+	}
+
+	if _, exists := cm.Data[caBundleKey]; !exists {
+		return field.ErrorList{field.Invalid(path, *name,
+			fmt.Sprintf("the ConfigMap does not contain a %s key", caBundleKey))}, nil
+	}
+
+	return nil, nil
+}
+
+// checkMaintenancePolicy checks that a referenced maintenance policy ConfigMap exists with a valid policy
+func (w *HardwareManagerWebhook) checkMaintenancePolicy(ctx context.Context, path *field.Path, name string) (field.ErrorList, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := w.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: w.Namespace}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.ErrorList{field.NotFound(path, name)}, nil
+		}
+		return nil, fmt.Errorf("failed to get ConfigMap %s: %w", name, err)
+	}
+
+	policy, err := utils.ExtractDataFromConfigMap[utils.MaintenancePolicy](cm, utils.MaintenancePolicyKey)
+	if err == nil {
+		_, err = utils.ParseMaintenancePolicy(&policy)
+	}
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, name, err.Error())}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hwmgrplugin
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	// Register the adaptors
+	_ "github.com/openshift-kni/oran-hwmgr-plugin/adaptors"
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/hwmgrclient"
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+)
+
+func TestWebhook(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Webhook Suite")
+}
+
+var _ = Describe("HardwareManagerWebhook", func() {
+	const namespace = "oran-hwmgr-plugin"
+
+	var (
+		ctx     context.Context
+		webhook *HardwareManagerWebhook
+	)
+
+	newDellHwMgr := func() *pluginv1alpha1.HardwareManager {
+		return &pluginv1alpha1.HardwareManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "dell-1", Namespace: namespace},
+			Spec: pluginv1alpha1.HardwareManagerSpec{
+				AdaptorID: pluginv1alpha1.SupportedAdaptors.Dell,
+				DellData: &pluginv1alpha1.DellData{
+					AuthSecret:   "dell-auth",
+					ApiUrl:       "https://hwmgr.example.com:8443",
+					CaBundleName: ptr.To("dell-ca"),
+				},
+			},
+		}
+	}
+
+	expectInvalid := func(err error, substr string) {
+		ExpectWithOffset(1, apierrors.IsInvalid(err)).To(BeTrue(), "unexpected error: %v", err)
+		ExpectWithOffset(1, err.Error()).To(ContainSubstring(substr))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(pluginv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		webhook = &HardwareManagerWebhook{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "dell-auth", Namespace: namespace},
+					Data: map[string][]byte{
+						"client-id": []byte("client"),
+						"username":  []byte("user"),
+						"password":  []byte("pass"),
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "dell-client-credentials", Namespace: namespace},
+					Data: map[string][]byte{
+						"client-id":     []byte("client"),
+						"client-secret": []byte("secret"),
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "incomplete-auth", Namespace: namespace},
+					Data:       map[string][]byte{"username": []byte("user")},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "dell-ca", Namespace: namespace},
+					Data:       map[string]string{"ca-bundle.pem": "cert"},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "maintenance", Namespace: namespace},
+					Data: map[string]string{"maintenancePolicy": `
+timeZone: America/Toronto
+windows:
+- schedule: "0 2 * * 6"
+  duration: 4h
+`},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "bad-maintenance", Namespace: namespace},
+					Data: map[string]string{"maintenancePolicy": `
+windows:
+- schedule: "0 25 * * *"
+  duration: 4h
+`},
+				},
+			).Build(),
+			Logger:    slog.Default(),
+			Namespace: namespace,
+		}
+	})
+
+	It("defaults the Dell tenant", func() {
+		hwmgr := newDellHwMgr()
+		Expect(webhook.Default(ctx, hwmgr)).To(Succeed())
+		Expect(hwmgr.Spec.DellData.Tenant).To(HaveValue(Equal(hwmgrclient.DefaultTenant)))
+
+		hwmgr.Spec.DellData.Tenant = ptr.To("tenant-a")
+		Expect(webhook.Default(ctx, hwmgr)).To(Succeed())
+		Expect(hwmgr.Spec.DellData.Tenant).To(HaveValue(Equal("tenant-a")))
+	})
+
+	It("accepts a valid HardwareManager", func() {
+		warnings, err := webhook.ValidateCreate(ctx, newDellHwMgr())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+
+		_, err = webhook.ValidateCreate(ctx, &pluginv1alpha1.HardwareManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "metal3-1", Namespace: namespace},
+			Spec:       pluginv1alpha1.HardwareManagerSpec{AdaptorID: pluginv1alpha1.SupportedAdaptors.Metal3},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("rejects an unregistered adaptorId", func() {
+		hwmgr := newDellHwMgr()
+		hwmgr.Spec.AdaptorID = "unknown"
+		_, err := webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "spec.adaptorId: Unsupported value")
+	})
+
+	It("requires exactly the config data block of the adaptor", func() {
+		hwmgr := newDellHwMgr()
+		hwmgr.Spec.DellData = nil
+		_, err := webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "missing dellData configuration field")
+
+		hwmgr = newDellHwMgr()
+		hwmgr.Spec.LoopbackData = &pluginv1alpha1.LoopbackData{}
+		_, err = webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "spec.loopbackData: Forbidden")
+	})
+
+	It("rejects an invalid apiUrl", func() {
+		for _, apiUrl := range []string{"hwmgr.example.com", "ftp://hwmgr.example.com", "https://", "https://host:port"} {
+			hwmgr := newDellHwMgr()
+			hwmgr.Spec.DellData.ApiUrl = apiUrl
+			_, err := webhook.ValidateCreate(ctx, hwmgr)
+			expectInvalid(err, "spec.dellData.apiUrl")
+		}
+	})
+
+	It("warns when TLS verification is disabled", func() {
+		hwmgr := newDellHwMgr()
+		hwmgr.Spec.DellData.InsecureSkipTLSVerify = true
+		warnings, err := webhook.ValidateCreate(ctx, hwmgr)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("spec.dellData.insecureSkipTLSVerify")))
+	})
+
+	It("checks the referenced Secret and ConfigMap", func() {
+		hwmgr := newDellHwMgr()
+		hwmgr.Spec.DellData.AuthSecret = "missing-auth"
+		_, err := webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "spec.dellData.authSecret: Not found")
+
+		hwmgr = newDellHwMgr()
+		hwmgr.Spec.DellData.AuthSecret = "incomplete-auth"
+		_, err = webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "does not contain a client-id key")
+		expectInvalid(err, "does not contain a password key")
+
+		hwmgr = newDellHwMgr()
+		hwmgr.Spec.DellData.CaBundleName = ptr.To("missing-ca")
+		_, err = webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "spec.dellData.caBundleName: Not found")
+	})
+
+	It("checks the auth secret keys and token URL for the grant type", func() {
+		hwmgr := newDellHwMgr()
+		hwmgr.Spec.DellData.GrantType = pluginv1alpha1.OAuthGrantTypes.ClientCredentials
+		_, err := webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "does not contain a client-secret key")
+
+		hwmgr.Spec.DellData.AuthSecret = "dell-client-credentials"
+		hwmgr.Spec.DellData.TokenUrl = "https://sso.example.com/realms/oran/protocol/openid-connect/token"
+		_, err = webhook.ValidateCreate(ctx, hwmgr)
+		Expect(err).ToNot(HaveOccurred())
+
+		hwmgr.Spec.DellData.TokenUrl = "/protocol/openid-connect/token"
+		_, err = webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "spec.dellData.tokenUrl")
+	})
+
+	It("checks the circuit breaker open duration", func() {
+		hwmgr := newDellHwMgr()
+		hwmgr.Spec.DellData.CircuitBreaker = &pluginv1alpha1.CircuitBreaker{
+			FailureThreshold: 3,
+			OpenDuration:     &metav1.Duration{Duration: 2 * time.Minute},
+		}
+		_, err := webhook.ValidateCreate(ctx, hwmgr)
+		Expect(err).ToNot(HaveOccurred())
+
+		hwmgr.Spec.DellData.CircuitBreaker.OpenDuration.Duration = -time.Minute
+		_, err = webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "spec.dellData.circuitBreaker.openDuration")
+	})
+
+	It("checks the notification configuration", func() {
+		hwmgr := newDellHwMgr()
+		hwmgr.Spec.DellData.Notifications = &pluginv1alpha1.DellNotifications{
+			CallbackUrl:          "https://hwmgr-plugin-notifications.oran-hwmgr-plugin.svc",
+			FallbackPollInterval: &metav1.Duration{Duration: 10 * time.Minute},
+		}
+		_, err := webhook.ValidateCreate(ctx, hwmgr)
+		Expect(err).ToNot(HaveOccurred())
+
+		hwmgr.Spec.DellData.Notifications.CallbackUrl = "http://hwmgr-plugin-notifications.oran-hwmgr-plugin.svc"
+		_, err = webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "spec.dellData.notifications.callbackUrl")
+
+		hwmgr.Spec.DellData.Notifications.CallbackUrl = "https://hwmgr-plugin-notifications.oran-hwmgr-plugin.svc"
+		hwmgr.Spec.DellData.Notifications.FallbackPollInterval.Duration = 0
+		_, err = webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "spec.dellData.notifications.fallbackPollInterval")
+	})
+
+	It("checks the referenced maintenance policy", func() {
+		hwmgr := newDellHwMgr()
+		hwmgr.Spec.MaintenancePolicyConfigMap = "maintenance"
+		_, err := webhook.ValidateCreate(ctx, hwmgr)
+		Expect(err).ToNot(HaveOccurred())
+
+		hwmgr.Spec.MaintenancePolicyConfigMap = "missing-maintenance"
+		_, err = webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "spec.maintenancePolicyConfigMap: Not found")
+
+		hwmgr.Spec.MaintenancePolicyConfigMap = "bad-maintenance"
+		_, err = webhook.ValidateCreate(ctx, hwmgr)
+		expectInvalid(err, "value out of range 0-23")
+	})
+
+	It("only validates spec changes", func() {
+		oldHwMgr := newDellHwMgr()
+		oldHwMgr.Spec.DellData.AuthSecret = "missing-auth"
+		hwmgr := oldHwMgr.DeepCopy()
+		hwmgr.Annotations = map[string]string{"a": "b"}
+		_, err := webhook.ValidateUpdate(ctx, oldHwMgr, hwmgr)
+		Expect(err).ToNot(HaveOccurred())
+
+		hwmgr.Spec.DellData.ApiUrl = "https://other.example.com"
+		_, err = webhook.ValidateUpdate(ctx, oldHwMgr, hwmgr)
+		expectInvalid(err, "spec.dellData.authSecret: Not found")
+	})
+})
@@ -0,0 +1,222 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package o2imshardwaremanagement
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+//+kubebuilder:webhook:path=/validate-o2ims-hardwaremanagement-oran-openshift-io-v1alpha1-nodepool,mutating=false,failurePolicy=ignore,sideEffects=None,groups=o2ims-hardwaremanagement.oran.openshift.io,resources=nodepools,verbs=create;update,versions=v1alpha1,name=vnodepool.kb.io,admissionReviewVersions=v1
+
+// The NodePool webhook intercepts a CRD owned by another operator, so it fails open, and is scoped to the plugin
+// namespace by the namespaceSelector added in config/default, so that an outage of the plugin does not block NodePool
+// writes. NodePools admitted while the webhook is unavailable are still validated by the adaptors.
+
+// NodePoolValidator validates NodePool requests against the HardwareManagers selected to handle them, so that an
+// invalid request is rejected on admission rather than failing during provisioning
+type NodePoolValidator struct {
+	client.Client
+	Logger    *slog.Logger
+	Namespace string
+}
+
+var _ admission.CustomValidator = &NodePoolValidator{}
+
+// SetupWebhookWithManager registers the NodePool validating webhook with the manager's webhook server
+func (v *NodePoolValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&hwmgmtv1alpha1.NodePool{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate validates a new NodePool
+func (v *NodePoolValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	nodepool, ok := obj.(*hwmgmtv1alpha1.NodePool)
+	if !ok {
+		return nil, fmt.Errorf("expected a NodePool object but got %T", obj)
+	}
+
+	return nil, v.validate(ctx, nil, nodepool)
+}
+
+// ValidateUpdate validates a NodePool spec change
+func (v *NodePoolValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldNodePool, ok := oldObj.(*hwmgmtv1alpha1.NodePool)
+	if !ok {
+		return nil, fmt.Errorf("expected a NodePool object but got %T", oldObj)
+	}
+	nodepool, ok := newObj.(*hwmgmtv1alpha1.NodePool)
+	if !ok {
+		return nil, fmt.Errorf("expected a NodePool object but got %T", newObj)
+	}
+
+	// Metadata and status updates, and updates of a NodePool being deleted, are not restricted
+	if !nodepool.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldNodePool.Spec, nodepool.Spec) {
+		return nil, nil
+	}
+
+	return nil, v.validate(ctx, oldNodePool, nodepool)
+}
+
+// ValidateDelete allows all NodePool deletions
+func (v *NodePoolValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the NodePool spec and, for an update, the spec change against the capabilities of the adaptor
+func (v *NodePoolValidator) validate(ctx context.Context, oldNodePool, nodepool *hwmgmtv1alpha1.NodePool) error {
+	if nodepool.Namespace != v.Namespace {
+		return nil
+	}
+
+	errs := utils.ValidateNodePoolSpec(nodepool)
+
+	hwmgrs := make(map[string]*pluginv1alpha1.HardwareManager)
+	nodeGroupPath := field.NewPath("spec", "nodeGroup")
+	for i, nodegroup := range nodepool.Spec.NodeGroup {
+		hwMgrId := utils.GetNodeGroupHwMgrId(nodepool, nodegroup.NodePoolData.Name)
+		if hwMgrId == "" {
+			// Reported by ValidateNodePoolSpec
+			continue
+		}
+
+		hwmgr, cached := hwmgrs[hwMgrId]
+		if !cached {
+			var hwmgrErr *field.Error
+			var err error
+			hwmgr, hwmgrErr, err = v.getHwMgr(ctx, nodepool, nodegroup.NodePoolData.Name, hwMgrId)
+			if err != nil {
+				return apierrors.NewInternalError(err)
+			}
+			if hwmgrErr != nil {
+				errs = append(errs, hwmgrErr)
+			}
+			hwmgrs[hwMgrId] = hwmgr
+		}
+		if hwmgr == nil {
+			continue
+		}
+
+		if len(hwmgr.Status.ResourcePools) > 0 &&
+			!slices.Contains(hwmgr.Status.ResourcePools[nodepool.Spec.Site], nodegroup.NodePoolData.ResourcePoolId) {
+			errs = append(errs, field.Invalid(nodeGroupPath.Index(i).Child("nodePoolData", "resourcePoolId"),
+				nodegroup.NodePoolData.ResourcePoolId,
+				fmt.Sprintf("resource pool is not available at site %q on HardwareManager %s", nodepool.Spec.Site, hwMgrId)))
+		}
+
+		if oldNodePool != nil && hwmgr.Status.Capabilities != nil {
+			errs = append(errs,
+				checkNodeGroupChange(*hwmgr.Status.Capabilities, oldNodePool, nodegroup, nodeGroupPath.Index(i))...)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	v.Logger.InfoContext(ctx, "Rejecting NodePool",
+		slog.String("nodepool", nodepool.Name),
+		slog.String("reason", errs.ToAggregate().Error()))
+
+	return apierrors.NewInvalid(hwmgmtv1alpha1.GroupVersion.WithKind("NodePool").GroupKind(), nodepool.Name, errs)
+}
+
+// getHwMgr gets the HardwareManager assigned to a nodegroup, returning a field error if it is not found or has not
+// been validated
+func (v *NodePoolValidator) getHwMgr(
+	ctx context.Context,
+	nodepool *hwmgmtv1alpha1.NodePool,
+	groupname, hwMgrId string) (*pluginv1alpha1.HardwareManager, *field.Error, error) {
+
+	path := field.NewPath("spec", "hwMgrId")
+	if hwMgrId != nodepool.Spec.HwMgrId {
+		path = field.NewPath("spec", "extensions").Key(utils.NodeGroupHwMgrIdKeyPrefix + groupname)
+	}
+
+	hwmgr := &pluginv1alpha1.HardwareManager{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: hwMgrId, Namespace: v.Namespace}, hwmgr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, field.NotFound(path, hwMgrId), nil
+		}
+		return nil, nil, fmt.Errorf("failed to get HardwareManager %s: %w", hwMgrId, err)
+	}
+
+	if !utils.IsHardwareManagerValidationCompleted(hwmgr) {
+		return nil, field.Invalid(path, hwMgrId, "HardwareManager has not been validated"), nil
+	}
+
+	return hwmgr, nil, nil
+}
+
+// checkNodeGroupChange rejects a change to a nodegroup of a provisioned NodePool that requires a capability the
+// adaptor does not have
+func checkNodeGroupChange(
+	capabilities pluginv1alpha1.AdaptorCapabilities,
+	oldNodePool *hwmgmtv1alpha1.NodePool,
+	nodegroup hwmgmtv1alpha1.NodeGroup,
+	path *field.Path) field.ErrorList {
+
+	// Changes to a NodePool that is still being provisioned are handled by the initial provisioning
+	provisionedCondition := utils.GetNodePoolProvisionedCondition(oldNodePool)
+	if provisionedCondition == nil || provisionedCondition.Status != metav1.ConditionTrue {
+		return nil
+	}
+
+	// A nodegroup added to a provisioned NodePool is a scale-out from zero
+	oldNodeGroup := hwmgmtv1alpha1.NodeGroup{NodePoolData: nodegroup.NodePoolData}
+	for _, group := range oldNodePool.Spec.NodeGroup {
+		if group.NodePoolData.Name == nodegroup.NodePoolData.Name {
+			oldNodeGroup = group
+			break
+		}
+	}
+
+	var errs field.ErrorList
+
+	if nodegroup.Size > oldNodeGroup.Size && !capabilities.ScaleOut {
+		errs = append(errs, field.Forbidden(path.Child("size"), "scale-out is not supported by the adaptor"))
+	}
+
+	if nodegroup.Size < oldNodeGroup.Size && !capabilities.ScaleIn {
+		errs = append(errs, field.Forbidden(path.Child("size"), "scale-in is not supported by the adaptor"))
+	}
+
+	if nodegroup.NodePoolData.HwProfile != oldNodeGroup.NodePoolData.HwProfile && !capabilities.ProfileUpdate {
+		errs = append(errs, field.Forbidden(path.Child("nodePoolData", "hwProfile"),
+			"hardware profile update is not supported by the adaptor"))
+	}
+
+	return errs
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package o2imshardwaremanagement
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pluginv1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	hwmgmtv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+)
+
+func TestWebhook(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Webhook Suite")
+}
+
+var _ = Describe("NodePoolValidator", func() {
+	const namespace = "oran-hwmgr-plugin"
+
+	var (
+		ctx       context.Context
+		validator *NodePoolValidator
+	)
+
+	newHwMgr := func(name string, validated bool, capabilities *pluginv1alpha1.AdaptorCapabilities) *pluginv1alpha1.HardwareManager {
+		hwmgr := &pluginv1alpha1.HardwareManager{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       pluginv1alpha1.HardwareManagerSpec{AdaptorID: pluginv1alpha1.SupportedAdaptors.Loopback},
+			Status: pluginv1alpha1.HardwareManagerStatus{
+				ResourcePools: pluginv1alpha1.PerSiteResourcePoolList{"site-a": {"pool-1", "pool-2"}},
+				Capabilities:  capabilities,
+			},
+		}
+		if validated {
+			utils.SetStatusCondition(&hwmgr.Status.Conditions,
+				string(pluginv1alpha1.ConditionTypes.Validation),
+				string(pluginv1alpha1.ConditionReasons.Completed),
+				metav1.ConditionTrue, "Validated")
+		}
+		return hwmgr
+	}
+
+	newNodeGroup := func(name string, size int, pool string) hwmgmtv1alpha1.NodeGroup {
+		return hwmgmtv1alpha1.NodeGroup{
+			NodePoolData: hwmgmtv1alpha1.NodePoolData{
+				Name:           name,
+				Role:           "worker",
+				HwProfile:      "profile-a",
+				ResourcePoolId: pool,
+			},
+			Size: size,
+		}
+	}
+
+	newNodePool := func(hwMgrId string, nodegroups ...hwmgmtv1alpha1.NodeGroup) *hwmgmtv1alpha1.NodePool {
+		return &hwmgmtv1alpha1.NodePool{
+			ObjectMeta: metav1.ObjectMeta{Name: "np1", Namespace: namespace},
+			Spec: hwmgmtv1alpha1.NodePoolSpec{
+				HwMgrId:      hwMgrId,
+				LocationSpec: hwmgmtv1alpha1.LocationSpec{Site: "site-a"},
+				NodeGroup:    nodegroups,
+			},
+		}
+	}
+
+	provisioned := func(nodepool *hwmgmtv1alpha1.NodePool) *hwmgmtv1alpha1.NodePool {
+		utils.SetStatusCondition(&nodepool.Status.Conditions,
+			string(hwmgmtv1alpha1.Provisioned), string(hwmgmtv1alpha1.Completed), metav1.ConditionTrue, "Provisioned")
+		return nodepool
+	}
+
+	expectInvalid := func(err error, substr string) {
+		ExpectWithOffset(1, apierrors.IsInvalid(err)).To(BeTrue(), "unexpected error: %v", err)
+		ExpectWithOffset(1, err.Error()).To(ContainSubstring(substr))
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(pluginv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(hwmgmtv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		validator = &NodePoolValidator{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newHwMgr("hwmgr-a", true, &pluginv1alpha1.AdaptorCapabilities{ScaleOut: true}),
+				newHwMgr("hwmgr-pending", false, nil),
+			).Build(),
+			Logger:    slog.Default(),
+			Namespace: namespace,
+		}
+	})
+
+	It("accepts a valid NodePool", func() {
+		_, err := validator.ValidateCreate(ctx, newNodePool("hwmgr-a",
+			newNodeGroup("controller", 3, "pool-1"), newNodeGroup("worker", 2, "pool-2")))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("ignores NodePools in other namespaces", func() {
+		nodepool := newNodePool("")
+		nodepool.Namespace = "other"
+		_, err := validator.ValidateCreate(ctx, nodepool)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("rejects a NodePool without a hwMgrId", func() {
+		_, err := validator.ValidateCreate(ctx, newNodePool("", newNodeGroup("controller", 3, "pool-1")))
+		expectInvalid(err, "spec.hwMgrId: Required value")
+	})
+
+	It("rejects a NodePool with an unknown or unvalidated hardware manager", func() {
+		_, err := validator.ValidateCreate(ctx, newNodePool("hwmgr-missing", newNodeGroup("controller", 3, "pool-1")))
+		expectInvalid(err, "spec.hwMgrId: Not found")
+
+		_, err = validator.ValidateCreate(ctx, newNodePool("hwmgr-pending", newNodeGroup("controller", 3, "pool-1")))
+		expectInvalid(err, "HardwareManager has not been validated")
+	})
+
+	It("checks the hardware manager assigned to a nodegroup by extension", func() {
+		nodepool := newNodePool("hwmgr-a", newNodeGroup("controller", 3, "pool-1"), newNodeGroup("worker", 2, "pool-2"))
+		nodepool.Spec.Extensions = map[string]string{utils.NodeGroupHwMgrIdKeyPrefix + "worker": "hwmgr-pending"}
+		_, err := validator.ValidateCreate(ctx, nodepool)
+		expectInvalid(err, "spec.extensions[hwMgrId.worker]")
+	})
+
+	It("rejects duplicate nodegroup names and non-positive sizes", func() {
+		_, err := validator.ValidateCreate(ctx, newNodePool("hwmgr-a",
+			newNodeGroup("controller", 3, "pool-1"), newNodeGroup("controller", 0, "pool-1")))
+		expectInvalid(err, "spec.nodeGroup[1].nodePoolData.name: Duplicate value")
+		expectInvalid(err, "spec.nodeGroup[1].size: Invalid value")
+	})
+
+	It("rejects a resource pool not available at the site", func() {
+		_, err := validator.ValidateCreate(ctx, newNodePool("hwmgr-a", newNodeGroup("controller", 3, "pool-3")))
+		expectInvalid(err, "spec.nodeGroup[0].nodePoolData.resourcePoolId")
+
+		nodepool := newNodePool("hwmgr-a", newNodeGroup("controller", 3, "pool-1"))
+		nodepool.Spec.Site = "site-b"
+		_, err = validator.ValidateCreate(ctx, nodepool)
+		expectInvalid(err, `not available at site "site-b"`)
+	})
+
+	It("rejects spec changes the adaptor cannot perform on a provisioned NodePool", func() {
+		oldNodePool := provisioned(newNodePool("hwmgr-a", newNodeGroup("controller", 3, "pool-1")))
+
+		scaledOut := newNodePool("hwmgr-a", newNodeGroup("controller", 4, "pool-1"))
+		_, err := validator.ValidateUpdate(ctx, oldNodePool, scaledOut)
+		Expect(err).ToNot(HaveOccurred())
+
+		scaledIn := newNodePool("hwmgr-a", newNodeGroup("controller", 2, "pool-1"))
+		_, err = validator.ValidateUpdate(ctx, oldNodePool, scaledIn)
+		expectInvalid(err, "scale-in is not supported")
+
+		profileUpdate := newNodePool("hwmgr-a", newNodeGroup("controller", 3, "pool-1"))
+		profileUpdate.Spec.NodeGroup[0].NodePoolData.HwProfile = "profile-b"
+		_, err = validator.ValidateUpdate(ctx, oldNodePool, profileUpdate)
+		expectInvalid(err, "hardware profile update is not supported")
+
+		// The same change is allowed before the NodePool is provisioned
+		_, err = validator.ValidateUpdate(ctx, newNodePool("hwmgr-a", newNodeGroup("controller", 3, "pool-1")), scaledIn)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("allows updates that do not change the spec", func() {
+		oldNodePool := newNodePool("", newNodeGroup("controller", 0, "pool-1"))
+		nodepool := oldNodePool.DeepCopy()
+		nodepool.Labels = map[string]string{"a": "b"}
+		_, err := validator.ValidateUpdate(ctx, oldNodePool, nodepool)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -22,12 +22,15 @@ import (
 	"embed"
 	"fmt"
 	"html/template"
+	"path"
 
 	hwmgrpluginoranopenshiftiov1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
 	imsv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -68,7 +71,7 @@ func GetConfigmapFromFile(name string) (*corev1.ConfigMap, error) {
 
 func GetHardwareManagerFromTmpl(url string, tmpl string) (*hwmgrpluginoranopenshiftiov1alpha1.HardwareManager, error) {
 
-	t := template.Must(template.New("dell-hwmgr.tmpl").ParseFS(manifests, tmpl))
+	t := template.Must(template.New(path.Base(tmpl)).ParseFS(manifests, tmpl))
 	var data struct {
 		Url string
 	}
@@ -103,6 +106,20 @@ func GetHardwareManagerFromFile(name string) (*hwmgrpluginoranopenshiftiov1alpha
 	return hardwaremgrObject.(*hwmgrpluginoranopenshiftiov1alpha1.HardwareManager), nil
 }
 
+func GetHardwareNodeFromFile(name string) (*hwmgrpluginoranopenshiftiov1alpha1.HardwareNode, error) {
+	hwnodeBytes, err := manifests.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed with error: (%w)", "readfile", err)
+	}
+
+	hwnodeObject, err := runtime.Decode(cdcs.UniversalDecoder(hwmgrpluginoranopenshiftiov1alpha1.GroupVersion), hwnodeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed with error: (%w)", "decode", err)
+	}
+
+	return hwnodeObject.(*hwmgrpluginoranopenshiftiov1alpha1.HardwareNode), nil
+}
+
 func GetNodePoolFromFile(name string) (*imsv1alpha1.NodePool, error) {
 	nodepoolBytes, err := manifests.ReadFile(name)
 	if err != nil {
@@ -144,3 +161,18 @@ func GetSecretFromFile(name string) (*corev1.Secret, error) {
 
 	return secretObject.(*corev1.Secret), nil
 }
+
+// GetUnstructuredFromFile reads a manifest for a kind not registered with the test scheme, such as a BareMetalHost
+func GetUnstructuredFromFile(name string) (*unstructured.Unstructured, error) {
+	objectBytes, err := manifests.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s failed with error: (%w)", "readfile", err)
+	}
+
+	object := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(objectBytes, &object.Object); err != nil {
+		return nil, fmt.Errorf("%s failed with error: (%w)", "unmarshal", err)
+	}
+
+	return object, nil
+}
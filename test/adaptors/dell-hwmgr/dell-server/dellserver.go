@@ -17,7 +17,14 @@ limitations under the License.
 package dellserver
 
 import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
+	"sync"
+	"time"
 
 	apiserver "github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/dell-hwmgr/dell-server/generated"
 )
@@ -25,85 +32,552 @@ import (
 // These functions will be mocked on a test basis
 var GetTokenFn http.HandlerFunc
 
-// This struct implements the http interface provided by the server infra
-type DellServer struct{}
+// subscription is a resource subscription of a tenant, to whose callback notifications are pushed
+type subscription struct {
+	tenant    string
+	callback  string
+	resources []string
+}
+
+// subscriptionRequest is the body of a subscribe or unsubscribe request, including the callback of a subscription
+type subscriptionRequest struct {
+	Id        string   `json:"Id"`
+	Resources []string `json:"Resources"`
+	Callback  string   `json:"Callback,omitempty"`
+}
+
+// notification is the body of a notification pushed to the callback of a subscription
+type notification struct {
+	SubscriptionId string              `json:"SubscriptionId"`
+	Events         []notificationEvent `json:"Events"`
+}
+
+type notificationEvent struct {
+	Type       string `json:"Type"`
+	JobId      string `json:"JobId,omitempty"`
+	ResourceId string `json:"ResourceId,omitempty"`
+	Status     string `json:"Status,omitempty"`
+}
+
+// job is a resource group deletion job of a tenant, or an update of the resource group membership of a resource
+type job struct {
+	tenant        string
+	resourceGroup string
+	resource      string
+	op            string
+	status        string
+	failReason    string
+}
+
+// resource is a compute resource of a resource pool, selected for the nodegroup matching its role label
+type resource struct {
+	tenant string
+	pool   string
+	role   string
+	group  string
+}
+
+// This struct implements the http interface provided by the server infra. The resource subscriptions, resources,
+// resource groups and jobs are kept in memory, so that tests can emit notifications to the subscribers and drive the
+// jobs.
+type DellServer struct {
+	mu            sync.Mutex
+	subscriptions map[string]*subscription
+
+	// resourceGroups maps the resource group IDs to their tenant
+	resourceGroups map[string]string
+	resources      map[string]*resource
+	jobs           map[string]*job
+	lastJob        int
+
+	// resourceGroupFailure is the status of the response to resource group requests, if set
+	resourceGroupFailure int
+
+	// notifier pushes the notifications to the subscribers, which serve self-signed certificates in tests
+	notifier *http.Client
+}
+
+// NewDellServer creates a mock Dell hardware manager server without any subscriptions or resource groups
+func NewDellServer() *DellServer {
+	return &DellServer{
+		subscriptions:  make(map[string]*subscription),
+		resourceGroups: make(map[string]string),
+		resources:      make(map[string]*resource),
+		jobs:           make(map[string]*job),
+		notifier: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // nolint: gosec
+			},
+		},
+	}
+}
+
+// EmitJobNotification pushes a job status change to the subscribers of the tenant
+func (s *DellServer) EmitJobNotification(tenant, jobId, status string) error {
+	return s.emit(func(sub *subscription) bool { return sub.tenant == tenant },
+		notificationEvent{Type: "Job", JobId: jobId, Status: status})
+}
+
+// EmitResourceNotification pushes a resource state change to the subscribers of the resource
+func (s *DellServer) EmitResourceNotification(resourceId, status string) error {
+	return s.emit(func(sub *subscription) bool { return slices.Contains(sub.resources, resourceId) },
+		notificationEvent{Type: "Resource", ResourceId: resourceId, Status: status})
+}
+
+func (s *DellServer) emit(match func(*subscription) bool, event notificationEvent) error {
+	callbacks := make(map[string]string)
+	s.mu.Lock()
+	for id, sub := range s.subscriptions {
+		if sub.callback != "" && match(sub) {
+			callbacks[id] = sub.callback
+		}
+	}
+	s.mu.Unlock()
+
+	for id, callback := range callbacks {
+		body, err := json.Marshal(notification{SubscriptionId: id, Events: []notificationEvent{event}})
+		if err != nil {
+			return fmt.Errorf("failed to encode notification: %w", err)
+		}
+
+		response, err := s.notifier.Post(callback, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to push notification to %s: %w", callback, err)
+		}
+		response.Body.Close()
+
+		if response.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("notification to %s rejected with status %d", callback, response.StatusCode)
+		}
+	}
+
+	return nil
+}
+
+// AddResourceGroup creates a resource group for the tenant
+func (s *DellServer) AddResourceGroup(tenant, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resourceGroups[id] = tenant
+}
+
+// RemoveResourceGroup deletes a resource group, without a job
+func (s *DellServer) RemoveResourceGroup(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.resourceGroups, id)
+}
+
+// HasResourceGroup checks whether the resource group exists
+func (s *DellServer) HasResourceGroup(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.resourceGroups[id]
+	return exists
+}
+
+// AddResource creates a free resource in a resource pool of the tenant, with the role label of a nodegroup
+func (s *DellServer) AddResource(tenant, pool, id, role string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resources[id] = &resource{tenant: tenant, pool: pool, role: role}
+}
+
+// SetResourceGroup sets the resource group of which a resource is a member, without a job
+func (s *DellServer) SetResourceGroup(id, group string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if res, exists := s.resources[id]; exists {
+		res.group = group
+	}
+}
+
+// GetResourceGroupMembers returns the IDs of the resources that are members of the resource group
+func (s *DellServer) GetResourceGroupMembers(group string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var members []string
+	for id, res := range s.resources {
+		if res.group == group {
+			members = append(members, id)
+		}
+	}
+	return members
+}
+
+// SetJobStatus sets the status of a job. A completed deletion job deletes its resource group, and a completed resource
+// update job adds the resource to, or releases it from, its resource group.
+func (s *DellServer) SetJobStatus(jobId, status, failReason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if j, exists := s.jobs[jobId]; exists {
+		j.status, j.failReason = status, failReason
+		if status == "completed" {
+			switch j.op {
+			case "add":
+				s.resources[j.resource].group = j.resourceGroup
+			case "remove":
+				s.resources[j.resource].group = ""
+			default:
+				delete(s.resourceGroups, j.resourceGroup)
+			}
+		}
+	}
+}
+
+// RemoveJob deletes a job, as the hardware manager may do once the job has completed
+func (s *DellServer) RemoveJob(jobId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, jobId)
+}
 
-func (s DellServer) GetToken(w http.ResponseWriter, r *http.Request) {
+// FailResourceGroupRequests responds to the resource group requests with the status, or handles them if zero
+func (s *DellServer) FailResourceGroupRequests(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resourceGroupFailure = status
+}
+
+// HasSubscription checks whether the subscription exists
+func (s *DellServer) HasSubscription(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.subscriptions[id]
+	return exists
+}
+
+// getResource builds the data of a resource, including the BMC details and interfaces required to allocate a node.
+// The caller must hold the lock.
+func (s *DellServer) getResource(id string, res *resource) apiserver.RhprotoResource {
+	roleKey := "role"
+	ipAddress := "192.0.2.1"
+	password := "bmc-" + id
+	labels := []apiserver.ApiprotoLabel{{Key: &roleKey, Value: &res.role}}
+	groups := []string{}
+	if res.group != "" {
+		groups = append(groups, res.group)
+	}
+
+	return apiserver.RhprotoResource{
+		Id:             &id,
+		Name:           &id,
+		Labels:         &labels,
+		Groups:         &apiserver.ApiprotoGroups{Group: &groups},
+		ResourcePoolId: &res.pool,
+		ResourceAttribute: &apiserver.ApiprotoResourceAttribute{
+			Compute: &apiserver.ApiprotoCompute{
+				Lom: &apiserver.ApiprotoLom{IpAddress: &ipAddress, Password: &password},
+			},
+		},
+		Extensions: &map[string]map[string]interface{}{
+			"O2-nics": {
+				"nads": []map[string]interface{}{{
+					"model": "nic",
+					"ports": []map[string]interface{}{{
+						"mac":    "00:00:5e:00:53:01",
+						"Labels": []map[string]string{{"Key": "name", "Value": "eth0"}},
+					}},
+				}},
+			},
+			"RemoteManagement": {
+				"virtualMediaUrl": "redfish-virtualmedia+https://192.0.2.1/redfish/v1/Systems/" + id,
+			},
+		},
+	}
+}
+
+// getSubscriptionResp builds the response for a subscription. The caller must hold the lock.
+func (s *DellServer) getSubscriptionResp(id string, sub *subscription) apiserver.ApiprotoResourceSubscriptionResp {
+	subscribed := "true"
+	details := []apiserver.ApiprotoResourceDetails{}
+	for _, resource := range sub.resources {
+		details = append(details, apiserver.ApiprotoResourceDetails{Resource: &resource, Subscribed: &subscribed})
+	}
+	return apiserver.ApiprotoResourceSubscriptionResp{Id: &id, Resources: &details}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func (s *DellServer) GetToken(w http.ResponseWriter, r *http.Request) {
 	GetTokenFn(w, r)
 }
 
-func (s DellServer) VerifyRequestStatus(w http.ResponseWriter, r *http.Request, tenant, jobid string) {
-	// To be implemented
+func (s *DellServer) VerifyRequestStatus(w http.ResponseWriter, r *http.Request, tenant, jobid string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, exists := s.jobs[jobid]
+	if !exists || j.tenant != tenant {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+
+	brief := apiserver.RhprotoJobStatusBrief{Id: &jobid, Status: &j.status}
+	if j.failReason != "" {
+		brief.FailReason = &j.failReason
+	}
+	writeJSON(w, http.StatusOK, apiserver.RhprotoJobStatus{Brief: &brief})
 }
 
-func (s DellServer) CreateResourceGroup(w http.ResponseWriter, r *http.Request, tenant string) {
+func (s *DellServer) CreateResourceGroup(w http.ResponseWriter, r *http.Request, tenant string) {
 	// To be implemented
 }
 
-func (s DellServer) DeleteResourceGroup(w http.ResponseWriter, r *http.Request, tenant, resourceGroupId string) {
-	// To be implemented
+func (s *DellServer) DeleteResourceGroup(w http.ResponseWriter, r *http.Request, tenant, resourceGroupId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.resourceGroupFailure != 0 {
+		http.Error(w, "resource group request failed", s.resourceGroupFailure)
+		return
+	}
+
+	if owner, exists := s.resourceGroups[resourceGroupId]; !exists || owner != tenant {
+		http.Error(w, "resource group not found", http.StatusNotFound)
+		return
+	}
+
+	s.lastJob++
+	jobId := fmt.Sprintf("job-%d", s.lastJob)
+	s.jobs[jobId] = &job{tenant: tenant, resourceGroup: resourceGroupId, status: "started"}
+
+	writeJSON(w, http.StatusOK, apiserver.ApiprotoResponse{Jobid: &jobId})
 }
 
-func (s DellServer) GetResourceGroup(w http.ResponseWriter, r *http.Request, tenant, resourceGroupId string) {
-	// To be implemented
+func (s *DellServer) GetResourceGroup(w http.ResponseWriter, r *http.Request, tenant, resourceGroupId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.resourceGroupFailure != 0 {
+		http.Error(w, "resource group request failed", s.resourceGroupFailure)
+		return
+	}
+
+	if owner, exists := s.resourceGroups[resourceGroupId]; !exists || owner != tenant {
+		http.Error(w, "resource group not found", http.StatusNotFound)
+		return
+	}
+
+	// The members of the resource group are selected for the nodegroup matching their role label
+	selectors := make(map[string]apiserver.RhprotoResourceSelectorGetResponse)
+	for id, res := range s.resources {
+		if res.group != resourceGroupId {
+			continue
+		}
+		selector, exists := selectors[res.role]
+		if !exists {
+			selector = apiserver.RhprotoResourceSelectorGetResponse{RpId: &res.pool, Resources: &[]apiserver.RhprotoResource{}}
+		}
+		*selector.Resources = append(*selector.Resources, s.getResource(id, res))
+		selectors[res.role] = selector
+	}
+
+	writeJSON(w, http.StatusOK, apiserver.RhprotoResourceGroupObjectGetResponseBody{
+		Id:                &resourceGroupId,
+		ResourceSelectors: &selectors,
+	})
 }
 
-func (s DellServer) CreateResourcePool(w http.ResponseWriter, r *http.Request, tenant string) {
+func (s *DellServer) CreateResourcePool(w http.ResponseWriter, r *http.Request, tenant string) {
 	// To be implemented
 }
 
-func (s DellServer) DeleteResourcePool(w http.ResponseWriter, r *http.Request, tenant, resourcePoolId string, params apiserver.DeleteResourcePoolParams) {
+func (s *DellServer) DeleteResourcePool(w http.ResponseWriter, r *http.Request, tenant, resourcePoolId string, params apiserver.DeleteResourcePoolParams) {
 	// To be implemented
 }
 
-func (s DellServer) UpdateResource(w http.ResponseWriter, r *http.Request, tenant string) {
-	// To be implemented
+func (s *DellServer) UpdateResource(w http.ResponseWriter, r *http.Request, tenant string) {
+	var request apiserver.UpdateResourceJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.ResourceName == nil || request.Resource == nil {
+		http.Error(w, "invalid update resource request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := *request.ResourceName
+	if res, exists := s.resources[id]; !exists || res.tenant != tenant {
+		http.Error(w, "resource not found", http.StatusNotFound)
+		return
+	}
+
+	// Only updates of the resource group membership are supported
+	var jobId string
+	for _, update := range *request.Resource {
+		if update.Path == nil || *update.Path != "/Resource/Groups/Group" || update.Op == nil ||
+			update.Value == nil || len(*update.Value) != 1 || (*update.Op != "add" && *update.Op != "remove") {
+			http.Error(w, "unsupported resource update", http.StatusBadRequest)
+			return
+		}
+
+		group, _ := (*update.Value)[0]["group"].(string)
+		if _, exists := s.resourceGroups[group]; !exists {
+			http.Error(w, "resource group not found", http.StatusNotFound)
+			return
+		}
+
+		s.lastJob++
+		jobId = fmt.Sprintf("job-%d", s.lastJob)
+		s.jobs[jobId] = &job{tenant: tenant, resourceGroup: group, resource: id, op: *update.Op, status: "started"}
+	}
+
+	writeJSON(w, http.StatusOK, apiserver.ApiprotoUpdateResourceResp{Response: &apiserver.ApiprotoResponse{Jobid: &jobId}})
 }
 
-func (s DellServer) CreateResource(w http.ResponseWriter, r *http.Request, tenant string) {
+func (s *DellServer) CreateResource(w http.ResponseWriter, r *http.Request, tenant string) {
 	// To be implemented
 }
 
-func (s DellServer) GetResourceDeployments(w http.ResponseWriter, r *http.Request, tenant, id string) {
+func (s *DellServer) GetResourceDeployments(w http.ResponseWriter, r *http.Request, tenant, id string) {
 	// To be implemented
 }
 
-func (s DellServer) DeleteResource(w http.ResponseWriter, r *http.Request, tenant, resourceId string, params apiserver.DeleteResourceParams) {
+func (s *DellServer) DeleteResource(w http.ResponseWriter, r *http.Request, tenant, resourceId string, params apiserver.DeleteResourceParams) {
 	// To be implemented
 }
 
-func (s DellServer) SubscribeResources(w http.ResponseWriter, r *http.Request, tenant string) {
-	// To be implemented
+func (s *DellServer) SubscribeResources(w http.ResponseWriter, r *http.Request, tenant string) {
+	var request subscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Id == "" {
+		http.Error(w, "invalid subscription request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, exists := s.subscriptions[request.Id]
+	if !exists {
+		sub = &subscription{tenant: tenant}
+		s.subscriptions[request.Id] = sub
+	}
+	if request.Callback != "" {
+		sub.callback = request.Callback
+	}
+	for _, resource := range request.Resources {
+		if !slices.Contains(sub.resources, resource) {
+			sub.resources = append(sub.resources, resource)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, apiserver.ApiprotoSubscribeResourcesResp{Tenant: &tenant})
 }
 
-func (s DellServer) UnsubscribeResources(w http.ResponseWriter, r *http.Request, tenant string) {
-	// To be implemented
+func (s *DellServer) UnsubscribeResources(w http.ResponseWriter, r *http.Request, tenant string) {
+	var request subscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Id == "" {
+		http.Error(w, "invalid unsubscribe request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, exists := s.subscriptions[request.Id]
+	if !exists || sub.tenant != tenant {
+		http.Error(w, "subscription not found", http.StatusNotFound)
+		return
+	}
+
+	if len(request.Resources) == 0 {
+		delete(s.subscriptions, request.Id)
+	} else {
+		sub.resources = slices.DeleteFunc(sub.resources, func(resource string) bool {
+			return slices.Contains(request.Resources, resource)
+		})
+	}
+
+	writeJSON(w, http.StatusOK, apiserver.ApiprotoUnsubscribeResourcesResp{Tenant: &tenant})
 }
 
-func (s DellServer) GetResourcePools(w http.ResponseWriter, r *http.Request, tenant string) {
+func (s *DellServer) GetResourcePools(w http.ResponseWriter, r *http.Request, tenant string) {
 	// To be implemented
 }
 
-func (s DellServer) GetResourcePool(w http.ResponseWriter, r *http.Request, tenant, id string) {
-	// To be implemented
+func (s *DellServer) GetResourcePool(w http.ResponseWriter, r *http.Request, tenant, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resources := []apiserver.ApiprotoResource{}
+	for resourceId, res := range s.resources {
+		if res.tenant != tenant || res.pool != id {
+			continue
+		}
+		data := s.getResource(resourceId, res)
+		resources = append(resources, apiserver.ApiprotoResource{
+			Id:             data.Id,
+			Name:           data.Name,
+			Labels:         data.Labels,
+			Groups:         data.Groups,
+			ResourcePoolId: data.ResourcePoolId,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, apiserver.ApiprotoResourcePoolResp{
+		ResourcePool: &apiserver.ApiprotoResourcePool{Id: &id, Resources: &resources},
+	})
 }
 
-func (s DellServer) GetResources(w http.ResponseWriter, r *http.Request, tenant string) {
+func (s *DellServer) GetResources(w http.ResponseWriter, r *http.Request, tenant string) {
 	// To be implemented
 }
 
-func (s DellServer) GetResource(w http.ResponseWriter, r *http.Request, tenant, id string) {
+func (s *DellServer) GetResource(w http.ResponseWriter, r *http.Request, tenant, id string) {
 	// To be implemented
 }
 
-func (s DellServer) GetResourceSubscriptions(w http.ResponseWriter, r *http.Request, tenant string) {
-	// To be implemented
+func (s *DellServer) GetResourceSubscriptions(w http.ResponseWriter, r *http.Request, tenant string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscriptions := []apiserver.ApiprotoResourceSubscriptionResp{}
+	for id, sub := range s.subscriptions {
+		if sub.tenant == tenant {
+			subscriptions = append(subscriptions, s.getSubscriptionResp(id, sub))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, apiserver.ApiprotoGetResourceSubscriptionsResp{ResourceSubscription: &subscriptions})
 }
 
-func (s DellServer) GetResourceSubscription(w http.ResponseWriter, r *http.Request, tenant, id string) {
-	// To be implemented
+func (s *DellServer) GetResourceSubscription(w http.ResponseWriter, r *http.Request, tenant, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, exists := s.subscriptions[id]
+	if !exists || sub.tenant != tenant {
+		http.Error(w, "subscription not found", http.StatusNotFound)
+		return
+	}
+
+	subscriptions := []apiserver.ApiprotoResourceSubscriptionResp{s.getSubscriptionResp(id, sub)}
+	writeJSON(w, http.StatusOK, apiserver.ApiprotoGetResourceSubscriptionResp{
+		ResourceSubscription: &subscriptions,
+		Tenant:               &tenant,
+	})
 }
 
-func (s DellServer) GetSecrets(w http.ResponseWriter, r *http.Request, tenant, secretKey string) {
-	// To be implemented
+func (s *DellServer) GetSecrets(w http.ResponseWriter, r *http.Request, tenant, secretKey string) {
+	// Each secret holds the same BMC credentials
+	value := `{"bmc_username":"admin","bmc_password":"password"}`
+	writeJSON(w, http.StatusOK, apiserver.RhprotoGetSecretsResponseBody{
+		Secret: &apiserver.RhprotoSecret{Key: &secretKey, Tenant: &tenant, Value: &value},
+	})
 }
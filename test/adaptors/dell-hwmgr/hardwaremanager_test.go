@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+//nolint:all
+package dellhwmgr
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/controller"
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/hwmgrclient"
+	hwmgrpluginoranopenshiftiov1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/assets"
+	dellserver "github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/dell-hwmgr/dell-server"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("delete a subscribed hardware manager", func() {
+	When("the HardwareManager holds the subscription finalizer", func() {
+
+		var (
+			hwmgr  *hwmgrpluginoranopenshiftiov1alpha1.HardwareManager
+			secret *corev1.Secret
+		)
+
+		ctx := context.Background()
+
+		BeforeEach(func() {
+			var err error
+
+			dellserver.GetTokenFn = GetTokenSuccessfulMock
+
+			// create the Dell secret
+			secret, err = assets.GetSecretFromFile("manifests/dell-secret.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			// create the HardwareManager cr instance, as subscribed by the controller
+			url := fmt.Sprintf("http://127.0.0.1:%d", fp)
+			hwmgr, err = assets.GetHardwareManagerFromTmpl(url, "manifests/dell-hwmgr.tmpl")
+			Expect(err).NotTo(HaveOccurred())
+			hwmgr.Finalizers = []string{controller.SubscriptionFinalizer}
+			Expect(k8sClient.Create(ctx, hwmgr)).To(Succeed())
+
+			hmc, err := hwmgrclient.NewClientWithResponses(ctx, logger, k8sClient, hwmgr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hmc.SubscribeResources(ctx, hwmgrclient.GetSubscriptionId(hwmgr), "https://127.0.0.1/notifications",
+				[]string{"resource-1"})).To(Succeed())
+		})
+
+		AfterEach(func() {
+			// delete the secret
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+		})
+
+		It("must remove the subscription before releasing the finalizer", func() {
+			By("reconciling the deleted HardwareManager")
+
+			Expect(dellServer.HasSubscription(hwmgrclient.GetSubscriptionId(hwmgr))).To(BeTrue())
+			Expect(k8sClient.Delete(ctx, hwmgr)).To(Succeed())
+
+			reconciler := &controller.HardwareManagerReconciler{
+				Client:    k8sClient,
+				Logger:    logger,
+				AdaptorID: hwmgrpluginoranopenshiftiov1alpha1.SupportedAdaptors.Dell,
+			}
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(hwmgr)})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(dellServer.HasSubscription(hwmgrclient.GetSubscriptionId(hwmgr))).To(BeFalse())
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(hwmgr), &hwmgrpluginoranopenshiftiov1alpha1.HardwareManager{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,217 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+//nolint:all
+package dellhwmgr
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/dell-hwmgr/hwmgrclient"
+	hwmgrpluginoranopenshiftiov1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/utils"
+	"github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/assets"
+	dellserver "github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/dell-hwmgr/dell-server"
+	imsv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("release a dell nodepool", func() {
+	When("deleting a nodepool with a resource group", func() {
+
+		var (
+			hwmgr  *hwmgrpluginoranopenshiftiov1alpha1.HardwareManager
+			secret *corev1.Secret
+			np     *imsv1alpha1.NodePool
+			rgId   string
+		)
+
+		ctx := context.Background()
+
+		// reconcile processes the deletion of the nodepool
+		reconcile := func() error {
+			_, err := nodepoolReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(np)})
+			return err
+		}
+
+		// getNodePool returns the current nodepool, or nil once it has been deleted
+		getNodePool := func() *imsv1alpha1.NodePool {
+			current := &imsv1alpha1.NodePool{}
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(np), current)
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			Expect(err).NotTo(HaveOccurred())
+			return current
+		}
+
+		// getDeprovisioning returns the Deprovisioning condition of the nodepool
+		getDeprovisioning := func() *metav1.Condition {
+			current := getNodePool()
+			Expect(current).NotTo(BeNil())
+			return meta.FindStatusCondition(current.Status.Conditions, string(utils.NodePoolDeprovisioning))
+		}
+
+		BeforeEach(func() {
+			var err error
+
+			dellserver.GetTokenFn = GetTokenSuccessfulMock
+
+			// create the Dell secret
+			secret, err = assets.GetSecretFromFile("manifests/dell-secret.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			// create the HardwareManager cr instance
+			url := fmt.Sprintf("http://127.0.0.1:%d", fp)
+			hwmgr, err = assets.GetHardwareManagerFromTmpl(url, "manifests/dell-hwmgr.tmpl")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, hwmgr)).To(Succeed())
+
+			// create the Nodepool cr instance, as provisioned by the plugin
+			np, err = assets.GetNodePoolFromFile("manifests/np1-np.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			np.Spec.HwMgrId = hwmgr.Name
+			np.Finalizers = []string{utils.NodepoolFinalizer}
+			Expect(k8sClient.Create(ctx, np)).To(Succeed())
+
+			// create the resource group of the nodepool in the hardware manager
+			rgId = hwmgrclient.ResourceGroupIdFromNodePool(np)
+			dellServer.AddResourceGroup(hwmgrclient.DefaultTenant, rgId)
+
+			Expect(k8sClient.Delete(ctx, np)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			dellServer.FailResourceGroupRequests(0)
+			dellServer.RemoveResourceGroup(rgId)
+
+			// release the Nodepool cr instance, if not already released by the test
+			if current := getNodePool(); current != nil {
+				controllerutil.RemoveFinalizer(current, utils.NodepoolFinalizer)
+				Expect(k8sClient.Update(ctx, current)).To(Succeed())
+			}
+
+			// delete the HardwareManager cr instance and the secret
+			Expect(k8sClient.Delete(ctx, hwmgr)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+		})
+
+		It("must track the deletion job through the deleteJobId annotation", func() {
+			By("issuing the resource group deletion")
+
+			Expect(reconcile()).To(Succeed())
+			current := getNodePool()
+			Expect(current).NotTo(BeNil())
+			jobId := utils.GetDeleteJobId(current)
+			Expect(jobId).NotTo(BeEmpty())
+			Expect(getDeprovisioning().Reason).To(Equal(string(imsv1alpha1.InProgress)))
+
+			By("waiting for the job without reissuing the deletion")
+
+			Expect(reconcile()).To(Succeed())
+			Expect(utils.GetDeleteJobId(getNodePool())).To(Equal(jobId))
+			Expect(dellServer.HasResourceGroup(rgId)).To(BeTrue())
+
+			By("removing the finalizer once the job has completed")
+
+			dellServer.SetJobStatus(jobId, "completed", "")
+			Expect(reconcile()).To(Succeed())
+			Expect(getNodePool()).To(BeNil())
+			Expect(dellServer.HasResourceGroup(rgId)).To(BeFalse())
+		})
+
+		It("must clear a failed deletion job and reissue the deletion", func() {
+			By("failing the deletion job")
+
+			Expect(reconcile()).To(Succeed())
+			jobId := utils.GetDeleteJobId(getNodePool())
+			Expect(jobId).NotTo(BeEmpty())
+
+			dellServer.SetJobStatus(jobId, "failed", "resource group busy")
+			Expect(reconcile()).To(MatchError(ContainSubstring("resource group busy")))
+
+			current := getNodePool()
+			Expect(current).NotTo(BeNil())
+			Expect(current.Finalizers).To(ContainElement(utils.NodepoolFinalizer))
+			Expect(utils.GetDeleteJobId(current)).To(BeEmpty())
+			Expect(getDeprovisioning().Reason).To(Equal(string(imsv1alpha1.Failed)))
+
+			By("reissuing the deletion on the next attempt")
+
+			Expect(reconcile()).To(Succeed())
+			reissuedJobId := utils.GetDeleteJobId(getNodePool())
+			Expect(reissuedJobId).NotTo(BeEmpty())
+			Expect(reissuedJobId).NotTo(Equal(jobId))
+			Expect(getDeprovisioning().Reason).To(Equal(string(imsv1alpha1.InProgress)))
+		})
+
+		It("must fall back to the resource group when the deletion job is gone", func() {
+			By("removing a job whose resource group still exists")
+
+			Expect(reconcile()).To(Succeed())
+			jobId := utils.GetDeleteJobId(getNodePool())
+			Expect(jobId).NotTo(BeEmpty())
+
+			dellServer.RemoveJob(jobId)
+			Expect(reconcile()).To(Succeed())
+			Expect(getNodePool()).NotTo(BeNil())
+
+			By("removing the finalizer once the resource group is gone")
+
+			dellServer.RemoveResourceGroup(rgId)
+			Expect(reconcile()).To(Succeed())
+			Expect(getNodePool()).To(BeNil())
+		})
+
+		It("must keep the finalizer when the release fails", func() {
+			By("rejecting the resource group requests")
+
+			dellServer.FailResourceGroupRequests(http.StatusConflict)
+			Expect(reconcile()).NotTo(Succeed())
+
+			current := getNodePool()
+			Expect(current).NotTo(BeNil())
+			Expect(current.Finalizers).To(ContainElement(utils.NodepoolFinalizer))
+			Expect(utils.GetDeleteJobId(current)).To(BeEmpty())
+			Expect(getDeprovisioning().Reason).To(Equal(string(imsv1alpha1.Failed)))
+			Expect(dellServer.HasResourceGroup(rgId)).To(BeTrue())
+		})
+
+		It("must remove the finalizer of a force deleted nodepool when the release fails", func() {
+			By("annotating the nodepool for force deletion")
+
+			current := getNodePool()
+			Expect(current).NotTo(BeNil())
+			current.Annotations = map[string]string{utils.ForceDeleteAnnotation: "true"}
+			Expect(k8sClient.Update(ctx, current)).To(Succeed())
+
+			dellServer.FailResourceGroupRequests(http.StatusConflict)
+			Expect(reconcile()).To(Succeed())
+			Expect(getNodePool()).To(BeNil())
+			Expect(dellServer.HasResourceGroup(rgId)).To(BeTrue())
+		})
+	})
+})
@@ -25,6 +25,8 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors"
+	o2imshardwaremanagement "github.com/openshift-kni/oran-hwmgr-plugin/internal/controller/o2ims-hardwaremanagement"
 	"github.com/openshift-kni/oran-hwmgr-plugin/test/utils"
 
 	"github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/crds"
@@ -40,6 +42,7 @@ import (
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	apiserver "github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/dell-hwmgr/dell-server/generated"
 
@@ -61,6 +64,12 @@ var (
 	// a http test server infra
 	server *http.Server
 
+	// the mock Dell hardware manager behind the test server
+	dellServer *dellserver.DellServer
+
+	// the NodePool reconciler, driven directly by the tests rather than by a running manager
+	nodepoolReconciler *o2imshardwaremanagement.NodePoolReconciler
+
 	// store external CRDs
 	tmpDir string
 
@@ -129,8 +138,30 @@ var _ = BeforeSuite(func() {
 	err = assets.InitCodecs()
 	Expect(err).NotTo(HaveOccurred())
 
-	ds := dellserver.DellServer{}
-	h := apiserver.HandlerWithOptions(ds, apiserver.GorillaServerOptions{})
+	// build the adaptor controller, whose adaptors are set up with a manager that is not started
+	mgr, err := manager.New(cfg, manager.Options{Scheme: scheme.Scheme})
+	Expect(err).NotTo(HaveOccurred())
+
+	hwmgrAdaptor := &adaptors.HwMgrAdaptorController{
+		Client:    k8sClient,
+		Scheme:    scheme.Scheme,
+		Logger:    logger,
+		Namespace: "default",
+	}
+	err = hwmgrAdaptor.SetupWithManager(mgr)
+	Expect(err).NotTo(HaveOccurred())
+
+	// build the nodepool reconciler
+	nodepoolReconciler = &o2imshardwaremanagement.NodePoolReconciler{
+		Client:       k8sClient,
+		Scheme:       scheme.Scheme,
+		Logger:       logger,
+		Namespace:    "default",
+		HwMgrAdaptor: hwmgrAdaptor,
+	}
+
+	dellServer = dellserver.NewDellServer()
+	h := apiserver.HandlerWithOptions(dellServer, apiserver.GorillaServerOptions{})
 
 	fp, err = freeport.GetFreePort()
 	Expect(err).NotTo(HaveOccurred(), "failed to find a free port to listen on")
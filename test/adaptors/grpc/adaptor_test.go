@@ -0,0 +1,131 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpcadaptor
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	hwmgrpluginoranopenshiftiov1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/assets"
+	imsv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("reconcile via the grpc adaptor", func() {
+	When("reconciling a node pool", func() {
+
+		var (
+			cm    *corev1.ConfigMap
+			hwmgr *hwmgrpluginoranopenshiftiov1alpha1.HardwareManager
+			np    *imsv1alpha1.NodePool
+		)
+
+		ctx := context.Background()
+
+		BeforeEach(func() {
+			// create the loopback cm instance, used by the adaptor server
+			var err error
+			cm, err = assets.GetConfigmapFromFile("manifests/loopback-nodelist-cm.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, cm)).To(Succeed())
+
+			// create the HardwareManager cr instance
+			endpoint := fmt.Sprintf("127.0.0.1:%d", fp)
+			hwmgr, err = assets.GetHardwareManagerFromTmpl(endpoint, "manifests/grpc-hwmgr.tmpl")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, hwmgr)).To(Succeed())
+
+			// create the Nodepool cr instance
+			np, err = assets.GetNodePoolFromFile("manifests/np1-np.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			np.Spec.HwMgrId = hwmgr.Name
+			Expect(k8sClient.Create(ctx, np)).To(Succeed())
+		})
+
+		AfterEach(func() {
+
+			// delete the Nodepool cr instance
+			Expect(k8sClient.Delete(ctx, np)).To(Succeed())
+
+			// delete the loopback cm instance
+			Expect(k8sClient.Delete(ctx, cm)).To(Succeed())
+
+			// delete the HardwareManager cr instance
+			Expect(k8sClient.Delete(ctx, hwmgr)).To(Succeed())
+
+		})
+		It("must validate the connection to the adaptor server", func() {
+			By("performing the protocol handshake")
+
+			timeout, interval := 30, 1
+			Eventually(func() bool {
+				current := &hwmgrpluginoranopenshiftiov1alpha1.HardwareManager{}
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: hwmgr.Name, Namespace: hwmgr.Namespace}, current); err != nil {
+					return false
+				}
+				return meta.IsStatusConditionTrue(current.Status.Conditions,
+					string(hwmgrpluginoranopenshiftiov1alpha1.ConditionTypes.Validation))
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("must create ims nodes", func() {
+			By("reconciling according to the provided manifests")
+
+			// check node has been created by the adaptor server
+			node := &imsv1alpha1.Node{}
+			timeout, interval := 30, 1
+			Eventually(nodeExists("dummy-sp-64g-0", node), timeout, interval).Should(BeTrue())
+
+			// check node must use the hardware profile specified by the nodepool cr instance
+			got := node.Spec.HwProfile
+			wanted := np.Spec.NodeGroup[0].NodePoolData.HwProfile
+			Expect(got).To(Equal(wanted))
+
+			// check the node is owned by the NodePool, which requires the type info to be passed to the adaptor server
+			current := &imsv1alpha1.NodePool{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: np.Name, Namespace: np.Namespace}, current)).To(Succeed())
+			Expect(node.OwnerReferences).To(ContainElement(SatisfyAll(
+				HaveField("Kind", "NodePool"),
+				HaveField("UID", current.UID),
+			)))
+		})
+
+	})
+})
+
+func nodeExists(nodeId string, node *imsv1alpha1.Node) func() bool {
+	return func() bool {
+		nodelist := &imsv1alpha1.NodeList{}
+		if err := k8sClient.List(ctx, nodelist); err != nil {
+			return false
+		}
+
+		for _, nodeIter := range nodelist.Items {
+			if nodeIter.Spec.HwMgrNodeId == nodeId {
+				*node = nodeIter
+				return true
+			}
+		}
+
+		return false
+	}
+}
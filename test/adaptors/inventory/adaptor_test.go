@@ -0,0 +1,164 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	hwmgrpluginoranopenshiftiov1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/assets"
+	imsv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("inventory adaptor", func() {
+	When("reconciling a node pool", func() {
+
+		var (
+			secret *corev1.Secret
+			hwnode *hwmgrpluginoranopenshiftiov1alpha1.HardwareNode
+			hwmgr  *hwmgrpluginoranopenshiftiov1alpha1.HardwareManager
+			np     *imsv1alpha1.NodePool
+		)
+
+		ctx := context.Background()
+
+		BeforeEach(func() {
+			// create the BMC credentials secret of the HardwareNode
+			var err error
+			secret, err = assets.GetSecretFromFile("manifests/inventory-bmc-secret.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			// create a free HardwareNode
+			hwnode, err = assets.GetHardwareNodeFromFile("manifests/inventory-hardwarenode.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, hwnode)).To(Succeed())
+
+			// create the HardwareManager cr instance
+			hwmgr, err = assets.GetHardwareManagerFromFile("manifests/inventory-hwmgr.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, hwmgr)).To(Succeed())
+
+			// create the Nodepool cr instance
+			np, err = assets.GetNodePoolFromFile("manifests/np1-np.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			np.Spec.HwMgrId = hwmgr.Name
+			Expect(k8sClient.Create(ctx, np)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			// delete the Nodepool cr instance, if not already deleted by the test, and wait for the allocation to be
+			// released
+			_ = k8sClient.Delete(ctx, np)
+			timeout, interval := 30, 1
+			Eventually(func() bool {
+				current := &imsv1alpha1.NodePool{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: np.Name, Namespace: np.Namespace}, current)
+				return errors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+
+			// delete the HardwareNode and its secret
+			Expect(k8sClient.Delete(ctx, hwnode)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+
+			// delete the HardwareManager cr instance
+			Expect(k8sClient.Delete(ctx, hwmgr)).To(Succeed())
+		})
+
+		It("must create ims nodes from the HardwareNode", func() {
+			By("allocating the HardwareNode in the resource pool of the nodegroup")
+
+			node := &imsv1alpha1.Node{}
+			timeout, interval := 30, 1
+			Eventually(nodeExists("default/hwnode-0", node), timeout, interval).Should(BeTrue())
+
+			// check node must use the hardware profile specified by the nodepool cr instance
+			Expect(node.Spec.HwProfile).To(Equal(np.Spec.NodeGroup[0].NodePoolData.HwProfile))
+			Expect(node.Spec.GroupName).To(Equal(np.Spec.NodeGroup[0].NodePoolData.Name))
+
+			// check the BMC details and interfaces are taken from the HardwareNode
+			Eventually(func() bool {
+				current := &imsv1alpha1.Node{}
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: node.Name, Namespace: node.Namespace}, current); err != nil {
+					return false
+				}
+				*node = *current
+				return node.Status.BMC != nil
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(node.Status.BMC.Address).To(Equal(hwnode.Spec.BMC.Address))
+			Expect(node.Status.Interfaces).To(ConsistOf(
+				&imsv1alpha1.Interface{Name: "eno1", Label: "bootable-interface", MACAddress: "c6:b6:13:a0:02:01"},
+				&imsv1alpha1.Interface{Name: "eno2", MACAddress: "c6:b6:13:a0:02:02"},
+			))
+
+			// check the BMC secret is a copy of the HardwareNode credentials
+			bmcSecret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: node.Status.BMC.CredentialsName, Namespace: "default"}, bmcSecret)).To(Succeed())
+			Expect(bmcSecret.Data).To(Equal(secret.Data))
+
+			// check the allocation is recorded in the HardwareNode status
+			Expect(hwnodeAllocation()).To(Equal(&hwmgrpluginoranopenshiftiov1alpha1.HardwareNodeAllocation{
+				HwMgrId:   hwmgr.Name,
+				NodePool:  np.Name,
+				NodeGroup: np.Spec.NodeGroup[0].NodePoolData.Name,
+				NodeName:  node.Name,
+			}))
+		})
+
+		It("must release the HardwareNode on nodepool deletion", func() {
+			By("removing the allocation from the HardwareNode")
+
+			timeout, interval := 30, 1
+			Eventually(hwnodeAllocation, timeout, interval).ShouldNot(BeNil())
+
+			Expect(k8sClient.Delete(ctx, np)).To(Succeed())
+			Eventually(hwnodeAllocation, timeout, interval).Should(BeNil())
+		})
+	})
+})
+
+func hwnodeAllocation() *hwmgrpluginoranopenshiftiov1alpha1.HardwareNodeAllocation {
+	current := &hwmgrpluginoranopenshiftiov1alpha1.HardwareNode{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: "hwnode-0", Namespace: "default"}, current); err != nil {
+		return nil
+	}
+	return current.Status.Allocation
+}
+
+func nodeExists(nodeId string, node *imsv1alpha1.Node) func() bool {
+	return func() bool {
+		nodelist := &imsv1alpha1.NodeList{}
+		if err := k8sClient.List(ctx, nodelist); err != nil {
+			return false
+		}
+
+		for _, nodeIter := range nodelist.Items {
+			if nodeIter.Spec.HwMgrNodeId == nodeId {
+				*node = nodeIter
+				return true
+			}
+		}
+
+		return false
+	}
+}
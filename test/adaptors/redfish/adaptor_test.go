@@ -0,0 +1,232 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+//nolint:all
+package redfish
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	redfishadaptor "github.com/openshift-kni/oran-hwmgr-plugin/adaptors/redfish"
+	hwmgrpluginoranopenshiftiov1alpha1 "github.com/openshift-kni/oran-hwmgr-plugin/api/hwmgr-plugin/v1alpha1"
+	"github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/assets"
+	redfishserver "github.com/openshift-kni/oran-hwmgr-plugin/test/adaptors/redfish/redfish-server"
+	imsv1alpha1 "github.com/openshift-kni/oran-o2ims/api/hardwaremanagement/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("redfish adaptor", func() {
+	When("reconciling a node pool", func() {
+
+		var (
+			secret   *corev1.Secret
+			profiles *corev1.ConfigMap
+			hwmgr    *hwmgrpluginoranopenshiftiov1alpha1.HardwareManager
+			np       *imsv1alpha1.NodePool
+		)
+
+		ctx := context.Background()
+
+		BeforeEach(func() {
+			// reset the mock Redfish service with a single system
+			mock := redfishserver.NewRedfishServer("admin", "notreal")
+			mock.AddSystem(redfishserver.System{
+				Id:          "System.1",
+				Model:       "PowerEdge R760",
+				BiosVersion: "2.1.5",
+				NICs: []redfishserver.NIC{
+					{Id: "NIC.Integrated.1-1", MACAddress: "c6:b6:13:a0:02:01"},
+					{Id: "NIC.Integrated.1-2", MACAddress: "c6:b6:13:a0:02:02"},
+				},
+				Attributes: map[string]interface{}{
+					"ProcTurboMode": "Disabled",
+					"LogicalProc":   "Enabled",
+				},
+			})
+			mock.AddFirmware(redfishserver.Firmware{Id: "BIOS", Name: "BIOS", Version: "2.1.5"})
+			mock.AddFirmware(redfishserver.Firmware{Id: "iDRAC", Name: "iDRAC", Version: "7.00.00"})
+			redfish.Store(mock)
+
+			// create the BMC credentials secret
+			var err error
+			secret, err = assets.GetSecretFromFile("manifests/redfish-secret.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			// create the hardware profiles configmap
+			profiles, err = assets.GetConfigmapFromFile("manifests/redfish-profiles-cm.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, profiles)).To(Succeed())
+
+			// create the HardwareManager cr instance
+			hwmgr, err = assets.GetHardwareManagerFromTmpl(fmt.Sprintf("http://127.0.0.1:%d", fp), "manifests/redfish-hwmgr.tmpl")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(ctx, hwmgr)).To(Succeed())
+
+			// create the Nodepool cr instance
+			np, err = assets.GetNodePoolFromFile("manifests/np1-np.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			np.Spec.HwMgrId = hwmgr.Name
+			Expect(k8sClient.Create(ctx, np)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			// delete the Nodepool cr instance, if not already deleted by the test, and wait for the release
+			_ = k8sClient.Delete(ctx, np)
+			timeout, interval := 30, 1
+			Eventually(func() bool {
+				current := &imsv1alpha1.NodePool{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: np.Name, Namespace: np.Namespace}, current)
+				return errors.IsNotFound(err)
+			}, timeout, interval).Should(BeTrue())
+
+			// delete the HardwareManager cr instance, along with its secret and configmaps
+			Expect(k8sClient.Delete(ctx, hwmgr)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, profiles)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+			_ = k8sClient.Delete(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "redfish-1-redfish-allocations", Namespace: "default"},
+			})
+		})
+
+		It("must create ims nodes from the discovered systems", func() {
+			By("allocating the system of the BMC in the nodegroup resource pool")
+
+			node := &imsv1alpha1.Node{}
+			timeout, interval := 30, 1
+			Eventually(nodeExists("bmc-0/System.1", node), timeout, interval).Should(BeTrue())
+
+			// check node must use the hardware profile specified by the nodepool cr instance
+			Expect(node.Spec.HwProfile).To(Equal(np.Spec.NodeGroup[0].NodePoolData.HwProfile))
+			Expect(node.Spec.GroupName).To(Equal(np.Spec.NodeGroup[0].NodePoolData.Name))
+
+			// check the firmware discovered via Redfish is recorded
+			Expect(node.Annotations).To(HaveKeyWithValue(redfishadaptor.BiosVersionAnnotation, "2.1.5"))
+			Expect(node.Annotations).To(HaveKeyWithValue(redfishadaptor.FirmwareAnnotation, "BIOS=2.1.5,iDRAC=7.00.00"))
+
+			// check the BMC details and NIC inventory are taken from the system
+			Eventually(func() bool {
+				current := &imsv1alpha1.Node{}
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: node.Name, Namespace: node.Namespace}, current); err != nil {
+					return false
+				}
+				*node = *current
+				return node.Status.BMC != nil
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(node.Status.BMC.Address).To(Equal(fmt.Sprintf("redfish+http://127.0.0.1:%d/redfish/v1/Systems/System.1", fp)))
+			Expect(node.Status.Interfaces).To(ConsistOf(
+				&imsv1alpha1.Interface{Name: "NIC.Integrated.1-1", Label: "bootable-interface", MACAddress: "c6:b6:13:a0:02:01"},
+				&imsv1alpha1.Interface{Name: "NIC.Integrated.1-2", MACAddress: "c6:b6:13:a0:02:02"},
+			))
+
+			// check the BMC secret holds the BMC credentials
+			bmcSecret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: node.Status.BMC.CredentialsName, Namespace: "default"}, bmcSecret)).To(Succeed())
+			Expect(bmcSecret.Data).To(HaveKeyWithValue("username", []byte("admin")))
+			Expect(bmcSecret.Data).To(HaveKeyWithValue("password", []byte("notreal")))
+
+			// check the BIOS attributes of the hardware profile are pending for the next boot
+			Expect(redfish.Load().GetPendingBiosAttributes("System.1")).To(Equal(map[string]interface{}{
+				"ProcTurboMode": "Enabled",
+				"LogicalProc":   "Disabled",
+			}))
+		})
+
+		It("must apply a hardware profile change as BIOS settings", func() {
+			By("updating the nodegroup hardware profile")
+
+			node := &imsv1alpha1.Node{}
+			timeout, interval := 30, 1
+			Eventually(nodeExists("bmc-0/System.1", node), timeout, interval).Should(BeTrue())
+			Eventually(nodePoolProvisioned, timeout, interval).Should(BeTrue())
+
+			current := &imsv1alpha1.NodePool{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: np.Name, Namespace: np.Namespace}, current)).To(Succeed())
+			current.Spec.NodeGroup[0].NodePoolData.HwProfile = "profile-spr-dual-processor-128G"
+			Expect(k8sClient.Update(ctx, current)).To(Succeed())
+
+			// the update restarts the system, applying the pending settings
+			Eventually(func() string {
+				updated := &imsv1alpha1.Node{}
+				if err := k8sClient.Get(ctx, types.NamespacedName{Name: node.Name, Namespace: node.Namespace}, updated); err != nil {
+					return ""
+				}
+				return updated.Status.HwProfile
+			}, timeout, interval).Should(Equal("profile-spr-dual-processor-128G"))
+
+			Expect(redfish.Load().GetBiosAttribute("System.1", "ProcTurboMode")).To(Equal("Disabled"))
+			Expect(redfish.Load().GetBiosAttribute("System.1", "LogicalProc")).To(Equal("Enabled"))
+		})
+
+		It("must release the system on nodepool deletion", func() {
+			By("removing the allocation of the system")
+
+			node := &imsv1alpha1.Node{}
+			timeout, interval := 30, 1
+			Eventually(nodeExists("bmc-0/System.1", node), timeout, interval).Should(BeTrue())
+			Eventually(allocations, timeout, interval).Should(ContainSubstring("bmc-0/System.1"))
+
+			Expect(k8sClient.Delete(ctx, np)).To(Succeed())
+			Eventually(allocations, timeout, interval).ShouldNot(ContainSubstring("bmc-0/System.1"))
+		})
+	})
+})
+
+func allocations() string {
+	cm := &corev1.ConfigMap{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: "redfish-1-redfish-allocations", Namespace: "default"}, cm); err != nil {
+		return ""
+	}
+	return cm.Data["allocations"]
+}
+
+func nodePoolProvisioned() bool {
+	current := &imsv1alpha1.NodePool{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: "np1", Namespace: "default"}, current); err != nil {
+		return false
+	}
+	for _, condition := range current.Status.Conditions {
+		if condition.Type == string(imsv1alpha1.Provisioned) {
+			return condition.Status == metav1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func nodeExists(nodeId string, node *imsv1alpha1.Node) func() bool {
+	return func() bool {
+		nodelist := &imsv1alpha1.NodeList{}
+		if err := k8sClient.List(ctx, nodelist); err != nil {
+			return false
+		}
+
+		for _, nodeIter := range nodelist.Items {
+			if nodeIter.Spec.HwMgrNodeId == nodeId {
+				*node = nodeIter
+				return true
+			}
+		}
+
+		return false
+	}
+}
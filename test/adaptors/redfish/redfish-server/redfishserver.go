@@ -0,0 +1,418 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redfishserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/openshift-kni/oran-hwmgr-plugin/adaptors/redfish/redfishclient"
+)
+
+// NIC is a network interface of a mock system
+type NIC struct {
+	Id         string
+	MACAddress string
+}
+
+// System is a mock Redfish ComputerSystem
+type System struct {
+	Id          string
+	Model       string
+	BiosVersion string
+	PowerState  string
+	NICs        []NIC
+
+	// Attributes are the current BIOS attributes of the system
+	Attributes map[string]interface{}
+
+	// Pending are the BIOS attributes written to Bios/Settings, which are applied on the next reset
+	Pending map[string]interface{}
+}
+
+// Firmware is a mock firmware inventory component
+type Firmware struct {
+	Id      string
+	Name    string
+	Version string
+}
+
+// RedfishServer is an in-memory Redfish service, providing the subset of the Redfish API used by the redfish adaptor
+type RedfishServer struct {
+	username string
+	password string
+
+	mutex    sync.Mutex
+	systems  map[string]*System
+	firmware []Firmware
+}
+
+// NewRedfishServer creates a mock Redfish service that accepts the specified basic auth credentials
+func NewRedfishServer(username, password string) *RedfishServer {
+	return &RedfishServer{
+		username: username,
+		password: password,
+		systems:  make(map[string]*System),
+	}
+}
+
+// AddSystem adds a system to the mock service. Systems are powered on unless a PowerState is set.
+func (s *RedfishServer) AddSystem(system System) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if system.PowerState == "" {
+		system.PowerState = redfishclient.PowerStateOn
+	}
+	if system.Attributes == nil {
+		system.Attributes = make(map[string]interface{})
+	}
+	s.systems[system.Id] = &system
+}
+
+// AddFirmware adds a component to the firmware inventory of the mock service
+func (s *RedfishServer) AddFirmware(firmware Firmware) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.firmware = append(s.firmware, firmware)
+}
+
+// GetBiosAttribute returns the current value of a BIOS attribute of a system
+func (s *RedfishServer) GetBiosAttribute(systemId, name string) (interface{}, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	system, exists := s.systems[systemId]
+	if !exists {
+		return nil, false
+	}
+	value, exists := system.Attributes[name]
+	return value, exists
+}
+
+// GetPendingBiosAttributes returns the BIOS attributes waiting to be applied on the next reset of a system
+func (s *RedfishServer) GetPendingBiosAttributes(systemId string) map[string]interface{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	system, exists := s.systems[systemId]
+	if !exists {
+		return nil
+	}
+	return maps.Clone(system.Pending)
+}
+
+// Handler returns the HTTP handler serving the Redfish API
+func (s *RedfishServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /redfish/v1", s.getServiceRoot)
+	mux.HandleFunc("GET /redfish/v1/Systems", s.getSystems)
+	mux.HandleFunc("GET /redfish/v1/Systems/{id}", s.getSystem)
+	mux.HandleFunc("GET /redfish/v1/Systems/{id}/EthernetInterfaces", s.getEthernetInterfaces)
+	mux.HandleFunc("GET /redfish/v1/Systems/{id}/EthernetInterfaces/{nic}", s.getEthernetInterface)
+	mux.HandleFunc("GET /redfish/v1/Systems/{id}/Bios", s.getBios)
+	mux.HandleFunc("GET /redfish/v1/Systems/{id}/Bios/Settings", s.getBiosSettings)
+	mux.HandleFunc("PATCH /redfish/v1/Systems/{id}/Bios/Settings", s.patchBiosSettings)
+	mux.HandleFunc("POST /redfish/v1/Systems/{id}/Actions/ComputerSystem.Reset", s.resetSystem)
+	mux.HandleFunc("GET /redfish/v1/UpdateService/FirmwareInventory", s.getFirmwareInventory)
+	mux.HandleFunc("GET /redfish/v1/UpdateService/FirmwareInventory/{fw}", s.getFirmware)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != s.username || password != s.password {
+			writeError(w, http.StatusUnauthorized, "invalid credentials")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]string{"message": message},
+	})
+}
+
+func systemPath(id string) string {
+	return "/redfish/v1/Systems/" + id
+}
+
+// lookupSystem finds the system referenced by the request path. The caller must hold the mutex.
+func (s *RedfishServer) lookupSystem(w http.ResponseWriter, r *http.Request) *System {
+	system, exists := s.systems[r.PathValue("id")]
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("system %s not found", r.PathValue("id")))
+		return nil
+	}
+	return system
+}
+
+func (s *RedfishServer) getServiceRoot(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, redfishclient.ServiceRoot{
+		ODataID:        redfishclient.ServiceRootPath,
+		Id:             "RootService",
+		Name:           "Mock Redfish Service",
+		RedfishVersion: "1.15.0",
+		Systems:        &redfishclient.Link{ODataID: "/redfish/v1/Systems"},
+		UpdateService:  &redfishclient.Link{ODataID: "/redfish/v1/UpdateService"},
+	})
+}
+
+func (s *RedfishServer) getSystems(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	collection := redfishclient.Collection{
+		ODataID: "/redfish/v1/Systems",
+		Name:    "Computer System Collection",
+		Members: []redfishclient.Link{},
+	}
+	ids := make([]string, 0, len(s.systems))
+	for id := range s.systems {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		collection.Members = append(collection.Members, redfishclient.Link{ODataID: systemPath(id)})
+	}
+	collection.Count = len(collection.Members)
+
+	writeJSON(w, http.StatusOK, collection)
+}
+
+func (s *RedfishServer) getSystem(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	system := s.lookupSystem(w, r)
+	if system == nil {
+		return
+	}
+
+	path := systemPath(system.Id)
+	writeJSON(w, http.StatusOK, redfishclient.System{
+		ODataID:            path,
+		Id:                 system.Id,
+		Name:               "System " + system.Id,
+		Manufacturer:       "Mock",
+		Model:              system.Model,
+		SerialNumber:       "SN-" + system.Id,
+		BiosVersion:        system.BiosVersion,
+		PowerState:         system.PowerState,
+		Bios:               &redfishclient.Link{ODataID: path + "/Bios"},
+		EthernetInterfaces: &redfishclient.Link{ODataID: path + "/EthernetInterfaces"},
+		Actions: &redfishclient.SystemActions{
+			Reset: &redfishclient.ResetAction{Target: path + "/Actions/ComputerSystem.Reset"},
+		},
+	})
+}
+
+func (s *RedfishServer) getEthernetInterfaces(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	system := s.lookupSystem(w, r)
+	if system == nil {
+		return
+	}
+
+	path := systemPath(system.Id) + "/EthernetInterfaces"
+	collection := redfishclient.Collection{
+		ODataID: path,
+		Name:    "Ethernet Interface Collection",
+		Members: []redfishclient.Link{},
+	}
+	for _, nic := range system.NICs {
+		collection.Members = append(collection.Members, redfishclient.Link{ODataID: path + "/" + nic.Id})
+	}
+	collection.Count = len(collection.Members)
+
+	writeJSON(w, http.StatusOK, collection)
+}
+
+func (s *RedfishServer) getEthernetInterface(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	system := s.lookupSystem(w, r)
+	if system == nil {
+		return
+	}
+
+	for _, nic := range system.NICs {
+		if nic.Id == r.PathValue("nic") {
+			writeJSON(w, http.StatusOK, redfishclient.EthernetInterface{
+				ODataID:    systemPath(system.Id) + "/EthernetInterfaces/" + nic.Id,
+				Id:         nic.Id,
+				Name:       nic.Id,
+				MACAddress: nic.MACAddress,
+			})
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("interface %s not found", r.PathValue("nic")))
+}
+
+func (s *RedfishServer) getBios(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	system := s.lookupSystem(w, r)
+	if system == nil {
+		return
+	}
+
+	path := systemPath(system.Id) + "/Bios"
+	writeJSON(w, http.StatusOK, redfishclient.Bios{
+		ODataID:    path,
+		Id:         "BIOS",
+		Attributes: system.Attributes,
+		Settings: &redfishclient.SettingsObject{
+			SettingsObject: redfishclient.Link{ODataID: path + "/Settings"},
+		},
+	})
+}
+
+func (s *RedfishServer) getBiosSettings(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	system := s.lookupSystem(w, r)
+	if system == nil {
+		return
+	}
+
+	attributes := system.Pending
+	if attributes == nil {
+		attributes = make(map[string]interface{})
+	}
+	writeJSON(w, http.StatusOK, redfishclient.Bios{
+		ODataID:    systemPath(system.Id) + "/Bios/Settings",
+		Id:         "Settings",
+		Attributes: attributes,
+	})
+}
+
+func (s *RedfishServer) patchBiosSettings(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	system := s.lookupSystem(w, r)
+	if system == nil {
+		return
+	}
+
+	settings := redfishclient.BiosSettings{}
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	for name := range settings.Attributes {
+		if _, exists := system.Attributes[name]; !exists {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown BIOS attribute %s", name))
+			return
+		}
+	}
+
+	if system.Pending == nil {
+		system.Pending = make(map[string]interface{})
+	}
+	maps.Copy(system.Pending, settings.Attributes)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *RedfishServer) resetSystem(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	system := s.lookupSystem(w, r)
+	if system == nil {
+		return
+	}
+
+	request := redfishclient.ResetRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	switch request.ResetType {
+	case "ForceOff", "GracefulShutdown":
+		system.PowerState = redfishclient.PowerStateOff
+	case "On", "ForceOn", redfishclient.ResetTypeGracefulRestart, redfishclient.ResetTypeForceRestart:
+		// Pending BIOS settings are applied as the system boots
+		maps.Copy(system.Attributes, system.Pending)
+		system.Pending = nil
+		system.PowerState = redfishclient.PowerStateOn
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported reset type %s", request.ResetType))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *RedfishServer) getFirmwareInventory(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	path := "/redfish/v1/UpdateService/FirmwareInventory"
+	collection := redfishclient.Collection{
+		ODataID: path,
+		Name:    "Firmware Inventory Collection",
+		Members: []redfishclient.Link{},
+	}
+	for _, firmware := range s.firmware {
+		collection.Members = append(collection.Members, redfishclient.Link{ODataID: path + "/" + firmware.Id})
+	}
+	collection.Count = len(collection.Members)
+
+	writeJSON(w, http.StatusOK, collection)
+}
+
+func (s *RedfishServer) getFirmware(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, firmware := range s.firmware {
+		if firmware.Id == r.PathValue("fw") {
+			writeJSON(w, http.StatusOK, redfishclient.SoftwareInventory{
+				ODataID: "/redfish/v1/UpdateService/FirmwareInventory/" + firmware.Id,
+				Id:      firmware.Id,
+				Name:    firmware.Name,
+				Version: firmware.Version,
+			})
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("firmware %s not found", r.PathValue("fw")))
+}